@@ -0,0 +1,210 @@
+package canonicalized
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/* ------------------------- */
+/*      Alpine versions      */
+/* ------------------------- */
+
+// AlpineSuffix is one _suffix of an apk version, as in the _rc1 of
+// 1.2.3_rc1-r2. Number is empty when the suffix has none, as in _rc.
+type AlpineSuffix struct {
+	Name   string `json:"name"`
+	Number string `json:"number,omitempty"`
+}
+
+// AlpineVersion is an apk package version:
+// numbers[letter]{_suffix[number]}[~hash][-rN].
+type AlpineVersion struct {
+	Original string         `json:"original"`
+	Numbers  []string       `json:"numbers"`
+	Letter   string         `json:"letter,omitempty"`
+	Suffixes []AlpineSuffix `json:"suffixes,omitempty"`
+	Hash     string         `json:"hash,omitempty"`
+	// Revision is the N of -rN; empty when the version has none, which
+	// orders like -r0.
+	Revision string `json:"revision,omitempty"`
+}
+
+// alpineSuffixRank orders suffixes around a version without one (0):
+// pre-release suffixes sort before it, post-release suffixes after.
+var alpineSuffixRank = map[string]int{
+	"alpha": -4,
+	"beta":  -3,
+	"pre":   -2,
+	"rc":    -1,
+	"cvs":   1,
+	"svn":   2,
+	"git":   3,
+	"hg":    4,
+	"p":     5,
+}
+
+// AlpineSuffixes lists the suffixes apk accepts, lowest first.
+var AlpineSuffixes = []string{"alpha", "beta", "pre", "rc", "cvs", "svn", "git", "hg", "p"}
+
+var reAlpineVersion = regexp.MustCompile(`^(\d+(?:\.\d+)*)([a-z])?((?:_[a-z]+\d*)*)(?:~([0-9a-f]+))?(?:-r(\d+))?$`)
+
+var reAlpineSuffix = regexp.MustCompile(`_([a-z]+)(\d*)`)
+
+// ParseAlpineVersion parses an apk version such as 1.2.3_rc1-r2.
+func ParseAlpineVersion(s string) (AlpineVersion, error) {
+	v := AlpineVersion{Original: s}
+	m := reAlpineVersion.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return v, fmt.Errorf("invalid alpine version %q", s)
+	}
+	v.Numbers = strings.Split(m[1], ".")
+	v.Letter, v.Hash, v.Revision = m[2], m[4], m[5]
+	for _, sm := range reAlpineSuffix.FindAllStringSubmatch(m[3], -1) {
+		if _, ok := alpineSuffixRank[sm[1]]; !ok {
+			return v, fmt.Errorf("invalid alpine version %q: unknown suffix _%s", s, sm[1])
+		}
+		v.Suffixes = append(v.Suffixes, AlpineSuffix{Name: sm[1], Number: sm[2]})
+	}
+	return v, nil
+}
+
+// String returns the version as apk writes it.
+func (v AlpineVersion) String() string {
+	var b strings.Builder
+	b.WriteString(strings.Join(v.Numbers, "."))
+	b.WriteString(v.Letter)
+	for _, sf := range v.Suffixes {
+		b.WriteString("_" + sf.Name + sf.Number)
+	}
+	if v.Hash != "" {
+		b.WriteString("~" + v.Hash)
+	}
+	if v.Revision != "" {
+		b.WriteString("-r" + v.Revision)
+	}
+	return b.String()
+}
+
+// Compare orders v and other as apk does: by numbers, the first compared
+// numerically and the rest as decimal fractions when either has a leading
+// zero (1.01 < 1.1); then the letter, where none sorts first; then the
+// suffixes, _alpha < _beta < _pre < _rc < none < _cvs < _svn < _git < _hg
+// < _p; then the -rN revision. The ~hash is not ordered.
+func (v AlpineVersion) Compare(other AlpineVersion) int {
+	for i := 0; i < len(v.Numbers) || i < len(other.Numbers); i++ {
+		switch {
+		case i >= len(v.Numbers):
+			return -1
+		case i >= len(other.Numbers):
+			return 1
+		}
+		if c := alpineCmpNumber(v.Numbers[i], other.Numbers[i], i == 0); c != 0 {
+			return c
+		}
+	}
+	if c := strings.Compare(v.Letter, other.Letter); c != 0 {
+		return c
+	}
+	for i := 0; i < len(v.Suffixes) || i < len(other.Suffixes); i++ {
+		var a, b AlpineSuffix
+		if i < len(v.Suffixes) {
+			a = v.Suffixes[i]
+		}
+		if i < len(other.Suffixes) {
+			b = other.Suffixes[i]
+		}
+		if c := sign(alpineSuffixRank[a.Name] - alpineSuffixRank[b.Name]); c != 0 {
+			return c
+		}
+		if c := alpineCmpNumber(a.Number, b.Number, true); c != 0 {
+			return c
+		}
+	}
+	return alpineCmpNumber(v.Revision, other.Revision, true)
+}
+
+// alpineCmpNumber compares two digit strings, "" counting as 0. Unless
+// first, a leading zero makes both compare as decimal fractions.
+func alpineCmpNumber(a, b string, first bool) int {
+	if !first && (strings.HasPrefix(a, "0") || strings.HasPrefix(b, "0")) {
+		return strings.Compare(strings.TrimRight(a, "0"), strings.TrimRight(b, "0"))
+	}
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+// IsPrerelease reports whether v has an _alpha, _beta, _pre or _rc suffix,
+// which sorts it before the release it leads up to.
+func (v AlpineVersion) IsPrerelease() bool {
+	for _, sf := range v.Suffixes {
+		if alpineSuffixRank[sf.Name] < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareAlpine parses and compares two apk versions. ok is false when
+// either does not parse; the strings are then compared as they are.
+func CompareAlpine(a, b string) (int, bool) {
+	va, erra := ParseAlpineVersion(a)
+	vb, errb := ParseAlpineVersion(b)
+	if erra != nil || errb != nil {
+		return strings.Compare(a, b), false
+	}
+	return va.Compare(vb), true
+}
+
+// FuzzyBounds returns the half-open range of the versions apk's ~ operator
+// matches for v: those that start with v, so ~1.2 matches 1.2_rc1, 1.2,
+// 1.2.9 and 1.2a, but not 1.20. ok is false for a version with a revision,
+// which ~ only matches exactly.
+func (v AlpineVersion) FuzzyBounds() (lo, hi string, ok bool) {
+	if v.Revision != "" || v.Hash != "" {
+		return "", "", false
+	}
+	lo = v.String() + "_alpha"
+
+	up := v
+	up.Suffixes = append([]AlpineSuffix(nil), v.Suffixes...)
+	if n := len(up.Suffixes); n > 0 {
+		last := &up.Suffixes[n-1]
+		switch {
+		case last.Number != "":
+			// ~1.2_rc1 -> <1.2_rc2_alpha
+			last.Number = strconv.FormatInt(alpineAtoi(last.Number)+1, 10)
+			return lo, up.String() + "_alpha", true
+		case last.Name == "rc":
+			// ~1.2_rc -> <1.2
+			up.Suffixes = up.Suffixes[:n-1]
+			return lo, up.String(), true
+		case last.Name != "p":
+			// ~1.2_beta -> <1.2_pre_alpha
+			last.Name = AlpineSuffixes[slices.Index(AlpineSuffixes, last.Name)+1]
+			return lo, up.String() + "_alpha", true
+		}
+		up.Suffixes = up.Suffixes[:n-1]
+	}
+	switch {
+	case up.Letter != "" && up.Letter != "z":
+		up.Letter = string(up.Letter[0] + 1)
+	default:
+		up.Letter = ""
+		up.Numbers = append([]string(nil), up.Numbers...)
+		last := len(up.Numbers) - 1
+		up.Numbers[last] = strconv.FormatInt(alpineAtoi(up.Numbers[last])+1, 10)
+	}
+	up.Suffixes = nil
+	return lo, up.String() + "_alpha", true
+}
+
+func alpineAtoi(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
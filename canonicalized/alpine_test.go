@@ -0,0 +1,103 @@
+package canonicalized
+
+import (
+	"testing"
+)
+
+// ─── ParseAlpineVersion ───────────────────────────────────────────────────────
+
+func TestParseAlpineVersion(t *testing.T) {
+	for _, in := range []string{"1.2.3", "1.2.3_rc1-r2", "1.2.3_p1-r0", "1.2a", "2.0_alpha_p3", "1.0_git20230101~ab12cd-r1", "1.0.2u-r0"} {
+		v, err := ParseAlpineVersion(in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", in, err)
+			continue
+		}
+		if got := v.String(); got != in {
+			t.Errorf("%q: String got %q", in, got)
+		}
+	}
+	v, _ := ParseAlpineVersion("1.2.3_rc1-r2")
+	if v.Revision != "2" || len(v.Suffixes) != 1 || v.Suffixes[0] != (AlpineSuffix{Name: "rc", Number: "1"}) {
+		t.Errorf("1.2.3_rc1-r2: got %+v", v)
+	}
+}
+
+func TestParseAlpineVersion_Invalid(t *testing.T) {
+	for _, in := range []string{"", "v1.2", "1.2-3", "1.2_foo1", "1.2ab", "1.2-r", "1..2"} {
+		if _, err := ParseAlpineVersion(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+// ─── CompareAlpine ────────────────────────────────────────────────────────────
+
+func TestCompareAlpine(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3_alpha", "1.2.3_beta", -1},
+		{"1.2.3_beta2", "1.2.3_pre", -1},
+		{"1.2.3_pre1", "1.2.3_rc", -1},
+		{"1.2.3_rc1", "1.2.3", -1},
+		{"1.2.3_rc1-r2", "1.2.3_rc2-r0", -1},
+		{"1.2.3", "1.2.3_cvs", -1},
+		{"1.2.3_cvs", "1.2.3_svn", -1},
+		{"1.2.3_svn", "1.2.3_git", -1},
+		{"1.2.3_git", "1.2.3_hg", -1},
+		{"1.2.3_hg", "1.2.3_p1", -1},
+		{"1.2.3_p1-r0", "1.2.3_p1-r1", -1},
+		{"1.2.3-r9", "1.2.3-r10", -1},
+		{"1.2.3", "1.2.3-r0", 0},
+		{"1.2.3-r2", "1.2.3_p1-r0", -1},
+		{"1.2.3", "1.2.3a", -1},
+		{"1.2.3a", "1.2.3b", -1},
+		{"1.2.3z", "1.2.4", -1},
+		{"1.2a", "1.2.0", -1},
+		{"1.2.3", "1.2.3.1", -1},
+		{"1.9", "1.10", -1},
+		{"1.01", "1.1", -1},
+		{"1.001", "1.01", -1},
+		{"2.0_alpha_p3", "2.0_alpha1", -1},
+		{"1.0.2u-r0", "1.1.1-r0", -1},
+		{"1.0_git20230101~ab12cd", "1.0_git20230101~ff", 0},
+	} {
+		got, ok := CompareAlpine(tc.a, tc.b)
+		if got != tc.want || !ok {
+			t.Errorf("%q vs %q: got %d, %v; want %d", tc.a, tc.b, got, ok, tc.want)
+		}
+		if back, _ := CompareAlpine(tc.b, tc.a); back != -tc.want {
+			t.Errorf("%q vs %q: got %d, want %d", tc.b, tc.a, back, -tc.want)
+		}
+	}
+	if _, ok := CompareAlpine("1.0", "not a version"); ok {
+		t.Error("expected ok false for an invalid version")
+	}
+}
+
+// ─── FuzzyBounds ──────────────────────────────────────────────────────────────
+
+func TestAlpineVersion_FuzzyBounds(t *testing.T) {
+	for _, tc := range []struct {
+		in, lo, hi string
+	}{
+		{"1.2", "1.2_alpha", "1.3_alpha"},
+		{"1.2a", "1.2a_alpha", "1.2b_alpha"},
+		{"1.2_rc1", "1.2_rc1_alpha", "1.2_rc2_alpha"},
+		{"1.2_rc", "1.2_rc_alpha", "1.2"},
+		{"1.2_beta", "1.2_beta_alpha", "1.2_pre_alpha"},
+		{"1.2_p", "1.2_p_alpha", "1.3_alpha"},
+	} {
+		v, _ := ParseAlpineVersion(tc.in)
+		lo, hi, ok := v.FuzzyBounds()
+		if !ok || lo != tc.lo || hi != tc.hi {
+			t.Errorf("%q: got %q %q %v, want %q %q", tc.in, lo, hi, ok, tc.lo, tc.hi)
+		}
+	}
+	v, _ := ParseAlpineVersion("1.2-r1")
+	if _, _, ok := v.FuzzyBounds(); ok {
+		t.Error("1.2-r1: expected no fuzzy range")
+	}
+}
@@ -293,6 +293,11 @@ func toPreIdents(v *Version) []ident {
 			tag := t.Tag
 			out = append(out, ident{num: &tag})
 		}
+		// Maven snapshot builds order by timestamp, then build number
+		// (Extra).
+		if v.snapshot != nil && strings.EqualFold(t.Name, "snapshot") {
+			out = append(out, ident{num: v.snapshot})
+		}
 	}
 	if v.Extra != nil {
 		out = append(out, ident{num: v.Extra})
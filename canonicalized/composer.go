@@ -0,0 +1,220 @@
+package canonicalized
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/* ------------------------- */
+/*     Composer versions     */
+/* ------------------------- */
+
+// Composer stabilities, from least to most stable.
+const (
+	StabilityDev    = "dev"
+	StabilityAlpha  = "alpha"
+	StabilityBeta   = "beta"
+	StabilityRC     = "RC"
+	StabilityStable = "stable"
+)
+
+// StabilityRank orders Composer stabilities; unknown names rank 0.
+var StabilityRank = map[string]int{
+	StabilityDev:    1,
+	StabilityAlpha:  2,
+	StabilityBeta:   3,
+	StabilityRC:     4,
+	StabilityStable: 5,
+}
+
+// composerStages orders the modifiers of a Composer version; a patch
+// release ("-p1", "-pl2", "-patch3") follows the plain release.
+var composerStages = map[string]int{
+	"dev":    0,
+	"alpha":  1,
+	"a":      1,
+	"beta":   2,
+	"b":      2,
+	"rc":     3,
+	"stable": 4,
+	"":       4,
+	"patch":  5,
+	"pl":     5,
+	"p":      5,
+}
+
+// composerWildcard is what Composer normalizes the x of a branch alias such
+// as 1.0.x-dev to.
+const composerWildcard = 9999999
+
+var reComposerVersion = regexp.MustCompile(`(?i)^v?(\d+|[x*])(?:\.(\d+|[x*]))?(?:\.(\d+|[x*]))?(?:\.(\d+|[x*]))?` +
+	`(?:[._-]?(stable|beta|b|rc|alpha|a|patch|pl|p)((?:[.-]?\d+)*))?(?:[.-]?(dev))?(?:\+\S*)?$`)
+
+// ComposerVersion is a version as Composer's VersionParser normalizes it:
+// four numbers, a stability modifier and an optional -dev suffix, or a
+// branch such as dev-main.
+type ComposerVersion struct {
+	Original string `json:"original"`
+	// Branch is the name of a branch version, "main" for dev-main. The
+	// other fields are zero for branches.
+	Branch string `json:"branch,omitempty"`
+	// Numbers holds the four version numbers; an x in a branch alias such
+	// as 1.0.x-dev is 9999999.
+	Numbers [4]int64 `json:"numbers"`
+	// Modifier is the lower-cased stability modifier, e.g. "beta" or "p",
+	// and ModifierNumbers the numbers after it.
+	Modifier        string  `json:"modifier,omitempty"`
+	ModifierNumbers []int64 `json:"modifier_numbers,omitempty"`
+	// Dev is set for a -dev suffix.
+	Dev bool `json:"dev,omitempty"`
+}
+
+// ParseComposerVersion parses a Composer package version. A leading "v",
+// build metadata and the "#commit" of a branch reference are ignored;
+// "master", "trunk" and "default" are branches like "dev-master".
+func ParseComposerVersion(s string) (ComposerVersion, error) {
+	v := ComposerVersion{Original: s}
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "#"); i >= 0 {
+		s = s[:i]
+	}
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasPrefix(lower, "dev-") && len(s) > len("dev-"):
+		v.Branch = s[len("dev-"):]
+		return v, nil
+	case lower == "master" || lower == "trunk" || lower == "default":
+		v.Branch = s
+		return v, nil
+	}
+
+	m := reComposerVersion.FindStringSubmatch(s)
+	if m == nil {
+		return v, fmt.Errorf("invalid composer version %q", v.Original)
+	}
+	wildcard := false
+	for i, p := range m[1:5] {
+		switch {
+		case wildcard:
+			// 1.0.x-dev is 1.0.9999999.9999999-dev.
+			v.Numbers[i] = composerWildcard
+		case p == "":
+		case p == "x" || p == "X" || p == "*":
+			v.Numbers[i] = composerWildcard
+			wildcard = true
+		default:
+			n, err := strconv.ParseInt(p, 10, 64)
+			if err != nil {
+				return v, fmt.Errorf("invalid composer version %q: %w", v.Original, err)
+			}
+			v.Numbers[i] = n
+		}
+	}
+	v.Modifier = strings.ToLower(m[5])
+	for _, p := range strings.FieldsFunc(m[6], func(r rune) bool { return r == '.' || r == '-' }) {
+		n, _ := strconv.ParseInt(p, 10, 64)
+		v.ModifierNumbers = append(v.ModifierNumbers, n)
+	}
+	v.Dev = m[7] != ""
+	if wildcard && !v.Dev {
+		return v, fmt.Errorf("invalid composer version %q: wildcards are only valid in a -dev branch alias", v.Original)
+	}
+	return v, nil
+}
+
+// Stability returns the Composer stability of v: dev for branches and -dev
+// versions, alpha, beta or RC for those modifiers, stable otherwise.
+func (v ComposerVersion) Stability() string {
+	if v.Branch != "" || v.Dev {
+		return StabilityDev
+	}
+	switch v.stage() {
+	case 0:
+		return StabilityDev
+	case 1:
+		return StabilityAlpha
+	case 2:
+		return StabilityBeta
+	case 3:
+		return StabilityRC
+	default:
+		return StabilityStable
+	}
+}
+
+// Compare orders v and other the way Composer does. ok is false when one
+// of them is a branch: branches are only equal to themselves, and ordered
+// below every numbered version and by name among themselves.
+func (v ComposerVersion) Compare(other ComposerVersion) (c int, ok bool) {
+	switch {
+	case v.Branch != "" && other.Branch != "":
+		return strings.Compare(v.Branch, other.Branch), v.Branch == other.Branch
+	case v.Branch != "":
+		return -1, false
+	case other.Branch != "":
+		return 1, false
+	}
+	for i := range v.Numbers {
+		if c := cmpInt64(v.Numbers[i], other.Numbers[i]); c != 0 {
+			return c, true
+		}
+	}
+	if c := cmpInt64(int64(v.stage()), int64(other.stage())); c != 0 {
+		return c, true
+	}
+	for i := 0; i < max(len(v.ModifierNumbers), len(other.ModifierNumbers)); i++ {
+		if c := cmpInt64(at(v.ModifierNumbers, i), at(other.ModifierNumbers, i)); c != 0 {
+			return c, true
+		}
+	}
+	// 1.0.0-beta2-dev precedes 1.0.0-beta2.
+	switch {
+	case v.Dev && !other.Dev:
+		return -1, true
+	case !v.Dev && other.Dev:
+		return 1, true
+	}
+	return 0, true
+}
+
+// stage ranks the modifier; a bare -dev suffix, as in 1.0.0-dev, ranks
+// below alpha.
+func (v ComposerVersion) stage() int {
+	if v.Modifier == "" && v.Dev {
+		return composerStages["dev"]
+	}
+	return composerStages[v.Modifier]
+}
+
+// CompareComposer parses and compares two Composer versions. Versions that
+// do not parse are treated as branches.
+func CompareComposer(a, b string) (int, bool) {
+	va, err := ParseComposerVersion(a)
+	if err != nil {
+		va = ComposerVersion{Original: a, Branch: a}
+	}
+	vb, err := ParseComposerVersion(b)
+	if err != nil {
+		vb = ComposerVersion{Original: b, Branch: b}
+	}
+	return va.Compare(vb)
+}
+
+func cmpInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func at(nums []int64, i int) int64 {
+	if i < len(nums) {
+		return nums[i]
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package canonicalized
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// ─── ParseComposerVersion ─────────────────────────────────────────────────────
+
+func TestParseComposerVersion(t *testing.T) {
+	for _, tc := range []struct {
+		in        string
+		branch    string
+		nums      [4]int64
+		modifier  string
+		dev       bool
+		stability string
+	}{
+		{"1.2.3", "", [4]int64{1, 2, 3, 0}, "", false, StabilityStable},
+		{"v2.0", "", [4]int64{2, 0, 0, 0}, "", false, StabilityStable},
+		{"1.0.0-beta2", "", [4]int64{1, 0, 0, 0}, "beta", false, StabilityBeta},
+		{"1.0.0-RC1", "", [4]int64{1, 0, 0, 0}, "rc", false, StabilityRC},
+		{"1.0.0-p1", "", [4]int64{1, 0, 0, 0}, "p", false, StabilityStable},
+		{"1.0.0-stable", "", [4]int64{1, 0, 0, 0}, "stable", false, StabilityStable},
+		{"1.0.0-dev", "", [4]int64{1, 0, 0, 0}, "", true, StabilityDev},
+		{"1.0.x-dev", "", [4]int64{1, 0, 9999999, 9999999}, "", true, StabilityDev},
+		{"dev-main", "main", [4]int64{}, "", false, StabilityDev},
+		{"dev-feature/x#abc123", "feature/x", [4]int64{}, "", false, StabilityDev},
+		{"master", "master", [4]int64{}, "", false, StabilityDev},
+	} {
+		v, err := ParseComposerVersion(tc.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.in, err)
+			continue
+		}
+		if v.Branch != tc.branch || v.Numbers != tc.nums || v.Modifier != tc.modifier || v.Dev != tc.dev {
+			t.Errorf("%q: got %+v", tc.in, v)
+		}
+		if got := v.Stability(); got != tc.stability {
+			t.Errorf("%q stability: got %q, want %q", tc.in, got, tc.stability)
+		}
+	}
+}
+
+func TestParseComposerVersion_Invalid(t *testing.T) {
+	for _, in := range []string{"", "latest", "1.x", "1.0.0-foo"} {
+		if _, err := ParseComposerVersion(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+// ─── CompareComposer ──────────────────────────────────────────────────────────
+
+func TestCompareComposer_Order(t *testing.T) {
+	want := []string{
+		"1.0.0-dev", "1.0.0-alpha1", "1.0.0-beta1", "1.0.0-beta2-dev", "1.0.0-beta2",
+		"1.0.0-RC1", "1.0.0", "1.0.0-p1", "1.0.0.1", "1.0.1", "1.0.x-dev", "1.1.0",
+	}
+	got := make([]string, len(want))
+	for i, v := range want {
+		got[len(want)-1-i] = v
+	}
+	sort.SliceStable(got, func(i, j int) bool {
+		c, _ := CompareComposer(got[i], got[j])
+		return c < 0
+	})
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+}
+
+func TestCompareComposer_Equal(t *testing.T) {
+	for _, pair := range [][2]string{{"1.0", "1.0.0.0"}, {"v1.2.3", "1.2.3"}, {"1.0.0-stable", "1.0.0"}, {"1.0.0-b1", "1.0.0-beta.1"}} {
+		if c, ok := CompareComposer(pair[0], pair[1]); c != 0 || !ok {
+			t.Errorf("%q vs %q: got %d, %v; want 0, true", pair[0], pair[1], c, ok)
+		}
+	}
+}
+
+func TestCompareComposer_Branches(t *testing.T) {
+	if c, ok := CompareComposer("dev-main", "dev-main"); c != 0 || !ok {
+		t.Errorf("same branch: got %d, %v", c, ok)
+	}
+	if _, ok := CompareComposer("dev-main", "dev-next"); ok {
+		t.Error("different branches should not be ordered")
+	}
+	if c, ok := CompareComposer("dev-main", "0.0.1"); c >= 0 || ok {
+		t.Errorf("branch vs release: got %d, %v; want -1, false", c, ok)
+	}
+}
@@ -0,0 +1,226 @@
+package canonicalized
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/* ------------------------- */
+/*      Conda versions       */
+/* ------------------------- */
+
+// CondaVersion is a conda package version, [epoch!]version[+local], as in
+// 1!2.0.1rc1+cuda. Versions are case-insensitive and _ separates like .;
+// a trailing _ is kept, as in openssl's 1.0.1_.
+type CondaVersion struct {
+	Original string `json:"original"`
+	Epoch    int64  `json:"epoch"`
+	// Components are the dot-separated parts of the version, lower-cased.
+	Components []string `json:"components"`
+	Local      []string `json:"local,omitempty"`
+
+	parts [][]condaPart
+	local [][]condaPart
+}
+
+// condaPart is a run of digits, a run of other characters, or "post".
+// Kinds order strings before numbers before post.
+type condaPart struct {
+	kind int
+	s    string
+}
+
+const (
+	condaString = iota
+	condaNumber
+	condaPost
+)
+
+var reCondaVersion = regexp.MustCompile(`^[*.+!_0-9a-z]+$`)
+
+var reCondaRun = regexp.MustCompile(`[0-9]+|\*+|[^0-9*]+`)
+
+// ParseCondaVersion parses a conda version with conda's validity rules:
+// only letters, digits and * . + ! _, at most one epoch and one local part,
+// and no empty components.
+func ParseCondaVersion(s string) (CondaVersion, error) {
+	v := CondaVersion{Original: s}
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return v, fmt.Errorf("invalid conda version %q: empty", v.Original)
+	}
+	if !reCondaVersion.MatchString(s) {
+		return v, fmt.Errorf("invalid conda version %q: bad character", v.Original)
+	}
+	if strings.Count(s, "!") > 1 || strings.Count(s, "+") > 1 {
+		return v, fmt.Errorf("invalid conda version %q: duplicated epoch or local version", v.Original)
+	}
+	if epoch, rest, ok := strings.Cut(s, "!"); ok {
+		n, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return v, fmt.Errorf("invalid conda version %q: bad epoch", v.Original)
+		}
+		v.Epoch, s = n, rest
+	}
+	if main, local, ok := strings.Cut(s, "+"); ok {
+		v.Local = strings.Split(strings.ReplaceAll(local, "_", "."), ".")
+		s = main
+	}
+	if main, ok := strings.CutSuffix(s, "_"); ok {
+		v.Components = strings.Split(strings.ReplaceAll(main, "_", "."), ".")
+		v.Components[len(v.Components)-1] += "_"
+	} else {
+		v.Components = strings.Split(strings.ReplaceAll(s, "_", "."), ".")
+	}
+
+	// Numbers are kept without leading zeros, so 0 is "".
+	v.parts = [][]condaPart{{{kind: condaNumber, s: strings.TrimLeft(strconv.FormatInt(v.Epoch, 10), "0")}}}
+	for _, c := range v.Components {
+		p, ok := condaSplit(c)
+		if !ok {
+			return v, fmt.Errorf("invalid conda version %q: empty component", v.Original)
+		}
+		v.parts = append(v.parts, p)
+	}
+	for _, c := range v.Local {
+		p, ok := condaSplit(c)
+		if !ok {
+			return v, fmt.Errorf("invalid conda version %q: empty local component", v.Original)
+		}
+		v.local = append(v.local, p)
+	}
+	return v, nil
+}
+
+// condaSplit splits a component into runs. "dev" becomes "DEV", which
+// sorts before every other string but "*", and a component that does not
+// start with a number gets a leading 0 to keep numbers and strings in step.
+func condaSplit(c string) ([]condaPart, bool) {
+	runs := reCondaRun.FindAllString(c, -1)
+	if len(runs) == 0 {
+		return nil, false
+	}
+	var out []condaPart
+	if !isDigit(c[0]) {
+		out = append(out, condaPart{kind: condaNumber})
+	}
+	for _, r := range runs {
+		switch {
+		case isDigit(r[0]):
+			out = append(out, condaPart{kind: condaNumber, s: strings.TrimLeft(r, "0")})
+		case r == "post":
+			out = append(out, condaPart{kind: condaPost})
+		case r == "dev":
+			out = append(out, condaPart{kind: condaString, s: "DEV"})
+		default:
+			out = append(out, condaPart{kind: condaString, s: r})
+		}
+	}
+	return out, true
+}
+
+// String returns the version as written, lower-cased.
+func (v CondaVersion) String() string {
+	return strings.ToLower(strings.TrimSpace(v.Original))
+}
+
+// Compare orders v and other as conda does: by epoch, then component by
+// component, then by local version. Within a component, strings sort
+// before numbers, numbers before "post", and strings compare as text, so
+// 1.0dev1 < 1.0_ < 1.0a1 < 1.0b1 < 1.0rc1 < 1.0 < 1.0post1. Missing
+// components count as 0: 1.0 equals 1.0.0.
+func (v CondaVersion) Compare(other CondaVersion) int {
+	if c := condaCompareParts(v.parts, other.parts); c != 0 {
+		return c
+	}
+	return condaCompareParts(v.local, other.local)
+}
+
+func condaCompareParts(a, b [][]condaPart) int {
+	zero := condaPart{kind: condaNumber}
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var ca, cb []condaPart
+		if i < len(a) {
+			ca = a[i]
+		}
+		if i < len(b) {
+			cb = b[i]
+		}
+		for j := 0; j < len(ca) || j < len(cb); j++ {
+			pa, pb := zero, zero
+			if j < len(ca) {
+				pa = ca[j]
+			}
+			if j < len(cb) {
+				pb = cb[j]
+			}
+			if c := condaComparePart(pa, pb); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func condaComparePart(a, b condaPart) int {
+	if a.kind != b.kind {
+		return sign(a.kind - b.kind)
+	}
+	if a.kind == condaNumber && len(a.s) != len(b.s) {
+		return sign(len(a.s) - len(b.s))
+	}
+	return strings.Compare(a.s, b.s)
+}
+
+// IsPrerelease reports whether a component of v holds a string other than
+// "post", such as the dev, a, b or rc of 1.0dev1, 1.0a1 or 1.0rc1, which
+// sorts it before the release it leads up to.
+func (v CondaVersion) IsPrerelease() bool {
+	for _, c := range v.parts {
+		for _, p := range c {
+			if p.kind == condaString {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CompareConda parses and compares two conda versions. ok is false when
+// either does not parse; the strings are then compared as they are.
+func CompareConda(a, b string) (int, bool) {
+	va, erra := ParseCondaVersion(a)
+	vb, errb := ParseCondaVersion(b)
+	if erra != nil || errb != nil {
+		return strings.Compare(a, b), false
+	}
+	return va.Compare(vb), true
+}
+
+// CondaPrefixBounds returns the half-open range of the versions conda's
+// "prefix.*" matches: prefix itself and those that extend its last part,
+// so 1.19 covers 1.19, 1.19.5, 1.19.0rc1 and 1.19a, but not 1.190. lo is
+// prefix+"*", which sorts before every other version starting with prefix;
+// hi is the same for the prefix with its last number or letter bumped, or
+// empty when the prefix ends in neither.
+func CondaPrefixBounds(prefix string) (lo, hi string) {
+	prefix = strings.TrimSuffix(strings.TrimRight(prefix, "*"), ".")
+	lo = prefix + "*"
+	i := len(prefix)
+	for i > 0 && isDigit(prefix[i-1]) {
+		i--
+	}
+	switch {
+	case i < len(prefix):
+		n, err := strconv.ParseUint(prefix[i:], 10, 64)
+		if err != nil {
+			return lo, ""
+		}
+		return lo, prefix[:i] + strconv.FormatUint(n+1, 10) + "*"
+	case i > 0 && prefix[i-1] >= 'a' && prefix[i-1] < 'z':
+		return lo, prefix[:i-1] + string(prefix[i-1]+1) + "*"
+	}
+	return lo, ""
+}
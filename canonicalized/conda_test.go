@@ -0,0 +1,117 @@
+package canonicalized
+
+import (
+	"strings"
+	"testing"
+)
+
+// ─── ParseCondaVersion ────────────────────────────────────────────────────────
+
+func TestParseCondaVersion(t *testing.T) {
+	v, err := ParseCondaVersion("1!2.0_1RC1+cuda_118")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Epoch != 1 || strings.Join(v.Components, ".") != "2.0.1rc1" || strings.Join(v.Local, ".") != "cuda.118" {
+		t.Errorf("got %d %v %v", v.Epoch, v.Components, v.Local)
+	}
+	v, _ = ParseCondaVersion("1.0.1_")
+	if got := strings.Join(v.Components, "."); got != "1.0.1_" {
+		t.Errorf("1.0.1_: got %q", got)
+	}
+}
+
+func TestParseCondaVersion_Invalid(t *testing.T) {
+	for _, in := range []string{"", "1.0-1", "x!1.0", "1!2!3", "1.0+a+b", "1..0", "1.0 1"} {
+		if _, err := ParseCondaVersion(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+// ─── CompareConda ─────────────────────────────────────────────────────────────
+
+func TestCompareConda_Order(t *testing.T) {
+	// The ordering from conda's VersionOrder documentation.
+	for _, chain := range [][]string{
+		{"0.4", "0.4.1.rc", "0.4.1", "0.5a1", "0.5b3", "0.5C1", "0.5", "0.9.6", "0.960923", "1.0", "1.1dev1", "1.1_", "1.1a1", "1.1.dev1", "1.1.a1", "1.1.0rc1", "1.1.0", "1.1.post1", "1.1post1", "1996.07.12", "1!0.4.1", "1!3.1.1.6", "2!0.4.1"},
+	} {
+		for i := 1; i < len(chain); i++ {
+			if c, ok := CompareConda(chain[i-1], chain[i]); c >= 0 || !ok {
+				t.Errorf("%q should sort before %q (got %d, %v)", chain[i-1], chain[i], c, ok)
+			}
+		}
+	}
+}
+
+func TestCompareConda_Equal(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"0.4", "0.4.0"},
+		{"0.4.1.rc", "0.4.1.RC"},
+		{"1.1.0dev1", "1.1.dev1"},
+		{"1.1.0", "1.1"},
+		{"1.1.0post1", "1.1.post1"},
+		{"0!1.0", "1.0"},
+		{"1.0_1", "1.0.1"},
+		{"01.002", "1.2"},
+	} {
+		if c, ok := CompareConda(pair[0], pair[1]); c != 0 || !ok {
+			t.Errorf("%q vs %q: got %d, %v; want 0", pair[0], pair[1], c, ok)
+		}
+	}
+}
+
+func TestCompareConda_Local(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.0+1", "1.0", 1},
+		// A local part that starts with a string pads with 0 before it,
+		// and strings sort before numbers.
+		{"1.0+cuda", "1.0", -1},
+		{"1.0+1", "1.0+2", -1},
+		{"1.0+cuda.118", "1.0+cuda.120", -1},
+	} {
+		if got, _ := CompareConda(tc.a, tc.b); got != tc.want {
+			t.Errorf("%q vs %q: got %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+	if _, ok := CompareConda("1.0", "not a version"); ok {
+		t.Error("expected ok false for an invalid version")
+	}
+}
+
+// ─── CondaPrefixBounds ────────────────────────────────────────────────────────
+
+func TestCondaPrefixBounds(t *testing.T) {
+	for _, tc := range []struct {
+		in, lo, hi string
+	}{
+		{"1.19.*", "1.19*", "1.20*"},
+		{"1.19*", "1.19*", "1.20*"},
+		{"3.10", "3.10*", "3.11*"},
+		{"1.0a", "1.0a*", "1.0b*"},
+		{"1.0.post", "1.0.post*", "1.0.posu*"},
+		{"1.0_", "1.0_*", ""},
+	} {
+		lo, hi := CondaPrefixBounds(tc.in)
+		if lo != tc.lo || hi != tc.hi {
+			t.Errorf("%q: got %q %q, want %q %q", tc.in, lo, hi, tc.lo, tc.hi)
+		}
+	}
+
+	// Everything that starts with the prefix falls in the range, and
+	// nothing else.
+	lo, hi := CondaPrefixBounds("1.19")
+	for v, want := range map[string]bool{
+		"1.19": true, "1.19.0": true, "1.19.5": true, "1.19.0rc1": true, "1.19.dev0": true, "1.19a": true,
+		"1.18.9": false, "1.190": false, "1.20": false, "1.20a1": false, "1.2": false,
+	} {
+		c1, _ := CompareConda(v, lo)
+		c2, _ := CompareConda(v, hi)
+		if got := c1 >= 0 && c2 < 0; got != want {
+			t.Errorf("%q in [%q, %q): got %v, want %v", v, lo, hi, got, want)
+		}
+	}
+}
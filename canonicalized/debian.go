@@ -0,0 +1,183 @@
+package canonicalized
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/* ------------------------- */
+/*      Debian versions      */
+/* ------------------------- */
+
+// DebianVersion is a Debian package version, [epoch:]upstream[-revision],
+// as in 1:2.30-0ubuntu1~20.04.1.
+type DebianVersion struct {
+	Original string `json:"original"`
+	Epoch    int64  `json:"epoch"`
+	Upstream string `json:"upstream"`
+	// Revision is the Debian revision after the last hyphen; empty when
+	// the version has none, which dpkg orders like "0".
+	Revision string `json:"revision,omitempty"`
+}
+
+// ParseDebianVersion splits a Debian version into epoch, upstream version
+// and revision, with dpkg's validity rules: the epoch is a number, the
+// upstream version starts with a digit, and both parts use only
+// alphanumerics and . + ~ (and - or : where they cannot be confused with the
+// separators).
+func ParseDebianVersion(s string) (DebianVersion, error) {
+	v := DebianVersion{Original: s}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return v, fmt.Errorf("invalid debian version %q: empty", v.Original)
+	}
+	if strings.ContainsAny(s, " \t") {
+		return v, fmt.Errorf("invalid debian version %q: contains whitespace", v.Original)
+	}
+	if epoch, rest, ok := strings.Cut(s, ":"); ok {
+		n, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid debian version %q: bad epoch", v.Original)
+		}
+		v.Epoch, s = n, rest
+	}
+	if i := strings.LastIndex(s, "-"); i >= 0 {
+		v.Revision = s[i+1:]
+		s = s[:i]
+		if v.Revision == "" {
+			return v, fmt.Errorf("invalid debian version %q: empty revision", v.Original)
+		}
+	}
+	v.Upstream = s
+	if v.Upstream == "" || v.Upstream[0] < '0' || v.Upstream[0] > '9' {
+		return v, fmt.Errorf("invalid debian version %q: upstream version must start with a digit", v.Original)
+	}
+	for _, c := range v.Upstream {
+		if !isDebianChar(c) && c != '-' && c != ':' {
+			return v, fmt.Errorf("invalid debian version %q: bad character %q", v.Original, c)
+		}
+	}
+	for _, c := range v.Revision {
+		if !isDebianChar(c) {
+			return v, fmt.Errorf("invalid debian version %q: bad character %q in revision", v.Original, c)
+		}
+	}
+	return v, nil
+}
+
+func isDebianChar(c rune) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '.' || c == '+' || c == '~'
+}
+
+// String returns the version with its epoch only when non-zero.
+func (v DebianVersion) String() string {
+	s := v.Upstream
+	if v.Epoch != 0 {
+		s = strconv.FormatInt(v.Epoch, 10) + ":" + s
+	}
+	if v.Revision != "" {
+		s += "-" + v.Revision
+	}
+	return s
+}
+
+// Compare orders v and other as dpkg --compare-versions does: by epoch,
+// then upstream version, then revision.
+func (v DebianVersion) Compare(other DebianVersion) int {
+	if c := cmpInt64(v.Epoch, other.Epoch); c != 0 {
+		return c
+	}
+	if c := verrevcmp(v.Upstream, other.Upstream); c != 0 {
+		return c
+	}
+	return verrevcmp(v.Revision, other.Revision)
+}
+
+// CompareDebian parses and compares two Debian versions. ok is false when
+// either does not parse; the strings are then compared as they are.
+func CompareDebian(a, b string) (int, bool) {
+	va, erra := ParseDebianVersion(a)
+	vb, errb := ParseDebianVersion(b)
+	if erra != nil || errb != nil {
+		return strings.Compare(a, b), false
+	}
+	return va.Compare(vb), true
+}
+
+// verrevcmp is dpkg's comparison of upstream versions and revisions. The
+// strings alternate between non-digit runs, compared character by
+// character with letters before non-letters and ~ before everything, even
+// the end of the string, and digit runs, compared numerically.
+func verrevcmp(a, b string) int {
+	i, j := 0, 0
+	at := func(s string, k int) byte {
+		if k < len(s) {
+			return s[k]
+		}
+		return 0
+	}
+	for i < len(a) || j < len(b) {
+		firstDiff := 0
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := debianOrder(at(a, i)), debianOrder(at(b, j))
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			i++
+			j++
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		for i < len(a) && j < len(b) && isDigit(a[i]) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+	return 0
+}
+
+// debianOrder is the weight of a character in a non-digit run; 0 stands
+// for a digit or the end of the string.
+func debianOrder(c byte) int {
+	switch {
+	case isDigit(c):
+		return 0
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return int(c)
+	case c == '~':
+		return -1
+	case c != 0:
+		return int(c) + 256
+	default:
+		return 0
+	}
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package canonicalized
+
+import (
+	"testing"
+)
+
+// ─── ParseDebianVersion ───────────────────────────────────────────────────────
+
+func TestParseDebianVersion(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		epoch    int64
+		upstream string
+		revision string
+	}{
+		{"1:2.30-0ubuntu1~20.04.1", 1, "2.30", "0ubuntu1~20.04.1"},
+		{"2.30", 0, "2.30", ""},
+		{"1.2.3-4-5", 0, "1.2.3-4", "5"},
+		{"2:8.2.3995-1ubuntu2.1", 2, "8.2.3995", "1ubuntu2.1"},
+		{"1:1.2:3-1", 1, "1.2:3", "1"},
+		{"0.9.8~rc1+dfsg", 0, "0.9.8~rc1+dfsg", ""},
+	} {
+		v, err := ParseDebianVersion(tc.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.in, err)
+			continue
+		}
+		if v.Epoch != tc.epoch || v.Upstream != tc.upstream || v.Revision != tc.revision {
+			t.Errorf("%q: got %d %q %q, want %d %q %q", tc.in, v.Epoch, v.Upstream, v.Revision, tc.epoch, tc.upstream, tc.revision)
+		}
+	}
+}
+
+func TestParseDebianVersion_Invalid(t *testing.T) {
+	for _, in := range []string{"", "a1.0", "x:1.0", "1.0-", "1.0 1", "1.0_1", "1.2:3"} {
+		if _, err := ParseDebianVersion(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestDebianVersion_String(t *testing.T) {
+	for in, want := range map[string]string{"0:1.0-1": "1.0-1", "1:2.30": "1:2.30", "1.0": "1.0"} {
+		v, _ := ParseDebianVersion(in)
+		if got := v.String(); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+// ─── CompareDebian ────────────────────────────────────────────────────────────
+
+func TestCompareDebian(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0~", "1.0", -1},
+		{"1.0", "1.0+b1", -1},
+		{"1.0", "1.0a", -1},
+		{"1.0a", "1.0+", -1},
+		{"1.0", "1.0.1", -1},
+		{"1.9", "1.10", -1},
+		{"1:0.1", "2.0", 1},
+		{"0:1.0", "1.0", 0},
+		{"1.0-0", "1.0", 0},
+		{"1.0-1", "1.0", 1},
+		{"0001.02", "1.2", 0},
+		{"2.30-0ubuntu1~20.04.1", "2.30-0ubuntu1", -1},
+		{"2.31-0ubuntu9.9", "2.31-0ubuntu9.10", -1},
+		{"1.2.3-1", "1.2.3-1ubuntu1", -1},
+		{"1.2.3-1ubuntu1", "1.2.3-1+deb10u1", -1},
+		{"7.68.0-1ubuntu2.7", "7.68.0-1ubuntu2.18", -1},
+	} {
+		got, ok := CompareDebian(tc.a, tc.b)
+		if got != tc.want || !ok {
+			t.Errorf("%q vs %q: got %d, %v; want %d", tc.a, tc.b, got, ok, tc.want)
+		}
+		if back, _ := CompareDebian(tc.b, tc.a); back != -tc.want {
+			t.Errorf("%q vs %q: got %d, want %d", tc.b, tc.a, back, -tc.want)
+		}
+	}
+}
+
+func TestCompareDebian_Invalid(t *testing.T) {
+	if _, ok := CompareDebian("1.0", "not a version"); ok {
+		t.Error("expected ok false for an invalid version")
+	}
+}
+
+func TestCompareDebian_Epoch(t *testing.T) {
+	// The epoch outranks everything after it.
+	versions := []string{"1:1.0-1", "2.0-1", "2.0~rc1-1"}
+	for i := 1; i < len(versions); i++ {
+		if c, _ := CompareDebian(versions[i-1], versions[i]); c <= 0 {
+			t.Errorf("%q should sort after %q", versions[i-1], versions[i])
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package canonicalized
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* ------------------------- */
+/*      Maven versions       */
+/* ------------------------- */
+
+// reSnapshotBuild matches a timestamped Maven snapshot build:
+// 1.0-20230815.123045-7 (base, timestamp, build number).
+var reSnapshotBuild = regexp.MustCompile(`^(.+)-(\d{8}\.\d{6})-(\d+)$`)
+
+// ParseMavenVersion parses a Maven artifact version. It is
+// ParseVersionString, except that a timestamped snapshot build such as
+// 1.0-20230815.123045-7 parses as a snapshot of 1.0 carrying the build's
+// timestamp and, as Extra, its build number, so that builds order by
+// timestamp and then build number, below the release and the bare
+// SNAPSHOT, which stands for the newest build.
+func ParseMavenVersion(s string) Version {
+	if m := reSnapshotBuild.FindStringSubmatch(removeNoise(s)); m != nil {
+		return parseSnapshotBuild(s, m)
+	}
+	pv := ParseVersionString(s)
+	for _, t := range pv.Type {
+		if strings.EqualFold(t.Name, "snapshot") {
+			newest := int64(math.MaxInt64)
+			pv.snapshot = &newest
+		}
+	}
+	return pv
+}
+
+// parseSnapshotBuild builds the Version of a snapshot build from the
+// submatches of reSnapshotBuild.
+func parseSnapshotBuild(original string, m []string) Version {
+	pv := ParseVersionString(m[1] + "-SNAPSHOT")
+	pv.Original = original
+
+	stamp := m[2][:8] + m[2][9:]
+	parsed := stamp
+	if t, err := time.ParseInLocation("20060102150405", stamp, time.UTC); err == nil {
+		parsed = t.Format(time.RFC3339)
+	}
+	pv.Timestamp = append(pv.Timestamp, TimestampInfo{Original: m[2], Parsed: parsed})
+
+	n, _ := strconv.ParseInt(m[3], 10, 64)
+	pv.Extra = &n
+	ts, _ := strconv.ParseInt(stamp, 10, 64)
+	pv.snapshot = &ts
+	pv.Canonical += "." + stamp + "." + m[3]
+	return pv
+}
+
+// CompareMaven parses and compares two Maven versions; every pair orders.
+func CompareMaven(a, b string) (int, bool) {
+	va, vb := ParseMavenVersion(a), ParseMavenVersion(b)
+	return va.Compare(&vb), true
+}
@@ -0,0 +1,56 @@
+package canonicalized
+
+import (
+	"sort"
+	"testing"
+)
+
+// ─── ParseMavenVersion ────────────────────────────────────────────────────────
+
+func TestParseMavenVersion_SnapshotBuild(t *testing.T) {
+	v := ParseMavenVersion("1.0-20230815.123045-7")
+	assertCore(t, v, 1, 0, 0)
+	if v.Canonical != "1.0.0-snapshot.20230815123045.7" {
+		t.Errorf("canonical: got %q", v.Canonical)
+	}
+	if len(v.Timestamp) != 1 || v.Timestamp[0].Parsed != "2023-08-15T12:30:45Z" {
+		t.Errorf("timestamp: got %+v", v.Timestamp)
+	}
+	if v.Extra == nil || *v.Extra != 7 {
+		t.Errorf("build number: got %v", v.Extra)
+	}
+	if v.IsStable() {
+		t.Error("a snapshot build is not stable")
+	}
+}
+
+func TestParseMavenVersion_Plain(t *testing.T) {
+	for _, s := range []string{"1.0", "1.0-rc1", "2.3.4-SNAPSHOT"} {
+		m, g := ParseMavenVersion(s), ParseVersionString(s)
+		if m.Canonical != g.Canonical {
+			t.Errorf("%q: got %q, want %q", s, m.Canonical, g.Canonical)
+		}
+	}
+}
+
+// ─── CompareMaven ─────────────────────────────────────────────────────────────
+
+func TestCompareMaven_SnapshotBuilds(t *testing.T) {
+	got := []string{
+		"1.0", "1.0-SNAPSHOT", "1.0-20230816.010101-1", "1.0-20230815.123045-12",
+		"1.0-20230815.123045-7", "1.0-rc1", "0.9",
+	}
+	sort.SliceStable(got, func(i, j int) bool {
+		n, _ := CompareMaven(got[i], got[j])
+		return n < 0
+	})
+	want := []string{
+		"0.9", "1.0-rc1", "1.0-20230815.123045-7", "1.0-20230815.123045-12",
+		"1.0-20230816.010101-1", "1.0-SNAPSHOT", "1.0",
+	}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("pos %d: got %q, want %q", i, got[i], w)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package canonicalized
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/* ------------------------- */
+/*       RPM versions        */
+/* ------------------------- */
+
+// RPMVersion is an RPM epoch:version-release, as in 2:1.8.0-3.el8_4.
+type RPMVersion struct {
+	Original string `json:"original"`
+	// Epoch is nil when the string has none. RPM orders a missing epoch
+	// like 0.
+	Epoch   *int64 `json:"epoch,omitempty"`
+	Version string `json:"version"`
+	// Release is what follows the last hyphen; empty when the string has
+	// none, as in the "1.8.0" of a "Requires: foo >= 1.8.0".
+	Release string `json:"release,omitempty"`
+}
+
+// ParseRPMVersion splits an RPM EVR into epoch, version and release. The
+// epoch is the number before the first colon, the release what follows the
+// last hyphen.
+func ParseRPMVersion(s string) (RPMVersion, error) {
+	v := RPMVersion{Original: s}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return v, fmt.Errorf("invalid rpm version %q: empty", v.Original)
+	}
+	if strings.ContainsAny(s, " \t") {
+		return v, fmt.Errorf("invalid rpm version %q: contains whitespace", v.Original)
+	}
+	if epoch, rest, ok := strings.Cut(s, ":"); ok {
+		n, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid rpm version %q: bad epoch", v.Original)
+		}
+		v.Epoch, s = &n, rest
+	}
+	if i := strings.LastIndex(s, "-"); i >= 0 {
+		v.Release = s[i+1:]
+		s = s[:i]
+		if v.Release == "" {
+			return v, fmt.Errorf("invalid rpm version %q: empty release", v.Original)
+		}
+	}
+	v.Version = s
+	if v.Version == "" {
+		return v, fmt.Errorf("invalid rpm version %q: empty version", v.Original)
+	}
+	for _, part := range []string{v.Version, v.Release} {
+		for _, c := range part {
+			if !isRPMAlnum(byte(c)) && !strings.ContainsRune("._+~^", c) {
+				return v, fmt.Errorf("invalid rpm version %q: bad character %q", v.Original, c)
+			}
+		}
+	}
+	return v, nil
+}
+
+// String returns the version as epoch:version-release, leaving out the
+// parts the original did not have.
+func (v RPMVersion) String() string {
+	s := v.Version
+	if v.Epoch != nil {
+		s = strconv.FormatInt(*v.Epoch, 10) + ":" + s
+	}
+	if v.Release != "" {
+		s += "-" + v.Release
+	}
+	return s
+}
+
+// Compare orders v and other as rpm does: by epoch, then version, then
+// release. As in dependency matching, the release is only compared when
+// both have one, so 1.8.0 equals every 1.8.0-N.
+func (v RPMVersion) Compare(other RPMVersion) int {
+	var ea, eb int64
+	if v.Epoch != nil {
+		ea = *v.Epoch
+	}
+	if other.Epoch != nil {
+		eb = *other.Epoch
+	}
+	if c := cmpInt64(ea, eb); c != 0 {
+		return c
+	}
+	if c := RPMVerCmp(v.Version, other.Version); c != 0 || v.Release == "" || other.Release == "" {
+		return c
+	}
+	return RPMVerCmp(v.Release, other.Release)
+}
+
+// CompareRPM parses and compares two RPM EVRs. ok is false when either does
+// not parse; the strings are then compared as they are.
+func CompareRPM(a, b string) (int, bool) {
+	va, erra := ParseRPMVersion(a)
+	vb, errb := ParseRPMVersion(b)
+	if erra != nil || errb != nil {
+		return strings.Compare(a, b), false
+	}
+	return va.Compare(vb), true
+}
+
+// RPMVerCmp is rpm's rpmvercmp, which compares a version or a release. The
+// strings are split into runs of digits and runs of letters, anything else
+// separating them; digit runs compare numerically and beat letter runs,
+// letter runs compare as strings. ~ sorts before everything, even the end
+// of the string, and ^ after the end of the string but before anything
+// else: 1.0~rc1 < 1.0 < 1.0^git1 < 1.0.1.
+func RPMVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && !isRPMAlnum(a[i]) && a[i] != '~' && a[i] != '^' {
+			i++
+		}
+		for j < len(b) && !isRPMAlnum(b[j]) && b[j] != '~' && b[j] != '^' {
+			j++
+		}
+
+		// Tilde sorts before everything else.
+		if i < len(a) && a[i] == '~' || j < len(b) && b[j] == '~' {
+			if i >= len(a) || a[i] != '~' {
+				return 1
+			}
+			if j >= len(b) || b[j] != '~' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		// Caret sorts after the end of the string but before anything else.
+		if i < len(a) && a[i] == '^' || j < len(b) && b[j] == '^' {
+			if i >= len(a) {
+				return -1
+			}
+			if j >= len(b) {
+				return 1
+			}
+			if a[i] != '^' {
+				return 1
+			}
+			if b[j] != '^' {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+
+		ei, ej := i, j
+		isNum := isDigit(a[i])
+		if isNum {
+			for ei < len(a) && isDigit(a[ei]) {
+				ei++
+			}
+			for ej < len(b) && isDigit(b[ej]) {
+				ej++
+			}
+		} else {
+			for ei < len(a) && isRPMAlpha(a[ei]) {
+				ei++
+			}
+			for ej < len(b) && isRPMAlpha(b[ej]) {
+				ej++
+			}
+		}
+
+		// The segments differ in type: numbers are newer than letters.
+		if ej == j {
+			if isNum {
+				return 1
+			}
+			return -1
+		}
+
+		sa, sb := a[i:ei], b[j:ej]
+		if isNum {
+			sa, sb = strings.TrimLeft(sa, "0"), strings.TrimLeft(sb, "0")
+			if len(sa) != len(sb) {
+				return sign(len(sa) - len(sb))
+			}
+		}
+		if c := strings.Compare(sa, sb); c != 0 {
+			return c
+		}
+		i, j = ei, ej
+	}
+	switch {
+	case i >= len(a) && j >= len(b):
+		return 0
+	case i >= len(a):
+		return -1
+	}
+	return 1
+}
+
+func isRPMAlpha(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func isRPMAlnum(c byte) bool { return isDigit(c) || isRPMAlpha(c) }
@@ -0,0 +1,124 @@
+package canonicalized
+
+import (
+	"fmt"
+	"testing"
+)
+
+// ─── ParseRPMVersion ──────────────────────────────────────────────────────────
+
+func TestParseRPMVersion(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		epoch   string
+		version string
+		release string
+	}{
+		{"2:1.8.0-3.el8_4", "2", "1.8.0", "3.el8_4"},
+		{"1.8.0-3.el8_4", "", "1.8.0", "3.el8_4"},
+		{"0:1.8.0", "0", "1.8.0", ""},
+		{"1.0~rc1^git2-1.fc38", "", "1.0~rc1^git2", "1.fc38"},
+	} {
+		v, err := ParseRPMVersion(tc.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.in, err)
+			continue
+		}
+		epoch := ""
+		if v.Epoch != nil {
+			epoch = fmt.Sprint(*v.Epoch)
+		}
+		if epoch != tc.epoch || v.Version != tc.version || v.Release != tc.release {
+			t.Errorf("%q: got %q %q %q, want %q %q %q", tc.in, epoch, v.Version, v.Release, tc.epoch, tc.version, tc.release)
+		}
+		if got := v.String(); got != tc.in {
+			t.Errorf("%q: String got %q", tc.in, got)
+		}
+	}
+}
+
+func TestParseRPMVersion_Invalid(t *testing.T) {
+	for _, in := range []string{"", "x:1.0", "1.0-", "-1", "1.0 1", "1.0/1"} {
+		if _, err := ParseRPMVersion(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+// ─── RPMVerCmp ────────────────────────────────────────────────────────────────
+
+func TestRPMVerCmp(t *testing.T) {
+	// Cases from rpm's own rpmvercmp test suite.
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0.1", "2.0.1", 0},
+		{"2.0", "2.0.1", -1},
+		{"2.0.1a", "2.0.1", 1},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"10xyz", "10.1xyz", -1},
+		{"xyz10", "xyz10.1", -1},
+		{"xyz.4", "8", -1},
+		{"xyz.4", "2", -1},
+		{"5.5p2", "5.6p1", -1},
+		{"5.6p1", "6.5p1", -1},
+		{"6.0.rc1", "6.0", 1},
+		{"10b2", "10a1", 1},
+		{"1.0aa", "1.0a", 1},
+		{"10.0001", "10.1", 0},
+		{"10.0001", "10.0039", -1},
+		{"4.999.9", "5.0", -1},
+		{"20101121", "20101122", -1},
+		{"2_0", "2_0", 0},
+		{"2.0", "2_0", 0},
+		{"a", "a", 0},
+		{"a+", "a_", 0},
+		{"+", "_", 0},
+		{"1.0~rc1", "1.0~rc1", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~rc1~git123", "1.0~rc1", -1},
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.0", 1},
+		{"1.0^git1", "1.01", -1},
+		{"1.0^20160101", "1.0.1", -1},
+		{"1.0^20160101^git1", "1.0^20160101", 1},
+		{"1.0~rc1^git1", "1.0~rc1", 1},
+		{"1.0^git1~pre", "1.0^git1", -1},
+	} {
+		if got := RPMVerCmp(tc.a, tc.b); got != tc.want {
+			t.Errorf("%q vs %q: got %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+		if back := RPMVerCmp(tc.b, tc.a); back != -tc.want {
+			t.Errorf("%q vs %q: got %d, want %d", tc.b, tc.a, back, -tc.want)
+		}
+	}
+}
+
+// ─── CompareRPM ───────────────────────────────────────────────────────────────
+
+func TestCompareRPM(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"2:1.8.0-3.el8_4", "1:9.9-1", 1},
+		{"0:1.8.0-3.el8_4", "1.8.0-3.el8_4", 0},
+		{"1.8.0-3.el8_4", "1.8.0-3.el8_10", -1},
+		{"1.8.0-3.el8", "1.8.0-3.el8_4", -1},
+		{"1.8.0-3.el8_4", "1.8.0", 0},
+		{"1.8.0-3.el8_4", "1.8.1", -1},
+	} {
+		got, ok := CompareRPM(tc.a, tc.b)
+		if got != tc.want || !ok {
+			t.Errorf("%q vs %q: got %d, %v; want %d", tc.a, tc.b, got, ok, tc.want)
+		}
+	}
+	if _, ok := CompareRPM("1.0", "not a version"); ok {
+		t.Error("expected ok false for an invalid version")
+	}
+}
@@ -34,4 +34,9 @@ type Version struct {
 	Original   string           `json:"original"`
 	Timestamp  []TimestampInfo  `json:"timestamp"`
 	CommitHash []CommitHashInfo `json:"commit_hash"`
+
+	// snapshot orders the SNAPSHOT of a Maven version: the yyyyMMddHHmmss
+	// timestamp of a build, or math.MaxInt64 for a bare SNAPSHOT, which
+	// stands for the newest build. Only ParseMavenVersion sets it.
+	snapshot *int64
 }
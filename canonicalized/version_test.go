@@ -1148,4 +1148,3 @@ func assertOrder(t *testing.T, vs []*Version, want []string) {
 		}
 	}
 }
-
@@ -0,0 +1,178 @@
+package describe
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// Wording holds the phrases a Describer assembles descriptions from. Every
+// comparator phrase is a fmt template taking the version as its only verb.
+// Swap individual fields to change the wording or the language.
+type Wording struct {
+	AtLeast   string // >= inside a range
+	From      string // >= on its own
+	Above     string // >
+	Below     string // <
+	UpTo      string // <=
+	Exactly   string // =
+	Not       string // !=
+	BelowCore string // <core (compatible-release upper bound)
+
+	And string // joins comparators of one group
+	Or  string // joins OR groups
+
+	Any    string // a group that admits every version
+	None   string // a constraint that admits nothing
+	Latest string // the npm "latest" literal
+	Tag    string // any other dist-tag, e.g. the %s tag; empty falls back to Exactly
+
+	// Version formats a version before it is placed in a template.
+	// Nil leaves versions as parsed.
+	Version func(string) string
+}
+
+// English is the default wording.
+var English = Wording{
+	AtLeast:   "at least %s",
+	From:      "%s and above",
+	Above:     "above %s",
+	Below:     "below %s",
+	UpTo:      "up to %s inclusive",
+	Exactly:   "exactly %s",
+	Not:       "not %s",
+	BelowCore: "below %s, excluding its pre-releases",
+	And:       " and ",
+	Or:        ", or ",
+	Any:       "any version",
+	None:      "no version",
+	Latest:    "the latest release",
+	Tag:       "the version tagged %s",
+}
+
+// Describer turns parsed constraints into plain-language text. Output is
+// deterministic: groups and comparators keep the order the parser produced.
+type Describer struct {
+	Wording Wording
+}
+
+// New returns a Describer using w.
+func New(w Wording) Describer {
+	return Describer{Wording: w}
+}
+
+// Describe parses constraint with the parser of style and describes it in
+// English.
+func Describe(style vars.Style, constraint string) (string, error) {
+	return New(English).Constraint(style, constraint)
+}
+
+// Constraint parses constraint with the parser of style and describes it.
+// Bracket ranges (Maven, Gradle, NuGet) keep their versions as written, so
+// (,1.0] reads "up to 1.0 inclusive" rather than the parser's 1.0.0.
+func (d Describer) Constraint(style vars.Style, constraint string) (string, error) {
+	parsed, err := parser.Parse(style, constraint)
+	if err != nil {
+		return "", err
+	}
+	switch style {
+	case vars.StyleMaven, vars.StyleGradle, vars.StyleNuGet:
+		parsed = asWritten(parsed, constraint)
+	}
+	return d.Groups(parsed), nil
+}
+
+// reBracketVersion matches a version inside a bracket range.
+var reBracketVersion = regexp.MustCompile(`[^\[\](),\s]+`)
+
+// asWritten returns a copy of parsed with every version replaced by the
+// spelling constraint gives it.
+func asWritten(parsed [][]vars.Constraint, constraint string) [][]vars.Constraint {
+	written := reBracketVersion.FindAllString(constraint, -1)
+	out := make([][]vars.Constraint, len(parsed))
+	for i, ands := range parsed {
+		out[i] = append([]vars.Constraint(nil), ands...)
+		for j, c := range out[i] {
+			pv := canonicalized.NewVersion(c.Ver)
+			for _, w := range written {
+				if wv := canonicalized.NewVersion(w); wv.Equal(&pv) {
+					out[i][j].Ver = w
+					break
+				}
+			}
+		}
+	}
+	return out
+}
+
+// Groups describes already-parsed constraint groups.
+func (d Describer) Groups(parsed [][]vars.Constraint) string {
+	parts := make([]string, 0, len(parsed))
+	for _, g := range parsed {
+		if s := d.Group(g); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) == 0 {
+		return d.Wording.None
+	}
+	return strings.Join(parts, d.Wording.Or)
+}
+
+// Group describes one AND group.
+func (d Describer) Group(ands []vars.Constraint) string {
+	w := d.Wording
+	if len(ands) == 1 && ands[0].Op == ">=" && isZero(ands[0].Ver) {
+		return w.Any
+	}
+	parts := make([]string, 0, len(ands))
+	for _, c := range ands {
+		if c.Op == "=" && parser.IsDistTag(c.Ver) {
+			if c.Ver == "latest" {
+				return w.Latest
+			}
+			if w.Tag != "" {
+				return fmt.Sprintf(w.Tag, c.Ver)
+			}
+		}
+		var tmpl string
+		switch c.Op {
+		case ">=":
+			tmpl = w.AtLeast
+			if len(ands) == 1 {
+				tmpl = w.From
+			}
+		case ">":
+			tmpl = w.Above
+		case "<":
+			tmpl = w.Below
+		case "<=":
+			tmpl = w.UpTo
+		case "=":
+			tmpl = w.Exactly
+		case "!=":
+			tmpl = w.Not
+		case "<core":
+			tmpl = w.BelowCore
+		default:
+			tmpl = c.Op + " %s"
+		}
+		parts = append(parts, fmt.Sprintf(tmpl, d.version(c.Ver)))
+	}
+	return strings.Join(parts, w.And)
+}
+
+func (d Describer) version(v string) string {
+	if d.Wording.Version != nil {
+		return d.Wording.Version(v)
+	}
+	return v
+}
+
+func isZero(v string) bool {
+	return strings.Trim(v, "0.") == ""
+}
@@ -0,0 +1,67 @@
+package describe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+func TestDescribe(t *testing.T) {
+	cases := []struct {
+		style      vars.Style
+		constraint string
+		want       string
+	}{
+		{vars.StyleRuby, "~> 2.1", "at least 2.1.0 and below 3.0.0"},
+		{vars.StyleMaven, "(,1.0],[1.2,)", "up to 1.0 inclusive, or 1.2 and above"},
+		{vars.StyleMaven, "[1.0-alpha,2)", "at least 1.0-alpha and below 2"},
+		{vars.StylePy, "~=1.4.5", "at least 1.4.5 and below 1.5.0, excluding its pre-releases"},
+		{vars.StylePy, ">1.0,!=1.3.0", "above 1.0.0 and not 1.3.0"},
+		{vars.StyleNPM, "*", "any version"},
+		{vars.StyleNPM, "latest", "the latest release"},
+		{vars.StyleNPM, "next", "the version tagged next"},
+		{vars.StyleNPM, "1.2.3 || ^2.0.0", "exactly 1.2.3, or at least 2.0.0 and below 3.0.0"},
+		{vars.StyleNuGet, "[1.0,2.0)", "at least 1.0 and below 2.0"},
+		{vars.StyleGo, "", "no version"},
+	}
+	for _, c := range cases {
+		got, err := Describe(c.style, c.constraint)
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %v", c.style, c.constraint, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s %q:\n  got  %q\n  want %q", c.style, c.constraint, got, c.want)
+		}
+	}
+}
+
+func TestDescribe_UnsupportedSource(t *testing.T) {
+	if _, err := Describe(vars.StyleNPM, "file:../pkg"); !errors.Is(err, vars.ErrUnsupportedSource) {
+		t.Errorf("expected ErrUnsupportedSource, got %v", err)
+	}
+}
+
+func TestDescriber_CustomWording(t *testing.T) {
+	w := English
+	w.AtLeast = ">= %s"
+	w.Below = "< %s"
+	w.And = ", "
+	w.Version = func(v string) string { return strings.TrimSuffix(v, ".0") }
+	got, err := New(w).Constraint(vars.StyleRuby, "~> 2.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != ">= 2.1, < 3.0" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestDescriber_GroupsUnknownOperator(t *testing.T) {
+	got := New(English).Groups([][]vars.Constraint{{{Op: "~~", Ver: "1.0.0"}}})
+	if got != "~~ 1.0.0" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,398 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/vars"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Finding codes.
+const (
+	CodeUnknownStyle      = "unknown-style"
+	CodeUnparsable        = "unparsable"
+	CodeUnsupportedSource = "unsupported-source"
+	CodeDropped           = "dropped"
+	CodeUnbounded         = "unbounded"
+	CodeAnyVersion        = "any-version"
+	CodeUnsatisfiable     = "unsatisfiable"
+	CodeRedundant         = "redundant"
+	CodeLatest            = "latest"
+	CodeDistTag           = "dist-tag"
+	CodeBranch            = "branch"
+	CodePrereleasePin     = "prerelease-pin"
+)
+
+// Finding is one problem found in a constraint.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	Fix      string   `json:"fix,omitempty"`
+}
+
+// Constraint lints one constraint string written in style.
+func Constraint(style vars.Style, s string) []Finding {
+	out := []Finding{}
+	raw := strings.TrimSpace(s)
+
+	parsed, err := parser.Parse(style, raw)
+	switch {
+	case errors.Is(err, vars.ErrUnknownStyle):
+		return append(out, Finding{SeverityError, CodeUnknownStyle, fmt.Sprintf("no parser for style %q", style), ""})
+	case errors.Is(err, vars.ErrUnsupportedSource):
+		return append(out, Finding{SeverityWarning, CodeUnsupportedSource,
+			fmt.Sprintf("%q is a URL or file source and cannot be checked", raw),
+			"depend on a published version range instead"})
+	case err != nil:
+		return append(out, Finding{SeverityError, CodeUnparsable, err.Error(), ""})
+	}
+
+	if raw == "" {
+		return append(out, Finding{SeverityWarning, CodeAnyVersion, "empty constraint accepts any version",
+			"declare a range such as " + suggestRange(style, "")})
+	}
+	if len(parsed) == 0 {
+		return append(out, Finding{SeverityError, CodeUnparsable,
+			fmt.Sprintf("%q was not understood by the %s parser and matches nothing", raw, style), ""})
+	}
+
+	for _, part := range dropped(style, raw) {
+		out = append(out, Finding{SeverityWarning, CodeDropped,
+			fmt.Sprintf("%q is ignored by the %s parser", part, style),
+			fmt.Sprintf("remove %q or rewrite it as a comparator", part)})
+	}
+	if style == vars.StyleConda {
+		if b := parser.ParseCondaSpec(raw).Build; b != "" && b != "*" {
+			out = append(out, Finding{SeverityWarning, CodeDropped,
+				fmt.Sprintf("build string %q is not part of the version constraint", b),
+				"match builds separately with parser.CondaSpec.MatchBuild"})
+		}
+	}
+	for _, g := range parsed {
+		out = append(out, lintGroup(style, g)...)
+	}
+	return out
+}
+
+// Worst returns the highest severity among findings, or "" when there are none.
+func Worst(findings []Finding) Severity {
+	var worst Severity
+	rank := map[Severity]int{"": 0, SeverityInfo: 1, SeverityWarning: 2, SeverityError: 3}
+	for _, f := range findings {
+		if rank[f.Severity] > rank[worst] {
+			worst = f.Severity
+		}
+	}
+	return worst
+}
+
+// bound is one side of a group's range.
+type bound struct {
+	c vars.Constraint
+}
+
+// order compares two versions of a group.
+type order func(a, b string) int
+
+// orderOf returns the version ordering of style: its parser.Comparator, or
+// canonicalized for styles without one.
+func orderOf(style vars.Style) order {
+	if cmp := parser.Comparator(style); cmp != nil {
+		return func(a, b string) int {
+			n, _ := cmp(a, b)
+			return n
+		}
+	}
+	return func(a, b string) int {
+		va, vb := canonicalized.NewVersion(a), canonicalized.NewVersion(b)
+		return va.Compare(&vb)
+	}
+}
+
+func (b *bound) exclusive() bool { return b.c.Op == ">" || b.c.Op == "<" || b.c.Op == "<core" }
+
+func lintGroup(style vars.Style, ands []vars.Constraint) []Finding {
+	var out []Finding
+	var lowers, uppers, exacts []bound
+	cmp := orderOf(style)
+	seen := map[vars.Constraint]bool{}
+	// tagged is set once a tag finding explains the group, which then needs
+	// no any-version or unbounded finding on top.
+	tagged := false
+
+	for _, c := range ands {
+		if seen[c] {
+			out = append(out, redundant(c, "an identical bound"))
+			continue
+		}
+		seen[c] = true
+		if c.Ver == "latest" {
+			out = append(out, Finding{SeverityWarning, CodeLatest,
+				`"latest" resolves to whatever is newest at install time`,
+				"pin a range such as " + suggestRange(style, "")})
+			tagged = true
+			continue
+		}
+		if c.Op == "=" && isTag(style, c.Ver) {
+			out = append(out, Finding{SeverityWarning, CodeDistTag,
+				fmt.Sprintf("%q resolves to whatever the tag points at install time", c.Ver),
+				"pin a range such as " + suggestRange(style, "")})
+			tagged = true
+			continue
+		}
+		if c.Op == "=" && style == vars.StyleComposer && isComposerBranch(c.Ver) {
+			out = append(out, Finding{SeverityWarning, CodeBranch,
+				fmt.Sprintf("%q follows a branch and resolves to its head at install time", c.Ver),
+				"pin a range such as " + suggestRange(style, "")})
+			tagged = true
+			continue
+		}
+		b := bound{c: c}
+		switch c.Op {
+		case ">", ">=":
+			lowers = append(lowers, b)
+		case "<", "<=", "<core":
+			uppers = append(uppers, b)
+		case "=":
+			exacts = append(exacts, b)
+			if parser.IsPrereleaseFor(style, c.Ver) {
+				out = append(out, Finding{SeverityWarning, CodePrereleasePin,
+					fmt.Sprintf("exact pin on pre-release %s", c.Ver),
+					"depend on a stable release or a range that admits it"})
+			}
+		}
+	}
+
+	lo := tightest(lowers, true, cmp)
+	hi := tightest(uppers, false, cmp)
+
+	for _, set := range [][]bound{lowers, uppers} {
+		for i := range set {
+			b := &set[i]
+			if b != lo && b != hi {
+				out = append(out, redundant(b.c, "a tighter bound in the same group"))
+			}
+		}
+	}
+
+	if lo != nil && hi != nil {
+		d := cmp(lo.c.Ver, hi.c.Ver)
+		if d > 0 || (d == 0 && (lo.exclusive() || hi.exclusive())) {
+			out = append(out, Finding{SeverityError, CodeUnsatisfiable,
+				fmt.Sprintf("lower bound %s%s is not below upper bound %s%s", lo.c.Op, lo.c.Ver, hi.c.Op, hi.c.Ver),
+				"swap or correct the bounds"})
+			return out
+		}
+	}
+
+	for i, e := range exacts {
+		for _, o := range exacts[i+1:] {
+			if cmp(e.c.Ver, o.c.Ver) != 0 {
+				out = append(out, Finding{SeverityError, CodeUnsatisfiable,
+					fmt.Sprintf("cannot equal both %s and %s", e.c.Ver, o.c.Ver), "keep a single exact version"})
+				return out
+			}
+			out = append(out, redundant(o.c, "an identical pin"))
+		}
+		for _, b := range []*bound{lo, hi} {
+			if b == nil {
+				continue
+			}
+			if !satisfies(e.c.Ver, b, cmp) {
+				out = append(out, Finding{SeverityError, CodeUnsatisfiable,
+					fmt.Sprintf("pinned %s is outside %s%s", e.c.Ver, b.c.Op, b.c.Ver), "drop the pin or the bound"})
+				return out
+			}
+			out = append(out, redundant(b.c, "the exact pin "+e.c.Ver))
+		}
+	}
+
+	if len(exacts) == 0 && hi == nil && !tagged {
+		if lo == nil || (lo.c.Op == ">=" && strings.Trim(lo.c.Ver, "0.") == "") {
+			out = append(out, Finding{SeverityWarning, CodeAnyVersion, "constraint accepts any version",
+				"declare a range such as " + suggestRange(style, "")})
+		} else {
+			out = append(out, Finding{SeverityWarning, CodeUnbounded,
+				fmt.Sprintf("no upper bound above %s%s, future major releases will match", lo.c.Op, lo.c.Ver),
+				"use " + suggestRange(style, lo.c.Ver)})
+		}
+	}
+	return out
+}
+
+// isTag reports whether v names a tag that resolves at install time: an npm
+// dist-tag, or Maven's LATEST and RELEASE. Other styles have no tags, so a
+// bare word there is a version.
+func isTag(style vars.Style, v string) bool {
+	switch style {
+	case vars.StyleNPM:
+		return parser.IsDistTag(v)
+	case vars.StyleMaven, vars.StyleGradle:
+		return v == "LATEST" || v == "RELEASE"
+	}
+	return false
+}
+
+// isComposerBranch reports whether v is a Composer branch version, dev-main
+// or the 1.0.x-dev of a numbered branch.
+func isComposerBranch(v string) bool {
+	v = strings.ToLower(v)
+	return strings.HasPrefix(v, "dev-") || strings.HasSuffix(v, ".x-dev")
+}
+
+// tightest returns the highest lower bound or the lowest upper bound. Between
+// equal versions the exclusive comparator is tighter.
+func tightest(bs []bound, lower bool, cmp order) *bound {
+	var best *bound
+	for i := range bs {
+		b := &bs[i]
+		if best == nil {
+			best = b
+			continue
+		}
+		d := cmp(b.c.Ver, best.c.Ver)
+		if !lower {
+			d = -d
+		}
+		if d > 0 || (d == 0 && b.exclusive() && !best.exclusive()) {
+			best = b
+		}
+	}
+	return best
+}
+
+func satisfies(v string, b *bound, cmp order) bool {
+	d := cmp(v, b.c.Ver)
+	switch b.c.Op {
+	case ">":
+		return d > 0
+	case ">=":
+		return d >= 0
+	case "<", "<core":
+		return d < 0
+	case "<=":
+		return d <= 0
+	}
+	return false
+}
+
+func redundant(c vars.Constraint, by string) Finding {
+	return Finding{SeverityInfo, CodeRedundant,
+		fmt.Sprintf("%s%s is implied by %s", c.Op, c.Ver, by),
+		fmt.Sprintf("remove %s%s", c.Op, c.Ver)}
+}
+
+// dropped returns the pieces of raw that the style's parser skipped without
+// an error. Comma-separated styles are checked part by part; npm is checked
+// token by token within each || block.
+func dropped(style vars.Style, raw string) []string {
+	var out []string
+	switch style {
+	case vars.StylePy, vars.StyleRuby, vars.StyleRust, vars.StyleGo:
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if g, err := parser.Parse(style, part); err == nil && len(g) == 0 {
+				out = append(out, part)
+			}
+		}
+	case vars.StyleNPM:
+		raw = npmRange(raw)
+		if vars.ReDashRange.MatchString(raw) || parser.IsDistTag(raw) {
+			return nil
+		}
+		for _, block := range strings.Split(raw, "||") {
+			rest := block
+			for _, m := range vars.ReNpmToken.FindAllStringSubmatch(block, -1) {
+				rest = strings.Replace(rest, m[0], " ", 1)
+				// A comparator directly following another one is matched
+				// by the token pattern but never read by ParseNPM.
+				if m[9] != "" {
+					out = append(out, m[8]+m[9])
+				}
+			}
+			out = append(out, strings.Fields(rest)...)
+		}
+	}
+	return out
+}
+
+// npmRange strips the "npm:pkg@" alias or "pkg@" prefix ParseNPM reads
+// past, leaving the range or dist-tag.
+func npmRange(raw string) string {
+	if at := strings.LastIndex(raw, "@"); at > 0 && at+1 < len(raw) && !strings.ContainsAny(raw[:at], " <>=^~|") {
+		return raw[at+1:]
+	}
+	return raw
+}
+
+// suggestRange proposes a bounded range starting at lower (or a placeholder)
+// in the syntax of style. Distribution styles name a placeholder package,
+// pkg, and keep the epoch of lower on the upper bound.
+func suggestRange(style vars.Style, lower string) string {
+	if lower == "" {
+		lower = "1.2.0"
+	}
+	epoch, upstream := splitEpoch(style, lower)
+	v := canonicalized.NewVersion(upstream)
+	major, minor := int64(0), int64(0)
+	if v.Major != nil && *v.Major > 0 {
+		major = *v.Major
+	}
+	if v.Minor != nil && *v.Minor > 0 {
+		minor = *v.Minor
+	}
+	next := fmt.Sprintf("%d.0.0", major+1)
+	switch style {
+	case vars.StyleNPM, vars.StyleRust, vars.StyleComposer:
+		return "^" + lower
+	case vars.StylePy:
+		return fmt.Sprintf(">=%s,<%s", lower, next)
+	case vars.StyleRuby:
+		return fmt.Sprintf("~> %d.%d", major, minor)
+	case vars.StyleGo:
+		return fmt.Sprintf(">=v%s, <v%s", lower, next)
+	case vars.StyleDebian:
+		return fmt.Sprintf("pkg (>= %s), pkg (<< %s%d.0)", lower, epoch, major+1)
+	case vars.StyleRPM:
+		return fmt.Sprintf("(pkg >= %s with pkg < %s%d.0)", lower, epoch, major+1)
+	case vars.StyleAlpine:
+		return fmt.Sprintf("pkg>=%s pkg<%d.0", lower, major+1)
+	case vars.StyleConda:
+		return fmt.Sprintf(">=%s,<%s%d.0", lower, epoch, major+1)
+	default:
+		return fmt.Sprintf("[%s,%s)", lower, next)
+	}
+}
+
+// splitEpoch splits the epoch off a Debian or RPM version ("1:") or a conda
+// version ("1!"), returning it with its separator.
+func splitEpoch(style vars.Style, v string) (epoch, rest string) {
+	sep := ""
+	switch style {
+	case vars.StyleDebian, vars.StyleRPM:
+		sep = ":"
+	case vars.StyleConda:
+		sep = "!"
+	default:
+		return "", v
+	}
+	if e, r, ok := strings.Cut(v, sep); ok && strings.Trim(e, "0123456789") == "" {
+		return e + sep, r
+	}
+	return "", v
+}
@@ -0,0 +1,248 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+func codes(fs []Finding) map[string]Finding {
+	out := make(map[string]Finding, len(fs))
+	for _, f := range fs {
+		out[f.Code] = f
+	}
+	return out
+}
+
+func assertCode(t *testing.T, fs []Finding, code string, sev Severity) Finding {
+	t.Helper()
+	f, ok := codes(fs)[code]
+	if !ok {
+		t.Fatalf("expected finding %q, got %+v", code, fs)
+	}
+	if f.Severity != sev {
+		t.Errorf("%s: severity got %q, want %q", code, f.Severity, sev)
+	}
+	return f
+}
+
+func assertFindings(t *testing.T, got, want []Finding) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d findings %+v, want %+v", len(got), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("finding %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// ─── Constraint ───────────────────────────────────────────────────────────────
+
+func TestConstraint_Clean(t *testing.T) {
+	for _, c := range []struct {
+		style vars.Style
+		s     string
+	}{
+		{vars.StyleNPM, "^1.2.0"},
+		{vars.StylePy, ">=1.0,<2.0"},
+		{vars.StyleRuby, "~> 2.1"},
+		{vars.StyleMaven, "[1.0,2.0)"},
+		{vars.StyleNuGet, "1.2.3"},
+	} {
+		if fs := Constraint(c.style, c.s); len(fs) != 0 {
+			t.Errorf("%s %q: expected no findings, got %+v", c.style, c.s, fs)
+		}
+	}
+}
+
+func TestConstraint_AnyVersion(t *testing.T) {
+	for _, s := range []string{"*", ">=0", ""} {
+		f := assertCode(t, Constraint(vars.StyleNPM, s), CodeAnyVersion, SeverityWarning)
+		if f.Fix == "" {
+			t.Errorf("%q: expected a suggested fix", s)
+		}
+	}
+}
+
+func TestConstraint_Unbounded(t *testing.T) {
+	f := assertCode(t, Constraint(vars.StyleNPM, ">=1.2.0"), CodeUnbounded, SeverityWarning)
+	if f.Fix != "use ^1.2.0" {
+		t.Errorf("fix: got %q", f.Fix)
+	}
+	f = assertCode(t, Constraint(vars.StylePy, ">=1.4"), CodeUnbounded, SeverityWarning)
+	if f.Fix != "use >=1.4.0,<2.0.0" {
+		t.Errorf("fix: got %q", f.Fix)
+	}
+}
+
+func TestConstraint_UnboundedStyleSyntax(t *testing.T) {
+	for _, c := range []struct {
+		style vars.Style
+		s     string
+		fix   string
+	}{
+		{vars.StyleDebian, "libc6 (>= 1:1.0)", "use pkg (>= 1:1.0), pkg (<< 1:2.0)"},
+		{vars.StyleRPM, "foo >= 1.0~rc1", "use (pkg >= 1.0~rc1 with pkg < 2.0)"},
+		{vars.StyleAlpine, "musl>=1.2", "use pkg>=1.2 pkg<2.0"},
+		{vars.StyleConda, "numpy>=1!1.21", "use >=1!1.21,<1!2.0"},
+	} {
+		f := assertCode(t, Constraint(c.style, c.s), CodeUnbounded, SeverityWarning)
+		if f.Fix != c.fix {
+			t.Errorf("%s %q: fix got %q, want %q", c.style, c.s, f.Fix, c.fix)
+		}
+		fix := strings.TrimPrefix(f.Fix, "use ")
+		if fs := Constraint(c.style, fix); len(fs) != 0 {
+			t.Errorf("%s: suggested %q is not clean: %+v", c.style, fix, fs)
+		}
+	}
+}
+
+func TestConstraint_Unsatisfiable(t *testing.T) {
+	assertCode(t, Constraint(vars.StylePy, ">2.0,<1.0"), CodeUnsatisfiable, SeverityError)
+	assertCode(t, Constraint(vars.StyleRust, ">1.0.0, <1.0.0"), CodeUnsatisfiable, SeverityError)
+	assertCode(t, Constraint(vars.StylePy, "==1.5,<1.2"), CodeUnsatisfiable, SeverityError)
+	assertCode(t, Constraint(vars.StyleRust, "=1.0.0, =1.1.0"), CodeUnsatisfiable, SeverityError)
+}
+
+func TestConstraint_StyleOrdering(t *testing.T) {
+	for _, c := range []struct {
+		style vars.Style
+		s     string
+	}{
+		{vars.StyleDebian, "libc6 (>= 1:1.0), libc6 (<< 2.0)"},
+		{vars.StyleRPM, "foo >= 2:1.0, foo < 1:5.0"},
+		{vars.StyleAlpine, "openssl>=1.2.3_p1 openssl<1.2.3"},
+		{vars.StyleConda, ">=1!1.0,<2.0"},
+		{vars.StyleComposer, ">=1.0.0-p1 <1.0.0"},
+	} {
+		fs := Constraint(c.style, c.s)
+		if _, ok := codes(fs)[CodeUnsatisfiable]; !ok {
+			t.Errorf("%s %q: expected unsatisfiable, got %+v", c.style, c.s, fs)
+		}
+	}
+}
+
+func TestConstraint_StylePrereleasePin(t *testing.T) {
+	assertCode(t, Constraint(vars.StyleDebian, "libc6 (= 1.0~rc1)"), CodePrereleasePin, SeverityWarning)
+	assertCode(t, Constraint(vars.StyleConda, "==1.0rc1"), CodePrereleasePin, SeverityWarning)
+	for _, c := range []struct {
+		style vars.Style
+		s     string
+	}{
+		{vars.StyleAlpine, "openssl=1.2.3_p1-r0"},
+		{vars.StyleDebian, "libc6 (= 2.31-0ubuntu9.9)"},
+		{vars.StyleRPM, "foo = 1:1.1.1k-5.el8"},
+	} {
+		if fs := Constraint(c.style, c.s); len(fs) != 0 {
+			t.Errorf("%s %q: expected no findings, got %+v", c.style, c.s, fs)
+		}
+	}
+}
+
+func TestConstraint_EqualInclusiveBoundsSatisfiable(t *testing.T) {
+	if _, ok := codes(Constraint(vars.StyleRust, ">=1.0.0, <=1.0.0"))[CodeUnsatisfiable]; ok {
+		t.Error(">=1.0.0 <=1.0.0 admits 1.0.0")
+	}
+}
+
+func TestConstraint_Redundant(t *testing.T) {
+	f := assertCode(t, Constraint(vars.StylePy, ">=1.0,>=1.2,<2.0"), CodeRedundant, SeverityInfo)
+	if f.Fix != "remove >=1.0.0" {
+		t.Errorf("fix: got %q", f.Fix)
+	}
+	f = assertCode(t, Constraint(vars.StyleRust, "=1.5.0, <2.0.0"), CodeRedundant, SeverityInfo)
+	if f.Fix != "remove <2.0.0" {
+		t.Errorf("fix: got %q", f.Fix)
+	}
+}
+
+func TestConstraint_RedundantIdentical(t *testing.T) {
+	fs := Constraint(vars.StylePy, "==1.0.0,==1.0.0,>=0.5")
+	want := []Finding{
+		{SeverityInfo, CodeRedundant, "=1.0.0 is implied by an identical bound", "remove =1.0.0"},
+		{SeverityInfo, CodeRedundant, ">=0.5.0 is implied by the exact pin 1.0.0", "remove >=0.5.0"},
+	}
+	assertFindings(t, fs, want)
+}
+
+func TestConstraint_Latest(t *testing.T) {
+	assertFindings(t, Constraint(vars.StyleNPM, "latest"), []Finding{
+		{SeverityWarning, CodeLatest, `"latest" resolves to whatever is newest at install time`, "pin a range such as ^1.2.0"},
+	})
+}
+
+func TestConstraint_DistTag(t *testing.T) {
+	want := []Finding{
+		{SeverityWarning, CodeDistTag, `"next" resolves to whatever the tag points at install time`, "pin a range such as ^1.2.0"},
+	}
+	for _, s := range []string{"next", "pkg@next", "npm:pkg@next"} {
+		assertFindings(t, Constraint(vars.StyleNPM, s), want)
+	}
+}
+
+func TestConstraint_MavenMetaTag(t *testing.T) {
+	assertFindings(t, Constraint(vars.StyleMaven, "LATEST"), []Finding{
+		{SeverityWarning, CodeDistTag, `"LATEST" resolves to whatever the tag points at install time`, "pin a range such as [1.2.0,2.0.0)"},
+	})
+}
+
+func TestConstraint_ComposerBranch(t *testing.T) {
+	assertFindings(t, Constraint(vars.StyleComposer, "dev-main"), []Finding{
+		{SeverityWarning, CodeBranch, `"dev-main" follows a branch and resolves to its head at install time`, "pin a range such as ^1.2.0"},
+	})
+}
+
+func TestConstraint_PrereleasePin(t *testing.T) {
+	assertCode(t, Constraint(vars.StyleNPM, "1.0.0-beta.2"), CodePrereleasePin, SeverityWarning)
+	assertCode(t, Constraint(vars.StyleMaven, "[2.0.0-rc.1]"), CodePrereleasePin, SeverityWarning)
+}
+
+func TestConstraint_Dropped(t *testing.T) {
+	f := assertCode(t, Constraint(vars.StylePy, ">=1.0,==1.2.*"), CodeDropped, SeverityWarning)
+	if f.Message != `"==1.2.*" is ignored by the python parser` {
+		t.Errorf("message: got %q", f.Message)
+	}
+	assertCode(t, Constraint(vars.StyleNPM, "^1.2.0 foo"), CodeDropped, SeverityWarning)
+}
+
+func TestConstraint_DroppedCondaBuild(t *testing.T) {
+	f := assertCode(t, Constraint(vars.StyleConda, "pytorch=2.0=cuda118*"), CodeDropped, SeverityWarning)
+	if f.Message != `build string "cuda118*" is not part of the version constraint` {
+		t.Errorf("message: got %q", f.Message)
+	}
+	assertFindings(t, Constraint(vars.StyleConda, "pytorch 2.0.* *"), nil)
+}
+
+func TestConstraint_DroppedAdjacentNPMComparator(t *testing.T) {
+	f := assertCode(t, Constraint(vars.StyleNPM, ">2.0 <1.0"), CodeDropped, SeverityWarning)
+	if f.Message != `"<1.0" is ignored by the npm parser` {
+		t.Errorf("message: got %q", f.Message)
+	}
+}
+
+func TestConstraint_Unparsable(t *testing.T) {
+	assertCode(t, Constraint(vars.StyleNuGet, "garbage"), CodeUnparsable, SeverityError)
+}
+
+func TestConstraint_UnsupportedSource(t *testing.T) {
+	assertCode(t, Constraint(vars.StyleNPM, "https://example.com/a.tgz"), CodeUnsupportedSource, SeverityWarning)
+}
+
+func TestConstraint_UnknownStyle(t *testing.T) {
+	assertCode(t, Constraint("cobol", "1.0"), CodeUnknownStyle, SeverityError)
+}
+
+// ─── Worst ────────────────────────────────────────────────────────────────────
+
+func TestWorst(t *testing.T) {
+	if got := Worst(nil); got != "" {
+		t.Errorf("empty: got %q", got)
+	}
+	fs := Constraint(vars.StylePy, ">=1.0,>=1.2")
+	if got := Worst(fs); got != SeverityWarning {
+		t.Errorf("got %q, want warning (%+v)", got, fs)
+	}
+}
@@ -0,0 +1,263 @@
+// Package lockdiff compares two resolutions of the same project, typically a
+// lockfile before and after a change, and classifies every version change.
+package lockdiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/manifest"
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/resolver"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// Kind is the kind of a change.
+type Kind string
+
+const (
+	KindAdded      Kind = "added"
+	KindRemoved    Kind = "removed"
+	KindUpgraded   Kind = "upgraded"
+	KindDowngraded Kind = "downgraded"
+	// KindChanged is a change between versions the style cannot order,
+	// such as two Composer branches.
+	KindChanged Kind = "changed"
+)
+
+// Change is one package version that differs between two lockfiles.
+type Change struct {
+	Name string `json:"name"`
+	Kind Kind   `json:"change"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Bump is the most significant component that differs between From and
+	// To. It is only set for upgrades and downgrades.
+	Bump vars.Bump `json:"bump,omitempty"`
+	// Direct is set by MarkDirect for packages the manifest asks for.
+	Direct bool `json:"direct,omitempty"`
+}
+
+// Summary counts the changes of a report.
+type Summary struct {
+	Added      int `json:"added"`
+	Removed    int `json:"removed"`
+	Upgraded   int `json:"upgraded"`
+	Downgraded int `json:"downgraded"`
+	Changed    int `json:"changed,omitempty"`
+	// Bumps counts upgrades and downgrades by their bump.
+	Bumps map[vars.Bump]int `json:"bumps,omitempty"`
+}
+
+// Report is the difference between two lockfiles.
+type Report struct {
+	Changes []Change `json:"changes"`
+	Summary Summary  `json:"summary"`
+}
+
+// Diff compares the packages of two lockfiles. A package locked at several
+// versions is compared as a set: versions present on both sides are
+// unchanged, and the remaining ones are paired lowest to lowest, so that
+// 1.0.0 and 2.0.0 becoming 1.0.0 and 3.0.0 is a single major upgrade.
+// Unpaired versions are added or removed.
+//
+// Versions are ordered by canonicalized, which misreads distribution
+// versions (epochs, ~, revisions); use DiffFor for Composer, Debian, RPM,
+// apk and conda packages.
+//
+// Changes are sorted by name, then by version.
+func Diff(before, after []manifest.Package) Report {
+	return DiffFor("", before, after)
+}
+
+// DiffFor is Diff under the ordering of style: versions are compared with
+// parser.Comparator(style) when the style has one and bumps are classified
+// with resolver.ClassifyBumpFor. A change between versions the comparator
+// cannot order, such as Composer's dev-main to dev-develop, is
+// KindChanged. manifest.LockStyle gives the style of a lockfile.
+func DiffFor(style vars.Style, before, after []manifest.Package) Report {
+	cmp := comparer(style)
+	b, a := group(before, cmp), group(after, cmp)
+	names := map[string]bool{}
+	for n := range b {
+		names[n] = true
+	}
+	for n := range a {
+		names[n] = true
+	}
+
+	r := Report{Changes: []Change{}}
+	for _, name := range sortedNames(names) {
+		from, to := subtract(b[name], a[name], cmp), subtract(a[name], b[name], cmp)
+		for i := 0; i < len(from) || i < len(to); i++ {
+			switch {
+			case i >= len(to):
+				r.add(Change{Name: name, Kind: KindRemoved, From: from[i]})
+			case i >= len(from):
+				r.add(Change{Name: name, Kind: KindAdded, To: to[i]})
+			default:
+				c := Change{Name: name, Kind: KindUpgraded, From: from[i], To: to[i]}
+				n, ok := cmp(c.To, c.From)
+				switch {
+				case !ok:
+					c.Kind = KindChanged
+					r.add(c)
+					continue
+				case n < 0:
+					c.Kind = KindDowngraded
+				}
+				c.Bump = resolver.ClassifyBumpFor(style, c.From, c.To)
+				r.add(c)
+			}
+		}
+	}
+	return r
+}
+
+// comparer returns the version ordering of style: its parser.Comparator,
+// or canonicalized for styles without one.
+func comparer(style vars.Style) parser.Compare {
+	if cmp := parser.Comparator(style); cmp != nil {
+		return cmp
+	}
+	return func(a, b string) (int, bool) {
+		va, vb := canonicalized.NewVersion(a), canonicalized.NewVersion(b)
+		return va.Compare(&vb), true
+	}
+}
+
+func (r *Report) add(c Change) {
+	r.Changes = append(r.Changes, c)
+	switch c.Kind {
+	case KindAdded:
+		r.Summary.Added++
+	case KindRemoved:
+		r.Summary.Removed++
+	case KindUpgraded:
+		r.Summary.Upgraded++
+	case KindDowngraded:
+		r.Summary.Downgraded++
+	case KindChanged:
+		r.Summary.Changed++
+	}
+	if c.Bump != "" {
+		if r.Summary.Bumps == nil {
+			r.Summary.Bumps = map[vars.Bump]int{}
+		}
+		r.Summary.Bumps[c.Bump]++
+	}
+}
+
+// MarkDirect sets Direct on the changes of packages named in deps, typically
+// the dependencies of the manifest next to the lockfile.
+func (r *Report) MarkDirect(deps []manifest.Dependency) {
+	direct := map[string]bool{}
+	for _, d := range deps {
+		direct[d.Name] = true
+		if d.Package != "" {
+			direct[d.Package] = true
+		}
+	}
+	for i := range r.Changes {
+		r.Changes[i].Direct = direct[r.Changes[i].Name]
+	}
+}
+
+// Filter returns the changes of the given kind.
+func (r Report) Filter(kind Kind) []Change {
+	var out []Change
+	for _, c := range r.Changes {
+		if c.Kind == kind {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// String returns a one-line summary such as
+// "1 added, 0 removed, 3 upgraded (2 major, 1 patch), 0 downgraded".
+func (s Summary) String() string {
+	var bumps []string
+	for _, b := range []vars.Bump{vars.BumpMajor, vars.BumpMinor, vars.BumpPatch, vars.BumpPrerelease} {
+		if n := s.Bumps[b]; n > 0 {
+			bumps = append(bumps, fmt.Sprintf("%d %s", n, b))
+		}
+	}
+	changed := fmt.Sprintf("%d upgraded", s.Upgraded)
+	if len(bumps) > 0 {
+		changed += " (" + strings.Join(bumps, ", ") + ")"
+	}
+	out := fmt.Sprintf("%d added, %d removed, %s, %d downgraded", s.Added, s.Removed, changed, s.Downgraded)
+	if s.Changed > 0 {
+		out += fmt.Sprintf(", %d changed", s.Changed)
+	}
+	return out
+}
+
+// Markdown renders the report as a summary line followed by a table.
+func (r Report) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("**" + r.Summary.String() + "**\n")
+	if len(r.Changes) == 0 {
+		return sb.String()
+	}
+	sb.WriteString("\n| Package | Change | From | To | Bump | Direct |\n")
+	sb.WriteString("|---|---|---|---|---|---|\n")
+	for _, c := range r.Changes {
+		direct := ""
+		if c.Direct {
+			direct = "yes"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s | %s |\n",
+			escape(c.Name), c.Kind, escape(c.From), escape(c.To), c.Bump, direct)
+	}
+	return sb.String()
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// group collects the versions locked for each name, sorted ascending.
+func group(pkgs []manifest.Package, cmp parser.Compare) map[string][]string {
+	out := map[string][]string{}
+	for _, p := range pkgs {
+		out[p.Name] = append(out[p.Name], p.Version)
+	}
+	for _, vs := range out {
+		sort.SliceStable(vs, func(i, j int) bool {
+			n, _ := cmp(vs[i], vs[j])
+			return n < 0
+		})
+	}
+	return out
+}
+
+// subtract returns the versions of vs that have no equal counterpart in
+// other, counting duplicates.
+func subtract(vs, other []string, cmp parser.Compare) []string {
+	used := make([]bool, len(other))
+	var out []string
+outer:
+	for i := range vs {
+		for j := range other {
+			if n, _ := cmp(vs[i], other[j]); !used[j] && n == 0 {
+				used[j] = true
+				continue outer
+			}
+		}
+		out = append(out, vs[i])
+	}
+	return out
+}
+
+func sortedNames(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,171 @@
+package lockdiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/manifest"
+	"github.com/rng70/versions/v2/vars"
+)
+
+var before = []manifest.Package{
+	{Name: "react", Version: "18.2.0"},
+	{Name: "lodash", Version: "4.17.20"},
+	{Name: "left-pad", Version: "1.3.0"},
+	{Name: "semver", Version: "5.7.2"},
+	{Name: "semver", Version: "6.3.1"},
+	{Name: "typescript", Version: "5.4.0"},
+	{Name: "vite", Version: "5.0.0-beta.1"},
+}
+
+var after = []manifest.Package{
+	{Name: "react", Version: "18.3.1"},
+	{Name: "lodash", Version: "4.17.21"},
+	{Name: "semver", Version: "5.7.2"},
+	{Name: "semver", Version: "7.6.0"},
+	{Name: "typescript", Version: "4.9.5"},
+	{Name: "vite", Version: "5.0.0"},
+	{Name: "zod", Version: "3.23.8"},
+}
+
+// ─── Diff ─────────────────────────────────────────────────────────────────────
+
+func TestDiff(t *testing.T) {
+	r := Diff(before, after)
+	want := []Change{
+		{Name: "left-pad", Kind: KindRemoved, From: "1.3.0"},
+		{Name: "lodash", Kind: KindUpgraded, From: "4.17.20", To: "4.17.21", Bump: vars.BumpPatch},
+		{Name: "react", Kind: KindUpgraded, From: "18.2.0", To: "18.3.1", Bump: vars.BumpMinor},
+		{Name: "semver", Kind: KindUpgraded, From: "6.3.1", To: "7.6.0", Bump: vars.BumpMajor},
+		{Name: "typescript", Kind: KindDowngraded, From: "5.4.0", To: "4.9.5", Bump: vars.BumpMajor},
+		{Name: "vite", Kind: KindUpgraded, From: "5.0.0-beta.1", To: "5.0.0", Bump: vars.BumpPrerelease},
+		{Name: "zod", Kind: KindAdded, To: "3.23.8"},
+	}
+	if !reflect.DeepEqual(r.Changes, want) {
+		t.Fatalf("got  %+v\nwant %+v", r.Changes, want)
+	}
+	s := r.Summary
+	if s.Added != 1 || s.Removed != 1 || s.Upgraded != 4 || s.Downgraded != 1 {
+		t.Errorf("summary: got %+v", s)
+	}
+	if s.Bumps[vars.BumpMajor] != 2 || s.Bumps[vars.BumpMinor] != 1 {
+		t.Errorf("bumps: got %v", s.Bumps)
+	}
+	if got := s.String(); got != "1 added, 1 removed, 4 upgraded (2 major, 1 minor, 1 patch, 1 prerelease), 1 downgraded" {
+		t.Errorf("summary string: got %q", got)
+	}
+}
+
+func TestDiff_EquivalentVersions(t *testing.T) {
+	r := Diff(
+		[]manifest.Package{{Name: "a", Version: "1.0"}},
+		[]manifest.Package{{Name: "a", Version: "1.0.0"}},
+	)
+	if len(r.Changes) != 0 {
+		t.Errorf("1.0 and 1.0.0 are equal: got %+v", r.Changes)
+	}
+	if got := r.Markdown(); got != "**0 added, 0 removed, 0 upgraded, 0 downgraded**\n" {
+		t.Errorf("markdown: got %q", got)
+	}
+}
+
+// ─── Output ───────────────────────────────────────────────────────────────────
+
+func TestReport_MarkDirectAndMarkdown(t *testing.T) {
+	r := Diff(before, after)
+	r.MarkDirect([]manifest.Dependency{{Name: "react"}, {Name: "ts", Package: "typescript"}})
+
+	md := r.Markdown()
+	for _, line := range []string{
+		"| Package | Change | From | To | Bump | Direct |",
+		"| react | upgraded | 18.2.0 | 18.3.1 | minor | yes |",
+		"| typescript | downgraded | 5.4.0 | 4.9.5 | major | yes |",
+		"| semver | upgraded | 6.3.1 | 7.6.0 | major |  |",
+		"| zod | added |  | 3.23.8 |  |  |",
+	} {
+		if !strings.Contains(md, line+"\n") {
+			t.Errorf("markdown missing %q:\n%s", line, md)
+		}
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `{"name":"react","change":"upgraded","from":"18.2.0","to":"18.3.1","bump":"minor","direct":true}`) {
+		t.Errorf("json: got %s", data)
+	}
+	if len(r.Filter(KindAdded)) != 1 {
+		t.Errorf("filter: got %+v", r.Filter(KindAdded))
+	}
+}
+
+func TestDiffFor_ComposerBranch(t *testing.T) {
+	read := func(lock string) []manifest.Package {
+		t.Helper()
+		pkgs, err := manifest.ReadLock("composer.lock", strings.NewReader(lock))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return pkgs
+	}
+	b := read(`{"packages": [{"name": "acme/lib", "version": "dev-main"}, {"name": "monolog/monolog", "version": "3.5.0"}]}`)
+	a := read(`{"packages": [{"name": "acme/lib", "version": "dev-develop"}, {"name": "monolog/monolog", "version": "3.6.1"}]}`)
+	style, _ := manifest.LockStyle("composer.lock")
+	r := DiffFor(style, b, a)
+	want := []Change{
+		{Name: "acme/lib", Kind: KindChanged, From: "dev-main", To: "dev-develop"},
+		{Name: "monolog/monolog", Kind: KindUpgraded, From: "3.5.0", To: "3.6.1", Bump: vars.BumpMinor},
+	}
+	if !reflect.DeepEqual(r.Changes, want) {
+		t.Fatalf("got  %+v\nwant %+v", r.Changes, want)
+	}
+	if got := r.Summary.String(); got != "0 added, 0 removed, 1 upgraded (1 minor), 0 downgraded, 1 changed" {
+		t.Errorf("summary string: got %q", got)
+	}
+}
+
+func TestDiffFor_Distro(t *testing.T) {
+	cases := []struct {
+		style         vars.Style
+		before, after string
+		kind          Kind
+		bump          vars.Bump
+	}{
+		{vars.StyleDebian, "1:2.30-0ubuntu1", "2.31-0ubuntu9", KindDowngraded, vars.BumpMajor},
+		{vars.StyleDebian, "5.0-6ubuntu1", "5.0-6ubuntu1.1", KindUpgraded, vars.BumpPatch},
+		{vars.StyleDebian, "1.0~rc1-1", "1.0-1", KindUpgraded, vars.BumpPrerelease},
+		{vars.StyleRPM, "1:1.1.1k-5.el8", "1:1.1.1k-6.el8", KindUpgraded, vars.BumpPatch},
+		{vars.StyleRPM, "2:1.0-1", "1:9.0-1", KindDowngraded, vars.BumpMajor},
+		{vars.StyleAlpine, "1.2.3-r0", "1.2.3_p1-r0", KindUpgraded, vars.BumpPatch},
+		{vars.StyleAlpine, "1.2.3_rc1-r0", "1.2.3-r0", KindUpgraded, vars.BumpPrerelease},
+		{vars.StyleAlpine, "3.0.12-r0", "3.0.12-r4", KindUpgraded, vars.BumpPatch},
+	}
+	for _, c := range cases {
+		r := DiffFor(c.style,
+			[]manifest.Package{{Name: "pkg", Version: c.before}},
+			[]manifest.Package{{Name: "pkg", Version: c.after}})
+		want := []Change{{Name: "pkg", Kind: c.kind, From: c.before, To: c.after, Bump: c.bump}}
+		if !reflect.DeepEqual(r.Changes, want) {
+			t.Errorf("%s %s -> %s: got %+v, want %+v", c.style, c.before, c.after, r.Changes, want)
+		}
+	}
+}
+
+func TestDiffFor_DpkgStatus(t *testing.T) {
+	read := func(status string) []manifest.Package {
+		t.Helper()
+		pkgs, err := manifest.ReadDpkgStatus(strings.NewReader(status))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return pkgs
+	}
+	b := read("Package: bash\nStatus: install ok installed\nVersion: 5.0-6ubuntu1\n")
+	a := read("Package: bash\nStatus: install ok installed\nVersion: 5.0-6ubuntu1.1\n")
+	if r := DiffFor(vars.StyleDebian, b, a); len(r.Changes) != 1 || r.Changes[0].Kind != KindUpgraded {
+		t.Errorf("got %+v", r.Changes)
+	}
+}
@@ -0,0 +1,52 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/* ------------------------- */
+/*     apk installed db      */
+/* ------------------------- */
+
+// ReadApkInstalled reads an apk installed database, /lib/apk/db/installed,
+// and returns its packages. Each package is a block of "K:value" lines;
+// P: is the name and V: the version.
+func ReadApkInstalled(r io.Reader) ([]Package, error) {
+	var (
+		out []Package
+		p   Package
+	)
+	flush := func() {
+		if p.Name != "" && p.Version != "" {
+			out = append(out, p)
+		}
+		p = Package{}
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "P":
+			p.Name = value
+		case "V":
+			p.Version = value
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("apk installed: %w", err)
+	}
+	flush()
+	return out, nil
+}
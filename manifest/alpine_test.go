@@ -0,0 +1,44 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const apkInstalled = `C:Q1Xo7ldU0TQsOC2UMdcDnwsGsFXSE=
+P:musl
+V:1.2.3-r4
+A:x86_64
+S:383152
+T:the musl c library (libc) implementation
+o:musl
+F:lib
+R:ld-musl-x86_64.so.1
+a:0:0:755
+Z:Q1Ug5pELE6qJcW4TmOh3dmGHb6ffE=
+
+C:Q1TnJzr5wXXDM0q7gqj0dgnS2mTRo=
+P:busybox
+V:1.36.1-r2
+A:x86_64
+
+P:broken
+A:x86_64
+`
+
+// ─── ReadApkInstalled ─────────────────────────────────────────────────────────
+
+func TestReadApkInstalled(t *testing.T) {
+	pkgs, err := ReadApkInstalled(strings.NewReader(apkInstalled))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Package{
+		{Name: "musl", Version: "1.2.3-r4"},
+		{Name: "busybox", Version: "1.36.1-r2"},
+	}
+	if !reflect.DeepEqual(pkgs, want) {
+		t.Errorf("got %+v, want %+v", pkgs, want)
+	}
+}
@@ -0,0 +1,225 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+/* ------------------------- */
+/*        Cargo.toml         */
+/* ------------------------- */
+
+// CargoManifest is the dependency-related content of a Cargo.toml.
+type CargoManifest struct {
+	// Path is the manifest's location when read by ReadCargoWorkspace.
+	Path    string
+	Name    string
+	Version string
+	// Dependencies holds [dependencies], [dev-dependencies] and
+	// [build-dependencies], plus their target-specific variants. Kind is
+	// the table name, e.g. "dev-dependencies" or
+	// "target.cfg(windows).dependencies".
+	Dependencies []Dependency
+	// WorkspaceDependencies holds [workspace.dependencies].
+	WorkspaceDependencies []Dependency
+	// Members lists the workspace.members patterns.
+	Members []string
+	// Exclude lists the workspace.exclude paths.
+	Exclude []string
+}
+
+var cargoDependencyTables = []string{"dependencies", "dev-dependencies", "build-dependencies"}
+
+// ReadCargoToml reads a Cargo.toml. Cargo reads a bare requirement such as
+// "1.2" as "^1.2"; the caret is made explicit so that parser.ParseRust,
+// which treats bare versions as exact, agrees with Cargo. Git and path
+// dependencies without a version get a Cargo.lock style source
+// ("git+url", "path+path") as their constraint. Dependencies declared with
+// `workspace = true` have Workspace set and stay empty until Inherit.
+func ReadCargoToml(r io.Reader) (*CargoManifest, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("Cargo.toml: %w", err)
+	}
+	doc, err := decodeTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("Cargo.toml: %w", err)
+	}
+
+	m := &CargoManifest{}
+	pkg := tomlTable(doc, "package")
+	m.Name = tomlString(pkg, "name")
+	m.Version = tomlString(pkg, "version")
+
+	for _, t := range cargoDependencyTables {
+		m.Dependencies = append(m.Dependencies, cargoTable(tomlTable(doc, t), t)...)
+	}
+	targets := tomlTable(doc, "target")
+	for _, cfg := range sortedKeys(targets) {
+		for _, t := range cargoDependencyTables {
+			kind := "target." + cfg + "." + t
+			m.Dependencies = append(m.Dependencies, cargoTable(tomlTable(tomlTable(targets, cfg), t), kind)...)
+		}
+	}
+
+	ws := tomlTable(doc, "workspace")
+	m.WorkspaceDependencies = cargoTable(tomlTable(ws, "dependencies"), "workspace.dependencies")
+	m.Members = tomlStrings(ws["members"])
+	m.Exclude = tomlStrings(ws["exclude"])
+	return m, nil
+}
+
+func cargoTable(t map[string]any, kind string) []Dependency {
+	var out []Dependency
+	for _, name := range sortedKeys(t) {
+		d := Dependency{Name: name, Kind: kind}
+		switch v := t[name].(type) {
+		case string:
+			d.Constraint = cargoRequirement(v)
+		case map[string]any:
+			d.Package = tomlString(v, "package")
+			d.Workspace, _ = v["workspace"].(bool)
+			d.Constraint = cargoRequirement(tomlString(v, "version"))
+			if d.Constraint == "" {
+				if git := tomlString(v, "git"); git != "" {
+					d.Constraint = "git+" + git
+				} else if p := tomlString(v, "path"); p != "" {
+					d.Constraint = "path+" + p
+				}
+			}
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// cargoRequirement prefixes bare versions in a comma-separated Cargo
+// requirement with "^".
+func cargoRequirement(s string) string {
+	parts := strings.Split(strings.TrimSpace(s), ",")
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" && p[0] >= '0' && p[0] <= '9' && !strings.Contains(p, "*") {
+			p = "^" + p
+		}
+		parts[i] = p
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Inherit fills in dependencies declared with `workspace = true` from the
+// [workspace.dependencies] of ws. A member may add features but not change
+// the version, so the constraint and rename come from the workspace entry.
+func (m *CargoManifest) Inherit(ws *CargoManifest) {
+	for i, d := range m.Dependencies {
+		if !d.Workspace {
+			continue
+		}
+		for _, w := range ws.WorkspaceDependencies {
+			if w.Name == d.Name {
+				m.Dependencies[i].Constraint = w.Constraint
+				m.Dependencies[i].Package = w.Package
+				break
+			}
+		}
+	}
+}
+
+// ReadCargoWorkspace reads the Cargo.toml in dir and, when it declares a
+// workspace, the Cargo.toml of every member matched by workspace.members
+// and not listed in workspace.exclude. Members inherit workspace
+// dependencies. The root manifest comes first, members follow in path
+// order.
+func ReadCargoWorkspace(fsys fs.FS, dir string) ([]*CargoManifest, error) {
+	root, err := readCargoAt(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	root.Inherit(root)
+	out := []*CargoManifest{root}
+
+	excluded := map[string]bool{}
+	for _, e := range root.Exclude {
+		excluded[path.Join(dir, e)] = true
+	}
+	seen := map[string]bool{path.Clean(dir): true}
+	for _, pattern := range root.Members {
+		matches, err := fs.Glob(fsys, path.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("Cargo.toml workspace.members %q: %w", pattern, err)
+		}
+		for _, member := range matches {
+			if seen[member] || excluded[member] {
+				continue
+			}
+			seen[member] = true
+			if _, err := fs.Stat(fsys, path.Join(member, "Cargo.toml")); err != nil {
+				continue
+			}
+			m, err := readCargoAt(fsys, member)
+			if err != nil {
+				return nil, err
+			}
+			m.Inherit(root)
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func readCargoAt(fsys fs.FS, dir string) (*CargoManifest, error) {
+	name := path.Join(dir, "Cargo.toml")
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := ReadCargoToml(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	m.Path = name
+	return m, nil
+}
+
+/* ------------------------- */
+/*        Cargo.lock         */
+/* ------------------------- */
+
+// ReadCargoLock reads a Cargo.lock. A crate may appear at several versions;
+// Verify picks the one matching each requirement.
+func ReadCargoLock(r io.Reader) ([]Package, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("Cargo.lock: %w", err)
+	}
+	doc, err := decodeTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("Cargo.lock: %w", err)
+	}
+	pkgs, _ := doc["package"].([]any)
+	out := make([]Package, 0, len(pkgs))
+	for _, it := range pkgs {
+		t, _ := it.(map[string]any)
+		p := Package{Name: tomlString(t, "name"), Version: tomlString(t, "version"), Source: tomlString(t, "source")}
+		if p.Name == "" || p.Version == "" {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func tomlStrings(v any) []string {
+	items, _ := v.([]any)
+	var out []string
+	for _, it := range items {
+		if s, ok := it.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+const cargoLock = `# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "rand 0.8.5",
+ "serde",
+]
+
+[[package]]
+name = "rand"
+version = "0.7.3"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand"
+version = "0.8.5"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "34af8d1a0e25924bc5b7c43c079c942339d8f0a8b57c39049bef581b46327404"
+
+[[package]]
+name = "serde"
+version = "1.0.197"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "tokio"
+version = "1.36.0"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "winapi"
+version = "0.3.9"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`
+
+// ─── ReadCargoToml ────────────────────────────────────────────────────────────
+
+func TestReadCargoToml(t *testing.T) {
+	m, err := ReadCargoToml(strings.NewReader(`
+[package]
+name = "app"
+version = "0.1.0"
+edition = "2021"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+rand = "0.8"
+rand07 = { package = "rand", version = "0.7" }
+local = { path = "../local" }
+fork = { git = "https://github.com/u/fork", branch = "main" }
+
+[dev-dependencies]
+tokio = { version = ">=1.30, <2", features = ["full"] }
+
+[target.'cfg(windows)'.dependencies]
+winapi = "=0.3.9"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Name != "app" || m.Version != "0.1.0" {
+		t.Errorf("metadata: got %+v", m)
+	}
+	want := []Dependency{
+		{Name: "fork", Constraint: "git+https://github.com/u/fork", Kind: "dependencies"},
+		{Name: "local", Constraint: "path+../local", Kind: "dependencies"},
+		{Name: "rand", Constraint: "^0.8", Kind: "dependencies"},
+		{Name: "rand07", Constraint: "^0.7", Kind: "dependencies", Package: "rand"},
+		{Name: "serde", Constraint: "^1.0", Kind: "dependencies"},
+		{Name: "tokio", Constraint: ">=1.30, <2", Kind: "dev-dependencies"},
+		{Name: "winapi", Constraint: "=0.3.9", Kind: "target.cfg(windows).dependencies"},
+	}
+	if !reflect.DeepEqual(m.Dependencies, want) {
+		t.Fatalf("got  %+v\nwant %+v", m.Dependencies, want)
+	}
+
+	lock, err := ReadCargoLock(strings.NewReader(cargoLock))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := map[string]Result{}
+	for _, r := range Verify(vars.StyleRust, m.Dependencies, lock) {
+		got[r.Name] = r
+	}
+	wantLocked := map[string]string{
+		"rand":   "0.8.5",
+		"rand07": "0.7.3",
+		"serde":  "1.0.197",
+		"tokio":  "1.36.0",
+		"winapi": "0.3.9",
+	}
+	for name, v := range wantLocked {
+		if got[name].Status != StatusOK || got[name].Locked != v {
+			t.Errorf("%s: got %s %q (%s), want ok %q", name, got[name].Status, got[name].Locked, got[name].Detail, v)
+		}
+	}
+	if got["rand07"].Alias != "rand" {
+		t.Errorf("rename: got alias %q", got["rand07"].Alias)
+	}
+	for _, name := range []string{"fork", "local"} {
+		if got[name].Status != StatusUnsupported {
+			t.Errorf("%s: got %s, want unsupported", name, got[name].Status)
+		}
+	}
+}
+
+func TestCargoRequirement(t *testing.T) {
+	cases := map[string]string{
+		"1.2":         "^1.2",
+		"=1.2.3":      "=1.2.3",
+		"~1.2":        "~1.2",
+		"1.*":         "1.*",
+		">=1.2,<1.5":  ">=1.2, <1.5",
+		"1.2, <1.2.9": "^1.2, <1.2.9",
+		"":            "",
+	}
+	for in, want := range cases {
+		if got := cargoRequirement(in); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+// ─── ReadCargoWorkspace ───────────────────────────────────────────────────────
+
+func TestReadCargoWorkspace(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Cargo.toml": {Data: []byte(`
+[workspace]
+members = ["crates/*"]
+exclude = ["crates/skip"]
+
+[workspace.dependencies]
+serde = { version = "1.0.150", features = ["derive"] }
+rng = { package = "rand", version = "0.8" }
+`)},
+		"crates/a/Cargo.toml": {Data: []byte(`
+[package]
+name = "a"
+version = "0.1.0"
+
+[dependencies]
+serde = { workspace = true, features = ["rc"] }
+rng.workspace = true
+`)},
+		"crates/skip/Cargo.toml": {Data: []byte("[package]\nname = \"skip\"\n")},
+		"crates/README.md":       {Data: []byte("not a crate")},
+	}
+	ms, err := ReadCargoWorkspace(fsys, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ms) != 2 || ms[0].Path != "Cargo.toml" || ms[1].Path != "crates/a/Cargo.toml" {
+		t.Fatalf("got %d manifests: %+v", len(ms), ms)
+	}
+	want := []Dependency{
+		{Name: "rng", Constraint: "^0.8", Kind: "dependencies", Package: "rand", Workspace: true},
+		{Name: "serde", Constraint: "^1.0.150", Kind: "dependencies", Workspace: true},
+	}
+	if !reflect.DeepEqual(ms[1].Dependencies, want) {
+		t.Errorf("got  %+v\nwant %+v", ms[1].Dependencies, want)
+	}
+
+	lock, _ := ReadCargoLock(strings.NewReader(cargoLock))
+	if ps := Problems(Verify(vars.StyleRust, ms[1].Dependencies, lock)); len(ps) != 0 {
+		t.Errorf("unexpected problems: %+v", ps)
+	}
+}
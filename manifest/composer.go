@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+/* ------------------------- */
+/*       composer.json       */
+/* ------------------------- */
+
+// ComposerManifest is the dependency-related content of a composer.json.
+type ComposerManifest struct {
+	Name    string
+	Version string
+	// Dependencies holds require and require-dev, tagged with Kind and
+	// sorted by kind then name.
+	Dependencies []Dependency
+	// Platform holds the requirements on PHP itself, its extensions and
+	// libraries ("php", "ext-json", "lib-openssl", "composer-plugin-api"),
+	// which no registry serves.
+	Platform []Dependency
+	// MinimumStability is the minimum-stability field, "stable" when absent.
+	MinimumStability string
+	PreferStable     bool
+}
+
+var composerDependencyKinds = []string{"require", "require-dev"}
+
+// rePlatformPackage matches the names of Composer platform packages.
+var rePlatformPackage = regexp.MustCompile(`^(?:php(?:-64bit|-ipv6|-zts|-debug)?|hhvm|(?:ext|lib)-.+|composer(?:-plugin|-runtime)?-api|composer)$`)
+
+// ReadComposerJSON reads a composer.json.
+func ReadComposerJSON(r io.Reader) (*ComposerManifest, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("composer.json: %w", err)
+	}
+
+	m := &ComposerManifest{MinimumStability: "stable"}
+	_ = json.Unmarshal(raw["name"], &m.Name)
+	_ = json.Unmarshal(raw["version"], &m.Version)
+	_ = json.Unmarshal(raw["minimum-stability"], &m.MinimumStability)
+	_ = json.Unmarshal(raw["prefer-stable"], &m.PreferStable)
+
+	for _, kind := range composerDependencyKinds {
+		deps, err := stringMap(raw[kind])
+		if err != nil {
+			return nil, fmt.Errorf("composer.json %s: %w", kind, err)
+		}
+		for _, d := range sortedDependencies(deps, kind) {
+			if rePlatformPackage.MatchString(d.Name) {
+				m.Platform = append(m.Platform, d)
+			} else {
+				m.Dependencies = append(m.Dependencies, d)
+			}
+		}
+	}
+	return m, nil
+}
+
+/* ------------------------- */
+/*       composer.lock       */
+/* ------------------------- */
+
+type composerLockPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  struct {
+		URL string `json:"url"`
+	} `json:"source"`
+	Dist struct {
+		URL string `json:"url"`
+	} `json:"dist"`
+}
+
+// ReadComposerLock reads the packages and packages-dev of a composer.lock.
+// Source is the dist URL, or the source URL for packages without one.
+func ReadComposerLock(r io.Reader) ([]Package, error) {
+	var lock struct {
+		Packages    []composerLockPackage `json:"packages"`
+		PackagesDev []composerLockPackage `json:"packages-dev"`
+	}
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, fmt.Errorf("composer.lock: %w", err)
+	}
+	var out []Package
+	for _, p := range append(lock.Packages, lock.PackagesDev...) {
+		if p.Name == "" || p.Version == "" {
+			continue
+		}
+		out = append(out, Package{Name: p.Name, Version: p.Version, Source: firstNonEmpty(p.Dist.URL, p.Source.URL)})
+	}
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+const composerJSON = `{
+  "name": "acme/app",
+  "minimum-stability": "beta",
+  "prefer-stable": true,
+  "require": {
+    "php": ">=8.1",
+    "ext-json": "*",
+    "monolog/monolog": "^3.0",
+    "symfony/console": "~6.3.0 || ^7.0",
+    "acme/internal": "dev-main"
+  },
+  "require-dev": {
+    "phpunit/phpunit": "^10.5@dev"
+  }
+}`
+
+const composerLock = `{
+  "packages": [
+    {"name": "monolog/monolog", "version": "3.5.0",
+     "dist": {"type": "zip", "url": "https://api.github.com/repos/Seldaek/monolog/zipball/abc"}},
+    {"name": "symfony/console", "version": "v6.3.12"},
+    {"name": "acme/internal", "version": "dev-main",
+     "source": {"type": "git", "url": "https://github.com/acme/internal.git"}}
+  ],
+  "packages-dev": [
+    {"name": "phpunit/phpunit", "version": "11.0.0"}
+  ]
+}`
+
+// ─── ReadComposerJSON ─────────────────────────────────────────────────────────
+
+func TestReadComposerJSON(t *testing.T) {
+	m, err := ReadComposerJSON(strings.NewReader(composerJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Name != "acme/app" || m.MinimumStability != "beta" || !m.PreferStable {
+		t.Errorf("got name=%q minimum-stability=%q prefer-stable=%v", m.Name, m.MinimumStability, m.PreferStable)
+	}
+	want := []Dependency{
+		{Name: "acme/internal", Constraint: "dev-main", Kind: "require"},
+		{Name: "monolog/monolog", Constraint: "^3.0", Kind: "require"},
+		{Name: "symfony/console", Constraint: "~6.3.0 || ^7.0", Kind: "require"},
+		{Name: "phpunit/phpunit", Constraint: "^10.5@dev", Kind: "require-dev"},
+	}
+	if !reflect.DeepEqual(m.Dependencies, want) {
+		t.Errorf("dependencies:\n got %+v\nwant %+v", m.Dependencies, want)
+	}
+	if len(m.Platform) != 2 || m.Platform[0].Name != "ext-json" || m.Platform[1].Name != "php" {
+		t.Errorf("platform: got %+v", m.Platform)
+	}
+}
+
+func TestReadComposerJSON_DefaultStability(t *testing.T) {
+	m, err := ReadComposerJSON(strings.NewReader(`{"require": {}}`))
+	if err != nil || m.MinimumStability != "stable" {
+		t.Errorf("got %+v, %v; want minimum-stability stable", m, err)
+	}
+}
+
+// ─── ReadComposerLock ─────────────────────────────────────────────────────────
+
+func TestReadComposerLock(t *testing.T) {
+	pkgs, err := ReadLock("composer.lock", strings.NewReader(composerLock))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pkgs) != 4 {
+		t.Fatalf("got %d packages, want 4: %+v", len(pkgs), pkgs)
+	}
+	if pkgs[0].Source != "https://api.github.com/repos/Seldaek/monolog/zipball/abc" ||
+		pkgs[2].Source != "https://github.com/acme/internal.git" {
+		t.Errorf("sources: got %q and %q", pkgs[0].Source, pkgs[2].Source)
+	}
+}
+
+func TestVerify_Composer(t *testing.T) {
+	m, _ := ReadComposerJSON(strings.NewReader(composerJSON))
+	lock, _ := ReadComposerLock(strings.NewReader(composerLock))
+	got := map[string]Status{}
+	for _, r := range Verify(vars.StyleComposer, m.Dependencies, lock) {
+		got[r.Name] = r.Status
+	}
+	want := map[string]Status{
+		"acme/internal":   StatusOK,
+		"monolog/monolog": StatusOK,
+		"symfony/console": StatusOK,
+		"phpunit/phpunit": StatusDrift,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReadComposerJSON_Invalid(t *testing.T) {
+	if _, err := ReadComposerJSON(strings.NewReader(`{"require": []}`)); err == nil {
+		t.Error("expected an error for a require list")
+	}
+}
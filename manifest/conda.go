@@ -0,0 +1,159 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rng70/versions/v2/parser"
+)
+
+/* ------------------------- */
+/*     environment.yml       */
+/* ------------------------- */
+
+// CondaEnvironment is the dependency-related content of a conda
+// environment.yml.
+type CondaEnvironment struct {
+	Name     string
+	Channels []string
+	// Dependencies holds the conda MatchSpecs, with Kind "dependencies". The
+	// constraint is the version spec; a channel prefix and build string are
+	// dropped. Version is set for exact pins, including the name=version=build
+	// lines written by `conda env export`.
+	Dependencies []Dependency
+	// Pip holds the requirements of the pip: subsection, read as
+	// requirements.txt lines, with Kind "pip".
+	Pip []Dependency
+}
+
+// ReadCondaEnvironment reads an environment.yml. Only the block and flow
+// list forms conda writes are understood, not YAML at large.
+func ReadCondaEnvironment(r io.Reader) (*CondaEnvironment, error) {
+	env := &CondaEnvironment{}
+	var section string // top-level key of the current block
+	pipIndent := -1    // indentation of the "- pip:" item, -1 outside it
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if indent == 0 && !strings.HasPrefix(trimmed, "-") {
+			key, value, _ := strings.Cut(trimmed, ":")
+			section, pipIndent = key, -1
+			value = strings.TrimSpace(value)
+			switch {
+			case key == "name":
+				env.Name = yamlScalar(value)
+			case strings.HasPrefix(value, "["):
+				for _, item := range yamlFlowList(value) {
+					env.add(key, item, false)
+				}
+			}
+			continue
+		}
+
+		item, ok := strings.CutPrefix(trimmed, "-")
+		if !ok {
+			continue
+		}
+		item = strings.TrimSpace(item)
+		if pipIndent >= 0 && indent <= pipIndent {
+			pipIndent = -1
+		}
+		if section == "dependencies" && pipIndent < 0 && strings.HasPrefix(item, "pip:") {
+			pipIndent = indent
+			for _, req := range yamlFlowList(strings.TrimSpace(strings.TrimPrefix(item, "pip:"))) {
+				env.add(section, req, true)
+			}
+			continue
+		}
+		env.add(section, yamlScalar(item), pipIndent >= 0)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("environment.yml: %w", err)
+	}
+	return env, nil
+}
+
+// add records one list item of the top-level section.
+func (env *CondaEnvironment) add(section, item string, pip bool) {
+	if item == "" {
+		return
+	}
+	switch {
+	case section == "channels":
+		env.Channels = append(env.Channels, item)
+	case section != "dependencies":
+	case pip:
+		if strings.HasPrefix(item, "-") {
+			return // pip options such as "-r requirements.txt"
+		}
+		d := ParseRequirement(item)
+		d.Kind = "pip"
+		env.Pip = append(env.Pip, d)
+	default:
+		spec := parser.ParseCondaSpec(item)
+		if spec.Name == "" {
+			return
+		}
+		d := Dependency{Name: spec.Name, Constraint: spec.Version, Kind: section}
+		switch v := spec.Version; {
+		case strings.HasPrefix(v, "=="):
+			d.Version = strings.TrimPrefix(v, "==")
+		case strings.HasPrefix(v, "=") && spec.Build != "":
+			d.Version = strings.TrimPrefix(v, "=")
+		case v != "" && !strings.ContainsAny(v, "<>=!~*|,()"):
+			d.Version = v
+		}
+		if strings.ContainsAny(d.Version, "*|,") {
+			d.Version = ""
+		}
+		env.Dependencies = append(env.Dependencies, d)
+	}
+}
+
+// yamlScalar removes the quotes around a YAML scalar.
+func yamlScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// yamlFlowList splits a flow list such as "[conda-forge, 'numpy>=1.21,<2']".
+func yamlFlowList(s string) []string {
+	s, ok := strings.CutPrefix(strings.TrimSpace(s), "[")
+	if !ok {
+		return nil
+	}
+	s = strings.TrimSuffix(s, "]")
+	var out []string
+	var quote byte
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		switch {
+		case i == len(s) || s[i] == ',' && quote == 0:
+			if item := yamlScalar(s[start:i]); item != "" {
+				out = append(out, item)
+			}
+			start = i + 1
+		case s[i] == quote:
+			quote = 0
+		case quote == 0 && (s[i] == '"' || s[i] == '\''):
+			quote = s[i]
+		}
+	}
+	return out
+}
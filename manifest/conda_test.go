@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const environmentYML = `name: science
+channels:
+  - conda-forge
+  - defaults
+dependencies:
+  - python=3.10
+  - numpy>=1.21,<2|1.19.*   # pinned for scipy
+  - "pytorch::pytorch=2.0=cuda118*"
+  - pandas==2.0.3
+  - libgcc-ng
+  - openssl 3.0.12 h7f8727e_0
+  - pip
+  - pip:
+    - requests>=2.28
+    - -r requirements-extra.txt
+    - Flask_SQLAlchemy==3.0.5
+  - zlib
+prefix: /opt/conda/envs/science
+`
+
+// ─── ReadCondaEnvironment ─────────────────────────────────────────────────────
+
+func TestReadCondaEnvironment(t *testing.T) {
+	env, err := ReadCondaEnvironment(strings.NewReader(environmentYML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env.Name != "science" {
+		t.Errorf("name: got %q, want %q", env.Name, "science")
+	}
+	if want := []string{"conda-forge", "defaults"}; !reflect.DeepEqual(env.Channels, want) {
+		t.Errorf("channels: got %v, want %v", env.Channels, want)
+	}
+	want := []Dependency{
+		{Name: "python", Constraint: "=3.10", Kind: "dependencies"},
+		{Name: "numpy", Constraint: ">=1.21,<2|1.19.*", Kind: "dependencies"},
+		{Name: "pytorch", Constraint: "=2.0", Kind: "dependencies", Version: "2.0"},
+		{Name: "pandas", Constraint: "==2.0.3", Kind: "dependencies", Version: "2.0.3"},
+		{Name: "libgcc-ng", Kind: "dependencies"},
+		{Name: "openssl", Constraint: "3.0.12", Kind: "dependencies", Version: "3.0.12"},
+		{Name: "pip", Kind: "dependencies"},
+		{Name: "zlib", Kind: "dependencies"},
+	}
+	if !reflect.DeepEqual(env.Dependencies, want) {
+		t.Errorf("dependencies:\n got %+v\nwant %+v", env.Dependencies, want)
+	}
+	wantPip := []Dependency{
+		{Name: "requests", Constraint: ">=2.28", Kind: "pip"},
+		{Name: "flask-sqlalchemy", Constraint: "==3.0.5", Kind: "pip", Version: "3.0.5"},
+	}
+	if !reflect.DeepEqual(env.Pip, wantPip) {
+		t.Errorf("pip:\n got %+v\nwant %+v", env.Pip, wantPip)
+	}
+}
+
+func TestReadCondaEnvironment_FlowLists(t *testing.T) {
+	env, err := ReadCondaEnvironment(strings.NewReader("channels: [conda-forge]\ndependencies: [python=3.11, 'numpy>=1.21,<2']\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(env.Channels) != 1 || len(env.Dependencies) != 2 || env.Dependencies[1].Constraint != ">=1.21,<2" {
+		t.Errorf("got channels %v, dependencies %+v", env.Channels, env.Dependencies)
+	}
+}
@@ -0,0 +1,79 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/* ------------------------- */
+/*      dpkg databases       */
+/* ------------------------- */
+
+// ReadDpkgList reads the output of `dpkg -l` and returns the installed
+// packages, those whose status column reads "i" ("ii", "hi", ...).
+// Architecture qualifiers are dropped from names: "libc6:amd64" is "libc6".
+func ReadDpkgList(r io.Reader) ([]Package, error) {
+	var out []Package
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		// Header lines start with "Desired=", "|", "||/" or "+++-".
+		if len(fields) < 3 || len(fields[0]) < 2 || len(fields[0]) > 3 || fields[0][1] != 'i' {
+			continue
+		}
+		name, _, _ := strings.Cut(fields[1], ":")
+		out = append(out, Package{Name: name, Version: fields[2]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("dpkg -l: %w", err)
+	}
+	return out, nil
+}
+
+// ReadDpkgStatus reads /var/lib/dpkg/status and returns the packages whose
+// Status ends in "installed".
+func ReadDpkgStatus(r io.Reader) ([]Package, error) {
+	var (
+		out       []Package
+		p         Package
+		installed bool
+	)
+	flush := func() {
+		if installed && p.Name != "" && p.Version != "" {
+			out = append(out, p)
+		}
+		p, installed = Package{}, false
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue // continuation of a multi-line field
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			p.Name = value
+		case "Version":
+			p.Version = value
+		case "Status":
+			installed = strings.HasSuffix(value, " installed")
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("dpkg status: %w", err)
+	}
+	flush()
+	return out, nil
+}
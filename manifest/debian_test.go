@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const dpkgList = `Desired=Unknown/Install/Remove/Purge/Hold
+| Status=Not/Inst/Conf-files/Unpacked/halF-conf/Half-inst/trig-aWait/Trig-pend
+|/ Err?=(none)/Reinst-required (Status,Err: uppercase=bad)
+||/ Name           Version                 Architecture Description
++++-==============-=======================-============-=================================
+ii  adduser        3.118ubuntu2            all          add and remove users and groups
+ii  libc6:amd64    2.31-0ubuntu9.9         amd64        GNU C Library: Shared libraries
+hi  openssl        1.1.1f-1ubuntu2.16      amd64        Secure Sockets Layer toolkit
+rc  oldpkg         1.0-1                   amd64        removed, config files remain
+un  neverthere     <none>                  <none>       (no description available)
+`
+
+const dpkgStatus = `Package: libc6
+Status: install ok installed
+Architecture: amd64
+Source: glibc
+Version: 2.31-0ubuntu9.9
+Description: GNU C Library: Shared libraries
+ Contains the standard libraries that are used by nearly all programs on
+ the system.
+
+Package: oldpkg
+Status: deinstall ok config-files
+Version: 1.0-1
+
+Package: tzdata
+Status: install ok installed
+Version: 2023c-0ubuntu0.20.04.2
+`
+
+// ─── ReadDpkgList ─────────────────────────────────────────────────────────────
+
+func TestReadDpkgList(t *testing.T) {
+	pkgs, err := ReadDpkgList(strings.NewReader(dpkgList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Package{
+		{Name: "adduser", Version: "3.118ubuntu2"},
+		{Name: "libc6", Version: "2.31-0ubuntu9.9"},
+		{Name: "openssl", Version: "1.1.1f-1ubuntu2.16"},
+	}
+	if !reflect.DeepEqual(pkgs, want) {
+		t.Errorf("got %+v, want %+v", pkgs, want)
+	}
+}
+
+// ─── ReadDpkgStatus ───────────────────────────────────────────────────────────
+
+func TestReadDpkgStatus(t *testing.T) {
+	pkgs, err := ReadDpkgStatus(strings.NewReader(dpkgStatus))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Package{
+		{Name: "libc6", Version: "2.31-0ubuntu9.9"},
+		{Name: "tzdata", Version: "2023c-0ubuntu0.20.04.2"},
+	}
+	if !reflect.DeepEqual(pkgs, want) {
+		t.Errorf("got %+v, want %+v", pkgs, want)
+	}
+}
@@ -0,0 +1,154 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rng70/versions/v2/parser"
+)
+
+/* ------------------------- */
+/*   Gradle version catalog  */
+/* ------------------------- */
+
+// VersionCatalog is the content of a Gradle version catalog
+// (gradle/libs.versions.toml).
+type VersionCatalog struct {
+	// Versions holds the [versions] table by name.
+	Versions map[string]parser.GradleVersion
+	// Libraries holds [libraries] with Kind "libraries" and Name
+	// "group:artifact".
+	Libraries []CatalogEntry
+	// Plugins holds [plugins] with Kind "plugins" and Name the plugin id.
+	Plugins []CatalogEntry
+	// Bundles maps each bundle to the library aliases it groups.
+	Bundles map[string][]string
+	// Unresolved lists the version.ref names missing from [versions].
+	Unresolved []string
+}
+
+// CatalogEntry is one library or plugin of a version catalog. Constraint
+// is the declaration in Gradle's shorthand ("1.5", "[1.0,2.0[!!1.5") and
+// Version is set when it requires a single version; Rich keeps the full
+// declaration, rejections included.
+type CatalogEntry struct {
+	Dependency
+	// Alias is the entry's key, e.g. "groovy-core" for libs.groovy.core.
+	Alias string `json:"alias"`
+	// Ref is the [versions] name the entry takes its version from.
+	Ref  string               `json:"ref,omitempty"`
+	Rich parser.GradleVersion `json:"rich"`
+}
+
+// ReadVersionCatalog reads a Gradle version catalog. Versions may be
+// strings, rich tables with strictly, require, prefer, reject and
+// rejectAll, or references to [versions] through version.ref. Libraries
+// and plugins declared without a version, e.g. because a platform manages
+// it, have an empty Constraint.
+func ReadVersionCatalog(r io.Reader) (*VersionCatalog, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("libs.versions.toml: %w", err)
+	}
+	doc, err := decodeTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("libs.versions.toml: %w", err)
+	}
+
+	c := &VersionCatalog{Versions: map[string]parser.GradleVersion{}, Bundles: map[string][]string{}}
+	versions := tomlTable(doc, "versions")
+	for _, name := range sortedKeys(versions) {
+		c.Versions[name] = gradleVersion(versions[name])
+	}
+
+	libraries := tomlTable(doc, "libraries")
+	for _, alias := range sortedKeys(libraries) {
+		e := CatalogEntry{Alias: alias, Dependency: Dependency{Kind: "libraries"}}
+		switch v := libraries[alias].(type) {
+		case string:
+			// "group:artifact:version"
+			parts := strings.SplitN(v, ":", 3)
+			e.Name = strings.Join(parts[:min(len(parts), 2)], ":")
+			if len(parts) == 3 {
+				e.Rich = parser.ParseGradleRich(parts[2])
+			}
+		case map[string]any:
+			e.Name = tomlString(v, "module")
+			if e.Name == "" {
+				e.Name = tomlString(v, "group") + ":" + tomlString(v, "name")
+			}
+			c.version(&e, v["version"])
+		}
+		c.Libraries = append(c.Libraries, e.finish())
+	}
+
+	plugins := tomlTable(doc, "plugins")
+	for _, alias := range sortedKeys(plugins) {
+		e := CatalogEntry{Alias: alias, Dependency: Dependency{Kind: "plugins"}}
+		switch v := plugins[alias].(type) {
+		case string:
+			// "id:version"
+			id, version, ok := strings.Cut(v, ":")
+			e.Name = id
+			if ok {
+				e.Rich = parser.ParseGradleRich(version)
+			}
+		case map[string]any:
+			e.Name = tomlString(v, "id")
+			c.version(&e, v["version"])
+		}
+		c.Plugins = append(c.Plugins, e.finish())
+	}
+
+	bundles := tomlTable(doc, "bundles")
+	for _, name := range sortedKeys(bundles) {
+		c.Bundles[name] = tomlStrings(bundles[name])
+	}
+	return c, nil
+}
+
+// version fills in the version of e from the value of its version key: a
+// string, a rich table, or {ref = "name"} as written by version.ref.
+func (c *VersionCatalog) version(e *CatalogEntry, v any) {
+	t, ok := v.(map[string]any)
+	if !ok || tomlString(t, "ref") == "" {
+		e.Rich = gradleVersion(v)
+		return
+	}
+	e.Ref = tomlString(t, "ref")
+	rich, ok := c.Versions[e.Ref]
+	if !ok {
+		c.Unresolved = append(c.Unresolved, e.Ref)
+	}
+	e.Rich = rich
+}
+
+// finish sets the shorthand constraint and, for a single required version,
+// the version.
+func (e CatalogEntry) finish() CatalogEntry {
+	e.Constraint = e.Rich.String()
+	v := strings.TrimSpace(firstNonEmpty(e.Rich.Strictly, e.Rich.Require))
+	if v != "" && !e.Rich.RejectAll && !strings.ContainsAny(v, "[]()+,") && !strings.HasPrefix(v, "latest.") {
+		e.Version = v
+	}
+	return e
+}
+
+// gradleVersion reads a version that is either a string or a rich table.
+func gradleVersion(v any) parser.GradleVersion {
+	switch v := v.(type) {
+	case string:
+		return parser.ParseGradleRich(v)
+	case map[string]any:
+		g := parser.GradleVersion{
+			Strictly: tomlString(v, "strictly"),
+			Require:  tomlString(v, "require"),
+			Prefer:   tomlString(v, "prefer"),
+			Reject:   tomlStrings(v["reject"]),
+		}
+		g.RejectAll, _ = v["rejectAll"].(bool)
+		return g
+	}
+	return parser.GradleVersion{}
+}
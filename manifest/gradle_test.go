@@ -0,0 +1,116 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+const versionCatalog = `
+[versions]
+groovy = "3.0.5"
+guava = { strictly = "[31.0,32.0[", prefer = "31.1-jre" }
+slf4j = { require = "[2.0,3.0[", reject = ["2.0.8"] }
+
+[libraries]
+groovy-core = { module = "org.codehaus.groovy:groovy", version.ref = "groovy" }
+groovy-json = { group = "org.codehaus.groovy", name = "groovy-json", version.ref = "groovy" }
+guava = { module = "com.google.guava:guava", version = { ref = "guava" } }
+slf4j-api = { module = "org.slf4j:slf4j-api", version.ref = "slf4j" }
+commons-lang3 = "org.apache.commons:commons-lang3:3.12.0"
+junit = { module = "junit:junit", version = "4.+" }
+jackson-bom = { module = "com.fasterxml.jackson:jackson-bom" }
+missing = { module = "org.example:missing", version.ref = "nope" }
+
+[bundles]
+groovy = ["groovy-core", "groovy-json"]
+
+[plugins]
+versions = { id = "com.github.ben-manes.versions", version = "0.45.0" }
+kotlin = "org.jetbrains.kotlin.jvm:1.9.22"
+`
+
+// ─── ReadVersionCatalog ───────────────────────────────────────────────────────
+
+func TestReadVersionCatalog(t *testing.T) {
+	c, err := ReadVersionCatalog(strings.NewReader(versionCatalog))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, e := range c.Libraries {
+		got = append(got, e.Alias+" "+e.Name+" "+e.Constraint+" "+e.Version)
+	}
+	want := []string{
+		"commons-lang3 org.apache.commons:commons-lang3 3.12.0 3.12.0",
+		"groovy-core org.codehaus.groovy:groovy 3.0.5 3.0.5",
+		"groovy-json org.codehaus.groovy:groovy-json 3.0.5 3.0.5",
+		"guava com.google.guava:guava [31.0,32.0[!!31.1-jre ",
+		"jackson-bom com.fasterxml.jackson:jackson-bom  ",
+		"junit junit:junit 4.+ ",
+		"missing org.example:missing  ",
+		"slf4j-api org.slf4j:slf4j-api [2.0,3.0[ ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("libraries:\n got %q\nwant %q", got, want)
+	}
+
+	if c.Libraries[0].Kind != "libraries" || c.Libraries[1].Ref != "groovy" {
+		t.Errorf("kind/ref: got %+v", c.Libraries[1])
+	}
+	if r := c.Libraries[7].Rich; !reflect.DeepEqual(r.Reject, []string{"2.0.8"}) {
+		t.Errorf("slf4j reject: got %+v", r)
+	}
+	if !reflect.DeepEqual(c.Unresolved, []string{"nope"}) {
+		t.Errorf("unresolved: got %v", c.Unresolved)
+	}
+	if !reflect.DeepEqual(c.Bundles["groovy"], []string{"groovy-core", "groovy-json"}) {
+		t.Errorf("bundles: got %v", c.Bundles)
+	}
+
+	got = nil
+	for _, e := range c.Plugins {
+		got = append(got, e.Alias+" "+e.Name+" "+e.Constraint+" "+e.Kind)
+	}
+	want = []string{
+		"kotlin org.jetbrains.kotlin.jvm 1.9.22 plugins",
+		"versions com.github.ben-manes.versions 0.45.0 plugins",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plugins:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestReadVersionCatalog_RichSelect(t *testing.T) {
+	c, err := ReadVersionCatalog(strings.NewReader(versionCatalog))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	versions := []string{"2.0.7", "2.0.8", "30.1-jre", "31.0-jre", "31.1-jre", "32.0-jre"}
+	for name, want := range map[string]string{"guava": "31.1-jre", "slf4j": "2.0.7"} {
+		if got := c.Versions[name].Select(versions); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestReadVersionCatalog_Verify(t *testing.T) {
+	deps := []Dependency{
+		{Name: "junit:junit", Constraint: "4.+"},
+		{Name: "com.google.guava:guava", Constraint: "[31.0,32.0[!!31.1-jre"},
+	}
+	lock := []Package{{Name: "junit:junit", Version: "5.0.0"}, {Name: "com.google.guava:guava", Version: "31.1-jre"}}
+	res := Verify(vars.StyleGradle, deps, lock)
+	if res[0].Status != StatusDrift || res[1].Status != StatusOK {
+		t.Errorf("got %s and %s, want drift and ok", res[0].Status, res[1].Status)
+	}
+}
+
+func TestReadVersionCatalog_Invalid(t *testing.T) {
+	if _, err := ReadVersionCatalog(strings.NewReader("[versions\n")); err == nil {
+		t.Error("expected an error for malformed TOML")
+	}
+}
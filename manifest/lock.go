@@ -0,0 +1,69 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ErrUnknownLockfile is returned by ReadLock for file names it has no
+// reader for.
+var ErrUnknownLockfile = errors.New("unknown lockfile")
+
+// ReadLock reads the resolved packages of any supported lockfile, chosen by
+// the base name of name: package-lock.json, npm-shrinkwrap.json, yarn.lock,
+// Pipfile.lock, poetry.lock, Cargo.lock, Gemfile.lock, packages.lock.json
+// or composer.lock.
+func ReadLock(name string, r io.Reader) ([]Package, error) {
+	switch base := path.Base(name); base {
+	case "package-lock.json", "npm-shrinkwrap.json":
+		return ReadPackageLock(r)
+	case "yarn.lock":
+		return ReadYarnLock(r)
+	case "Pipfile.lock":
+		return ReadPipfileLock(r)
+	case "poetry.lock":
+		return ReadPoetryLock(r)
+	case "Cargo.lock":
+		return ReadCargoLock(r)
+	case "Gemfile.lock":
+		gl, err := ReadGemfileLock(r)
+		if err != nil {
+			return nil, err
+		}
+		return gl.Specs, nil
+	case "packages.lock.json":
+		nl, err := ReadPackagesLock(r)
+		if err != nil {
+			return nil, err
+		}
+		return nl.Packages, nil
+	case "composer.lock":
+		return ReadComposerLock(r)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLockfile, base)
+	}
+}
+
+// LockStyle returns the constraint style of a lockfile ReadLock reads,
+// chosen like ReadLock by the base name of name.
+func LockStyle(name string) (vars.Style, bool) {
+	switch path.Base(name) {
+	case "package-lock.json", "npm-shrinkwrap.json", "yarn.lock":
+		return vars.StyleNPM, true
+	case "Pipfile.lock", "poetry.lock":
+		return vars.StylePy, true
+	case "Cargo.lock":
+		return vars.StyleRust, true
+	case "Gemfile.lock":
+		return vars.StyleRuby, true
+	case "packages.lock.json":
+		return vars.StyleNuGet, true
+	case "composer.lock":
+		return vars.StyleComposer, true
+	}
+	return "", false
+}
@@ -0,0 +1,30 @@
+package manifest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ─── ReadLock ─────────────────────────────────────────────────────────────────
+
+func TestReadLock(t *testing.T) {
+	pkgs, err := ReadLock("sub/dir/packages.lock.json", strings.NewReader(packagesLockJSON))
+	if err != nil || len(pkgs) != 4 {
+		t.Errorf("packages.lock.json: got %+v, %v", pkgs, err)
+	}
+	if _, err := ReadLock("pubspec.lock", strings.NewReader("{}")); !errors.Is(err, ErrUnknownLockfile) {
+		t.Errorf("expected ErrUnknownLockfile, got %v", err)
+	}
+}
+
+func TestLockStyle(t *testing.T) {
+	if s, ok := LockStyle("app/composer.lock"); !ok || s != vars.StyleComposer {
+		t.Errorf("composer.lock: got %q, %v", s, ok)
+	}
+	if _, ok := LockStyle("pubspec.lock"); ok {
+		t.Error("pubspec.lock: expected no style")
+	}
+}
@@ -0,0 +1,199 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// Dependency is one requirement declared in a manifest.
+type Dependency struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+	// Kind is the manifest section the requirement came from, e.g.
+	// "dependencies" or "devDependencies".
+	Kind string `json:"kind,omitempty"`
+	// Version is the pinned or resolved version when the manifest itself
+	// records one next to the requirement.
+	Version string `json:"version,omitempty"`
+	// Hashes lists the artifact hashes recorded for the requirement, e.g.
+	// pip's "--hash=sha256:...".
+	Hashes []string `json:"hashes,omitempty"`
+	// Package is the registry name of a renamed dependency, e.g. Cargo's
+	// `foo = { package = "bar" }`. Empty when Name is the registry name.
+	Package string `json:"package,omitempty"`
+	// Workspace is true for a Cargo dependency inherited from
+	// [workspace.dependencies].
+	Workspace bool `json:"workspace,omitempty"`
+}
+
+// Parse parses the dependency's constraint with the parser of style.
+func (d Dependency) Parse(style vars.Style) ([][]vars.Constraint, error) {
+	return parser.Parse(style, d.Constraint)
+}
+
+// Package is one resolved entry of a lockfile.
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Requested lists the specifiers that resolved to this entry, for
+	// lockfiles that record them (e.g. "lodash@^4.17.0" in yarn.lock).
+	Requested []string `json:"requested,omitempty"`
+	// Nested is true when the entry is installed below another package
+	// rather than at the top level.
+	Nested bool `json:"nested,omitempty"`
+	// Source is the resolved download location, if recorded.
+	Source string `json:"source,omitempty"`
+}
+
+type Status string
+
+const (
+	StatusOK          Status = "ok"
+	StatusDrift       Status = "drift"
+	StatusMissing     Status = "missing"
+	StatusUnsupported Status = "unsupported"
+	StatusUnparsable  Status = "unparsable"
+	// StatusUnresolved marks an npm dist-tag such as "latest", which names
+	// whatever version the registry points it at and cannot be checked
+	// without the registry's tags.
+	StatusUnresolved Status = "unresolved"
+)
+
+// Result is the verification outcome of one dependency.
+type Result struct {
+	Dependency
+	Locked string `json:"locked,omitempty"`
+	Status Status `json:"status"`
+	// Alias is the real package name behind an npm "npm:" alias.
+	Alias  string `json:"alias,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Verify checks the locked version of every dependency against its
+// constraint. The locked version is Dependency.Version when set, otherwise
+// the matching entry of lock.
+func Verify(style vars.Style, deps []Dependency, lock []Package) []Result {
+	out := make([]Result, 0, len(deps))
+	for _, d := range deps {
+		r := Result{Dependency: d, Locked: d.Version, Alias: d.Package}
+		if style == vars.StyleNPM {
+			r.Alias = npmAliasTarget(d.Constraint)
+		}
+		if r.Locked == "" {
+			r.Locked = locked(style, lock, d)
+		}
+		r.Status, r.Detail = check(style, d.Constraint, r.Locked)
+		out = append(out, r)
+	}
+	return out
+}
+
+// Problems returns the results whose status is not StatusOK.
+func Problems(results []Result) []Result {
+	var out []Result
+	for _, r := range results {
+		if r.Status != StatusOK {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Lookup finds the lockfile entry a dependency resolved to: the entry that
+// recorded the exact "name@constraint" request, or else the first top-level
+// entry with the dependency's registry name (Package when set, else Name).
+func Lookup(lock []Package, d Dependency) (Package, bool) {
+	return LookupFor("", lock, d)
+}
+
+// LookupFor is Lookup with the package name rules of style: NuGet package
+// IDs are case-insensitive, so Newtonsoft.Json finds newtonsoft.json.
+func LookupFor(style vars.Style, lock []Package, d Dependency) (Package, bool) {
+	same := func(a, b string) bool { return a == b }
+	if style == vars.StyleNuGet {
+		same = strings.EqualFold
+	}
+	spec := d.Name + "@" + d.Constraint
+	for _, p := range lock {
+		for _, r := range p.Requested {
+			if same(r, spec) {
+				return p, true
+			}
+		}
+	}
+	for _, p := range lock {
+		if same(p.Name, registryName(d)) && !p.Nested {
+			return p, true
+		}
+	}
+	return Package{}, false
+}
+
+func registryName(d Dependency) string {
+	if d.Package != "" {
+		return d.Package
+	}
+	return d.Name
+}
+
+// locked returns the locked version of d. Lockfiles such as Cargo.lock may
+// hold several versions of one package at the top level; the first one
+// satisfying the constraint is taken, as that is the one d resolved to.
+func locked(style vars.Style, lock []Package, d Dependency) string {
+	p, ok := LookupFor(style, lock, d)
+	if !ok {
+		return ""
+	}
+	if len(p.Requested) > 0 {
+		return p.Version
+	}
+	if status, _ := check(style, d.Constraint, p.Version); status == StatusOK {
+		return p.Version
+	}
+	for _, q := range lock {
+		if q.Name == p.Name && !q.Nested {
+			if status, _ := check(style, d.Constraint, q.Version); status == StatusOK {
+				return q.Version
+			}
+		}
+	}
+	return p.Version
+}
+
+func check(style vars.Style, constraint, locked string) (Status, string) {
+	parsed, err := parser.Parse(style, constraint)
+	switch {
+	case errors.Is(err, vars.ErrUnsupportedSource):
+		return StatusUnsupported, fmt.Sprintf("%q is not a registry version range", constraint)
+	case err != nil:
+		return StatusUnparsable, err.Error()
+	case strings.TrimSpace(constraint) != "" && len(parsed) == 0:
+		return StatusUnparsable, fmt.Sprintf("%q was not understood by the %s parser", constraint, style)
+	case locked == "":
+		return StatusMissing, "no locked version found"
+	case strings.TrimSpace(constraint) == "":
+		return StatusOK, ""
+	case style == vars.StyleNPM && distTag(parsed) != "":
+		return StatusUnresolved, fmt.Sprintf("dist-tag %q needs the registry's tags to check locked %s", distTag(parsed), locked)
+	case len(parser.FilterMatchesFor(style, parsed, []string{locked})) == 0:
+		return StatusDrift, fmt.Sprintf("locked %s does not satisfy %s", locked, constraint)
+	default:
+		return StatusOK, ""
+	}
+}
+
+// distTag returns the first dist-tag groups pin to, or "".
+func distTag(groups [][]vars.Constraint) string {
+	for _, ands := range groups {
+		for _, c := range ands {
+			if c.Op == "=" && parser.IsDistTag(c.Ver) {
+				return c.Ver
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,420 @@
+package manifest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+/* ------------------------- */
+/*          pom.xml          */
+/* ------------------------- */
+
+// Pom is the dependency-related content of a pom.xml after inheritance,
+// property interpolation and dependencyManagement have been applied.
+type Pom struct {
+	// Path is the file the pom was read from, when read from an fs.FS.
+	Path       string
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Packaging  string
+	Parent     *PomParent
+	// Properties holds the merged <properties> of the pom and its parents.
+	Properties map[string]string
+	// Dependencies holds the pom's and its parents' <dependencies>. Name is
+	// "groupId:artifactId", Kind the scope and Constraint the version or
+	// range, filled from dependencyManagement when the pom leaves it out.
+	// Version is set only for hard pins ("[1.2.3]"); a soft requirement
+	// ("1.2.3") is a preference Maven may override, so it is left empty.
+	Dependencies []Dependency
+	// DependencyManagement holds the managed versions, including those of
+	// imported BOMs that could be found.
+	DependencyManagement []Dependency
+	// Unresolved lists the ${...} properties that could not be resolved,
+	// e.g. because a parent pom was not available.
+	Unresolved []string
+}
+
+// PomParent is the <parent> element of a pom.
+type PomParent struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	// RelativePath is where the parent is looked for; "../pom.xml" when the
+	// element is absent, "" when it is empty.
+	RelativePath string
+}
+
+type pomXML struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Packaging  string `xml:"packaging"`
+	Parent     *struct {
+		GroupID      string  `xml:"groupId"`
+		ArtifactID   string  `xml:"artifactId"`
+		Version      string  `xml:"version"`
+		RelativePath *string `xml:"relativePath"`
+	} `xml:"parent"`
+	Properties           xmlProperties   `xml:"properties"`
+	DependencyManagement []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	Dependencies         []pomDependency `xml:"dependencies>dependency"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Type       string `xml:"type"`
+	Classifier string `xml:"classifier"`
+}
+
+// key identifies a dependency the way dependencyManagement matches it.
+func (d pomDependency) key() string {
+	typ := d.Type
+	if typ == "" {
+		typ = "jar"
+	}
+	return d.GroupID + ":" + d.ArtifactID + ":" + typ + ":" + d.Classifier
+}
+
+// xmlProperties collects the child elements of an element as name/text
+// pairs, as in Maven <properties> or an MSBuild <PropertyGroup>.
+type xmlProperties map[string]string
+
+func (p *xmlProperties) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	*p = xmlProperties{}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var v string
+			if err := dec.DecodeElement(&v, &t); err != nil {
+				return err
+			}
+			(*p)[t.Name.Local] = strings.TrimSpace(v)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func decodePom(r io.Reader) (*pomXML, error) {
+	var px pomXML
+	if err := xml.NewDecoder(r).Decode(&px); err != nil {
+		return nil, err
+	}
+	if px.Parent != nil {
+		if px.GroupID == "" {
+			px.GroupID = px.Parent.GroupID
+		}
+		if px.Version == "" {
+			px.Version = px.Parent.Version
+		}
+	}
+	return &px, nil
+}
+
+// ReadPom reads a single pom.xml, resolving properties defined in the
+// file itself. Use ReadEffectivePom to take parent poms into account.
+func ReadPom(r io.Reader) (*Pom, error) {
+	px, err := decodePom(r)
+	if err != nil {
+		return nil, fmt.Errorf("pom.xml: %w", err)
+	}
+	return effectivePom([]*pomXML{px}, nil), nil
+}
+
+// ReadEffectivePom reads the pom name from fsys together with its parents
+// and computes the effective dependency list, the way Maven does before
+// resolution: properties and dependencyManagement are inherited, child
+// values win, and ${...} references are interpolated against the child
+// project. Each parent is looked for at its relativePath, then in a local
+// repository layout (group/path/artifact/version/artifact-version.pom)
+// rooted at fsys; BOMs imported by dependencyManagement are looked for in
+// the repository layout only. Poms that cannot be found are skipped and
+// show up as Unresolved properties or dependencies without a version.
+func ReadEffectivePom(fsys fs.FS, name string) (*Pom, error) {
+	name = path.Clean(name)
+	chain, err := loadPomChain(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	visited := map[string]bool{}
+	imports := func(g, a, v string) []*pomXML {
+		p := repositoryPath(g, a, v)
+		if visited[p] {
+			return nil
+		}
+		visited[p] = true
+		bom, err := loadPomChain(fsys, p)
+		if err != nil {
+			return nil
+		}
+		return bom
+	}
+	pom := effectivePom(chain, imports)
+	pom.Path = name
+	return pom, nil
+}
+
+// loadPomChain reads the pom name and the parents that can be found, child
+// first.
+func loadPomChain(fsys fs.FS, name string) ([]*pomXML, error) {
+	px, err := readPomFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	chain := []*pomXML{px}
+	dir := path.Dir(name)
+	for depth := 0; px.Parent != nil && depth < 32; depth++ {
+		parent, parentPath := findParent(fsys, dir, px)
+		if parent == nil {
+			break
+		}
+		chain = append(chain, parent)
+		px, dir = parent, path.Dir(parentPath)
+	}
+	return chain, nil
+}
+
+func readPomFile(fsys fs.FS, name string) (*pomXML, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	px, err := decodePom(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return px, nil
+}
+
+// findParent locates the parent of child, whose pom lives in dir.
+func findParent(fsys fs.FS, dir string, child *pomXML) (*pomXML, string) {
+	want := child.Parent
+	matches := func(p *pomXML) bool {
+		return p.GroupID == want.GroupID && p.ArtifactID == want.ArtifactID &&
+			(p.Version == want.Version || strings.Contains(want.Version, "${"))
+	}
+
+	rel := "../pom.xml"
+	if want.RelativePath != nil {
+		rel = strings.TrimSpace(*want.RelativePath)
+	}
+	if rel != "" {
+		candidate := path.Join(dir, rel)
+		if !strings.HasSuffix(candidate, ".xml") && !strings.HasSuffix(candidate, ".pom") {
+			candidate = path.Join(candidate, "pom.xml")
+		}
+		if p, err := readPomFile(fsys, candidate); err == nil && matches(p) {
+			return p, candidate
+		}
+	}
+	candidate := repositoryPath(want.GroupID, want.ArtifactID, want.Version)
+	if p, err := readPomFile(fsys, candidate); err == nil && matches(p) {
+		return p, candidate
+	}
+	return nil, ""
+}
+
+func repositoryPath(groupID, artifactID, version string) string {
+	return path.Join(strings.ReplaceAll(groupID, ".", "/"), artifactID, version, artifactID+"-"+version+".pom")
+}
+
+var rePomProperty = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// effectivePom merges chain (child first, then its ancestors) into a Pom.
+// imports loads the poms of an imported BOM; nil disables imports.
+func effectivePom(chain []*pomXML, imports func(g, a, v string) []*pomXML) *Pom {
+	child := chain[0]
+	pom := &Pom{
+		GroupID:    child.GroupID,
+		ArtifactID: child.ArtifactID,
+		Version:    child.Version,
+		Packaging:  child.Packaging,
+		Properties: map[string]string{},
+	}
+	if child.Parent != nil {
+		pom.Parent = &PomParent{
+			GroupID:      child.Parent.GroupID,
+			ArtifactID:   child.Parent.ArtifactID,
+			Version:      child.Parent.Version,
+			RelativePath: "../pom.xml",
+		}
+		if child.Parent.RelativePath != nil {
+			pom.Parent.RelativePath = strings.TrimSpace(*child.Parent.RelativePath)
+		}
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		for k, v := range chain[i].Properties {
+			pom.Properties[k] = v
+		}
+	}
+	props := map[string]string{}
+	for k, v := range pom.Properties {
+		props[k] = v
+	}
+	for _, prefix := range []string{"project.", "pom."} {
+		props[prefix+"groupId"] = child.GroupID
+		props[prefix+"artifactId"] = child.ArtifactID
+		props[prefix+"version"] = child.Version
+		props[prefix+"packaging"] = child.Packaging
+		if pom.Parent != nil {
+			props[prefix+"parent.groupId"] = pom.Parent.GroupID
+			props[prefix+"parent.artifactId"] = pom.Parent.ArtifactID
+			props[prefix+"parent.version"] = pom.Parent.Version
+		}
+	}
+
+	unresolved := map[string]bool{}
+	interpolate := func(s string) string {
+		for range 10 {
+			if !strings.Contains(s, "${") {
+				break
+			}
+			next := rePomProperty.ReplaceAllStringFunc(s, func(ref string) string {
+				if v, ok := props[ref[2:len(ref)-1]]; ok {
+					return v
+				}
+				return ref
+			})
+			if next == s {
+				break
+			}
+			s = next
+		}
+		for _, m := range rePomProperty.FindAllStringSubmatch(s, -1) {
+			unresolved[m[1]] = true
+		}
+		return s
+	}
+	resolve := func(d pomDependency) pomDependency {
+		d.GroupID = interpolate(d.GroupID)
+		d.ArtifactID = interpolate(d.ArtifactID)
+		d.Version = interpolate(d.Version)
+		d.Scope = interpolate(d.Scope)
+		d.Type = interpolate(d.Type)
+		d.Classifier = interpolate(d.Classifier)
+		return d
+	}
+
+	pom.Version = interpolate(pom.Version)
+	props["project.version"], props["pom.version"] = pom.Version, pom.Version
+
+	var managed []pomDependency
+	seenManaged := map[string]bool{}
+	addManaged := func(ds []pomDependency) {
+		for _, d := range ds {
+			if !seenManaged[d.key()] {
+				seenManaged[d.key()] = true
+				managed = append(managed, d)
+			}
+		}
+	}
+	var boms []pomDependency
+	for _, px := range chain {
+		for _, d := range px.DependencyManagement {
+			d = resolve(d)
+			if d.Scope == "import" && d.Type == "pom" {
+				boms = append(boms, d)
+				continue
+			}
+			addManaged([]pomDependency{d})
+		}
+	}
+	// Imported BOMs rank below every explicitly managed version.
+	for _, b := range boms {
+		if imports == nil {
+			continue
+		}
+		if bom := imports(b.GroupID, b.ArtifactID, b.Version); bom != nil {
+			for _, d := range effectivePom(bom, imports).DependencyManagement {
+				addManaged([]pomDependency{pomDependencyOf(d)})
+			}
+		}
+	}
+
+	managedBy := map[string]pomDependency{}
+	for _, d := range managed {
+		managedBy[d.key()] = d
+		pom.DependencyManagement = append(pom.DependencyManagement, pomDependencyEntry(d))
+	}
+
+	seen := map[string]bool{}
+	for _, px := range chain {
+		for _, d := range px.Dependencies {
+			d = resolve(d)
+			if seen[d.key()] {
+				continue
+			}
+			seen[d.key()] = true
+			if m, ok := managedBy[d.key()]; ok {
+				if d.Version == "" {
+					d.Version = m.Version
+				}
+				if d.Scope == "" {
+					d.Scope = m.Scope
+				}
+			}
+			if d.Scope == "" {
+				d.Scope = "compile"
+			}
+			pom.Dependencies = append(pom.Dependencies, pomDependencyEntry(d))
+		}
+	}
+
+	for k := range unresolved {
+		pom.Unresolved = append(pom.Unresolved, k)
+	}
+	sort.Strings(pom.Unresolved)
+	return pom
+}
+
+// rePomHardPin matches a hard version pin such as "[1.2.3]".
+var rePomHardPin = regexp.MustCompile(`^\s*\[\s*([^\s\[\](),${}]+)\s*\]\s*$`)
+
+// pomDependencyEntry converts a resolved pom dependency. Type and
+// classifier, when not the defaults, are appended to Name as Maven
+// coordinates do: "group:artifact:type:classifier".
+func pomDependencyEntry(d pomDependency) Dependency {
+	name := d.GroupID + ":" + d.ArtifactID
+	typ := d.Type
+	if typ == "" {
+		typ = "jar"
+	}
+	if typ != "jar" || d.Classifier != "" {
+		name += ":" + typ
+		if d.Classifier != "" {
+			name += ":" + d.Classifier
+		}
+	}
+	out := Dependency{Name: name, Constraint: d.Version, Kind: d.Scope}
+	if m := rePomHardPin.FindStringSubmatch(d.Version); m != nil {
+		out.Version = m[1]
+	}
+	return out
+}
+
+func pomDependencyOf(d Dependency) pomDependency {
+	parts := strings.SplitN(d.Name, ":", 4)
+	for len(parts) < 4 {
+		parts = append(parts, "")
+	}
+	return pomDependency{
+		GroupID: parts[0], ArtifactID: parts[1], Type: parts[2], Classifier: parts[3],
+		Version: d.Constraint, Scope: d.Kind,
+	}
+}
@@ -0,0 +1,223 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+const parentPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>2.0.0</version>
+  <packaging>pom</packaging>
+  <properties>
+    <guava.version>32.1.3-jre</guava.version>
+    <junit.version>5.10.0</junit.version>
+    <slf4j.version>2.0.9</slf4j.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>${guava.version}</version>
+      </dependency>
+      <dependency>
+        <groupId>org.junit.jupiter</groupId>
+        <artifactId>junit-jupiter</artifactId>
+        <version>${junit.version}</version>
+        <scope>test</scope>
+      </dependency>
+      <dependency>
+        <groupId>com.example</groupId>
+        <artifactId>bom</artifactId>
+        <version>1.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>${slf4j.version}</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+const bomPom = `<project>
+  <groupId>com.example</groupId>
+  <artifactId>bom</artifactId>
+  <version>1.0</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>[2.15,2.16)</version>
+      </dependency>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>31.0-jre</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+
+const childPom = `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>2.0.0</version>
+  </parent>
+  <artifactId>service</artifactId>
+  <properties>
+    <guava.version>33.0.0-jre</guava.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.junit.jupiter</groupId>
+      <artifactId>junit-jupiter</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>com.example</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.example</groupId>
+      <artifactId>native</artifactId>
+      <version>${native.version}</version>
+      <classifier>linux</classifier>
+    </dependency>
+  </dependencies>
+</project>`
+
+// ─── ReadPom ──────────────────────────────────────────────────────────────────
+
+func TestReadPom_SingleFile(t *testing.T) {
+	pom, err := ReadPom(strings.NewReader(parentPom))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pom.GroupID != "com.example" || pom.ArtifactID != "parent" || pom.Packaging != "pom" {
+		t.Errorf("coordinates: got %+v", pom)
+	}
+	want := []Dependency{{Name: "org.slf4j:slf4j-api", Constraint: "2.0.9", Kind: "compile"}}
+	if !reflect.DeepEqual(pom.Dependencies, want) {
+		t.Errorf("got %+v", pom.Dependencies)
+	}
+	if len(pom.DependencyManagement) != 2 || pom.DependencyManagement[0].Constraint != "32.1.3-jre" {
+		t.Errorf("managed: got %+v", pom.DependencyManagement)
+	}
+}
+
+func TestReadPom_HardPin(t *testing.T) {
+	pom, err := ReadPom(strings.NewReader(`<project>
+  <dependencies>
+    <dependency><groupId>a</groupId><artifactId>pinned</artifactId><version>[1.0]</version></dependency>
+    <dependency><groupId>a</groupId><artifactId>soft</artifactId><version>1.0</version></dependency>
+    <dependency><groupId>a</groupId><artifactId>range</artifactId><version>[1.0,2.0)</version></dependency>
+  </dependencies>
+</project>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Dependency{
+		{Name: "a:pinned", Constraint: "[1.0]", Kind: "compile", Version: "1.0"},
+		{Name: "a:soft", Constraint: "1.0", Kind: "compile"},
+		{Name: "a:range", Constraint: "[1.0,2.0)", Kind: "compile"},
+	}
+	if !reflect.DeepEqual(pom.Dependencies, want) {
+		t.Errorf("got  %+v\nwant %+v", pom.Dependencies, want)
+	}
+}
+
+func TestReadPom_Invalid(t *testing.T) {
+	if _, err := ReadPom(strings.NewReader("<project><dependencies>")); err == nil {
+		t.Error("expected error for truncated XML")
+	}
+}
+
+// ─── ReadEffectivePom ─────────────────────────────────────────────────────────
+
+func TestReadEffectivePom(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pom.xml":                                {Data: []byte(parentPom)},
+		"service/pom.xml":                        {Data: []byte(childPom)},
+		"com/example/bom/1.0/bom-1.0.pom":        {Data: []byte(bomPom)},
+		"com/example/parent/2.0.0/unrelated.pom": {Data: []byte("<project/>")},
+	}
+	pom, err := ReadEffectivePom(fsys, "service/pom.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pom.GroupID != "com.example" || pom.Version != "2.0.0" || pom.Parent == nil || pom.Parent.RelativePath != "../pom.xml" {
+		t.Errorf("inherited coordinates: got %+v", pom)
+	}
+	want := []Dependency{
+		{Name: "com.google.guava:guava", Constraint: "33.0.0-jre", Kind: "compile"},
+		{Name: "org.junit.jupiter:junit-jupiter", Constraint: "5.10.0", Kind: "test"},
+		{Name: "com.fasterxml.jackson.core:jackson-databind", Constraint: "[2.15,2.16)", Kind: "compile"},
+		{Name: "com.example:core", Constraint: "2.0.0", Kind: "compile"},
+		{Name: "com.example:native:jar:linux", Constraint: "${native.version}", Kind: "compile"},
+		{Name: "org.slf4j:slf4j-api", Constraint: "2.0.9", Kind: "compile"},
+	}
+	if !reflect.DeepEqual(pom.Dependencies, want) {
+		t.Fatalf("got  %+v\nwant %+v", pom.Dependencies, want)
+	}
+	if !reflect.DeepEqual(pom.Unresolved, []string{"native.version"}) {
+		t.Errorf("unresolved: got %v", pom.Unresolved)
+	}
+
+	parsed, err := pom.Dependencies[2].Parse(vars.StyleMaven)
+	if err != nil || len(parsed) != 1 || parsed[0][0] != (vars.Constraint{Op: ">=", Ver: "2.15.0"}) {
+		t.Errorf("range parse: got %v, %v", parsed, err)
+	}
+
+	got := map[string]Status{}
+	lock := []Package{{Name: "com.google.guava:guava", Version: "33.0.0-jre"}}
+	for _, r := range Verify(vars.StyleMaven, pom.Dependencies, lock) {
+		got[r.Name] = r.Status
+	}
+	if got["com.google.guava:guava"] != StatusOK || got["com.example:native:jar:linux"] != StatusUnparsable {
+		t.Errorf("verify: got %v", got)
+	}
+}
+
+func TestReadEffectivePom_RepositoryParent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/pom.xml": {Data: []byte(strings.Replace(childPom, "<parent>", "<parent><relativePath/>", 1))},
+		"com/example/parent/2.0.0/parent-2.0.0.pom": {Data: []byte(parentPom)},
+	}
+	pom, err := ReadEffectivePom(fsys, "app/pom.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pom.Parent.RelativePath != "" {
+		t.Errorf("empty relativePath: got %q", pom.Parent.RelativePath)
+	}
+	// The BOM is missing, so jackson has no version.
+	if d := pom.Dependencies[2]; d.Name != "com.fasterxml.jackson.core:jackson-databind" || d.Constraint != "" {
+		t.Errorf("got %+v", d)
+	}
+	if d := pom.Dependencies[1]; d.Constraint != "5.10.0" || d.Kind != "test" {
+		t.Errorf("managed from repository parent: got %+v", d)
+	}
+}
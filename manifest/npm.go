@@ -0,0 +1,291 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+/* ------------------------- */
+/*     package.json          */
+/* ------------------------- */
+
+// NpmManifest is the dependency-related content of a package.json.
+type NpmManifest struct {
+	Name    string
+	Version string
+	// Dependencies holds dependencies, devDependencies, peerDependencies and
+	// optionalDependencies, tagged with Kind and sorted by kind then name.
+	Dependencies []Dependency
+	// Overrides flattens the overrides tree; nested overrides are named
+	// "parent>child".
+	Overrides []Dependency
+	// Engines holds the engines field, e.g. {Name: "node", Constraint: ">=18"}.
+	Engines []Dependency
+}
+
+var npmDependencyKinds = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+// ReadPackageJSON reads a package.json.
+func ReadPackageJSON(r io.Reader) (*NpmManifest, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("package.json: %w", err)
+	}
+
+	m := &NpmManifest{}
+	_ = json.Unmarshal(raw["name"], &m.Name)
+	_ = json.Unmarshal(raw["version"], &m.Version)
+
+	for _, kind := range npmDependencyKinds {
+		deps, err := stringMap(raw[kind])
+		if err != nil {
+			return nil, fmt.Errorf("package.json %s: %w", kind, err)
+		}
+		m.Dependencies = append(m.Dependencies, sortedDependencies(deps, kind)...)
+	}
+
+	engines, err := stringMap(raw["engines"])
+	if err != nil {
+		return nil, fmt.Errorf("package.json engines: %w", err)
+	}
+	m.Engines = sortedDependencies(engines, "engines")
+
+	if len(raw["overrides"]) > 0 {
+		var tree map[string]any
+		if err := json.Unmarshal(raw["overrides"], &tree); err != nil {
+			return nil, fmt.Errorf("package.json overrides: %w", err)
+		}
+		m.Overrides = flattenOverrides(tree, "")
+	}
+	return m, nil
+}
+
+func stringMap(data json.RawMessage) (map[string]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var m map[string]string
+	err := json.Unmarshal(data, &m)
+	return m, err
+}
+
+func sortedDependencies(m map[string]string, kind string) []Dependency {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	out := make([]Dependency, 0, len(names))
+	for _, n := range names {
+		out = append(out, Dependency{Name: n, Constraint: m[n], Kind: kind})
+	}
+	return out
+}
+
+// flattenOverrides walks an npm overrides object. A nested object's "." key
+// overrides the parent package itself.
+func flattenOverrides(tree map[string]any, parent string) []Dependency {
+	names := make([]string, 0, len(tree))
+	for n := range tree {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var out []Dependency
+	for _, n := range names {
+		name := n
+		if parent != "" {
+			name = parent + ">" + n
+		}
+		switch v := tree[n].(type) {
+		case string:
+			if n == "." {
+				out = append(out, Dependency{Name: parent, Constraint: v, Kind: "overrides"})
+			} else {
+				out = append(out, Dependency{Name: name, Constraint: v, Kind: "overrides"})
+			}
+		case map[string]any:
+			out = append(out, flattenOverrides(v, name)...)
+		}
+	}
+	return out
+}
+
+// npmAliasTarget returns the real package name of an "npm:name@range" alias,
+// or "" when constraint is not an alias.
+func npmAliasTarget(constraint string) string {
+	s, ok := strings.CutPrefix(strings.TrimSpace(constraint), "npm:")
+	if !ok {
+		return ""
+	}
+	name, _ := splitNpmSpec(s)
+	return name
+}
+
+// splitNpmSpec splits "name@range" into its parts. The name may be scoped
+// and the range may itself contain "@", as in "foo@npm:bar@^1.0.0".
+func splitNpmSpec(spec string) (string, string) {
+	if spec == "" {
+		return "", ""
+	}
+	at := strings.Index(spec[1:], "@")
+	if at < 0 {
+		return spec, ""
+	}
+	return spec[:at+1], spec[at+2:]
+}
+
+/* ------------------------- */
+/*     package-lock.json     */
+/* ------------------------- */
+
+type npmLockEntry struct {
+	Version      string                  `json:"version"`
+	Resolved     string                  `json:"resolved"`
+	Link         bool                    `json:"link"`
+	Dependencies map[string]npmLockEntry `json:"dependencies"`
+}
+
+type npmLockfile struct {
+	LockfileVersion int                     `json:"lockfileVersion"`
+	Packages        map[string]npmLockEntry `json:"packages"`
+	Dependencies    map[string]npmLockEntry `json:"dependencies"`
+}
+
+// ReadPackageLock reads a package-lock.json or npm-shrinkwrap.json of
+// lockfile version 1, 2 or 3. Version 2 and 3 files are read from their
+// "packages" section; version 1 files from the nested "dependencies" tree.
+// Linked workspace entries are skipped.
+func ReadPackageLock(r io.Reader) ([]Package, error) {
+	var lf npmLockfile
+	if err := json.NewDecoder(r).Decode(&lf); err != nil {
+		return nil, fmt.Errorf("package-lock.json: %w", err)
+	}
+
+	var out []Package
+	if len(lf.Packages) > 0 {
+		keys := make([]string, 0, len(lf.Packages))
+		for k := range lf.Packages {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			e := lf.Packages[k]
+			i := strings.LastIndex(k, "node_modules/")
+			if i < 0 || e.Link || e.Version == "" {
+				continue
+			}
+			out = append(out, Package{
+				Name:    k[i+len("node_modules/"):],
+				Version: e.Version,
+				Nested:  i > 0,
+				Source:  e.Resolved,
+			})
+		}
+		return out, nil
+	}
+	return appendNpmV1(out, lf.Dependencies, false), nil
+}
+
+func appendNpmV1(out []Package, deps map[string]npmLockEntry, nested bool) []Package {
+	names := make([]string, 0, len(deps))
+	for n := range deps {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		e := deps[n]
+		if e.Version != "" {
+			out = append(out, Package{Name: n, Version: e.Version, Nested: nested, Source: e.Resolved})
+		}
+		out = appendNpmV1(out, e.Dependencies, true)
+	}
+	return out
+}
+
+/* ------------------------- */
+/*        yarn.lock          */
+/* ------------------------- */
+
+// ReadYarnLock reads a yarn.lock written by Yarn Classic (v1) or Yarn Berry.
+// Each entry's Requested holds its specifiers with the default "npm:"
+// protocol removed, so "lodash@npm:^4.17.0" becomes "lodash@^4.17.0".
+func ReadYarnLock(r io.Reader) ([]Package, error) {
+	var out []Package
+	var cur *Package
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if line[0] != ' ' {
+			if cur != nil && cur.Version != "" {
+				out = append(out, *cur)
+			}
+			cur = nil
+			header := strings.TrimSuffix(trimmed, ":")
+			if header == "__metadata" {
+				continue
+			}
+			cur = &Package{}
+			for _, spec := range strings.Split(header, ",") {
+				spec = strings.Trim(strings.TrimSpace(spec), `"`)
+				if spec == "" {
+					continue
+				}
+				name, rng := splitNpmSpec(spec)
+				if rest, ok := strings.CutPrefix(rng, "npm:"); ok && !strings.Contains(rest, "@") {
+					rng = rest
+				}
+				cur.Name = name
+				cur.Requested = append(cur.Requested, name+"@"+rng)
+			}
+			continue
+		}
+
+		if cur == nil || strings.HasPrefix(line, "    ") {
+			continue
+		}
+		key, val := yarnField(trimmed)
+		switch key {
+		case "version":
+			cur.Version = val
+		case "resolved", "resolution":
+			if cur.Source == "" {
+				cur.Source = val
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("yarn.lock: %w", err)
+	}
+	if cur != nil && cur.Version != "" {
+		out = append(out, *cur)
+	}
+	return out, nil
+}
+
+// yarnField splits `version "1.2.3"` (v1) or `version: 1.2.3` (Berry).
+func yarnField(s string) (string, string) {
+	key, val, ok := strings.Cut(s, ":")
+	if !ok || strings.Contains(key, " ") {
+		key, val, _ = strings.Cut(s, " ")
+	}
+	return yarnUnquote(strings.TrimSpace(key)), yarnUnquote(strings.TrimSpace(val))
+}
+
+func yarnUnquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,271 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+const packageJSON = `{
+  "name": "app",
+  "version": "1.0.0",
+  "dependencies": {
+    "lodash": "^4.17.0",
+    "react": "~18.2.0",
+    "my-fork": "npm:left-pad@^1.3.0",
+    "local": "file:../local",
+    "gone": "^1.0.0"
+  },
+  "devDependencies": { "jest": ">=29.0.0 <30.0.0" },
+  "peerDependencies": { "react-dom": "^18.0.0" },
+  "optionalDependencies": { "fsevents": "^2.3.2" },
+  "overrides": {
+    "semver": "7.5.4",
+    "foo": { ".": "1.0.0", "bar": "2.0.0" }
+  },
+  "engines": { "node": ">=18", "npm": ">=9" }
+}`
+
+const packageLockV3 = `{
+  "name": "app",
+  "lockfileVersion": 3,
+  "packages": {
+    "": { "name": "app", "version": "1.0.0" },
+    "node_modules/lodash": { "version": "4.17.21", "resolved": "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz" },
+    "node_modules/react": { "version": "18.3.1" },
+    "node_modules/my-fork": { "name": "left-pad", "version": "1.3.0" },
+    "node_modules/jest": { "version": "29.7.0" },
+    "node_modules/react-dom": { "version": "18.3.1" },
+    "node_modules/fsevents": { "version": "2.3.3" },
+    "node_modules/jest/node_modules/lodash": { "version": "3.10.1" },
+    "packages/local": { "version": "0.0.1" },
+    "node_modules/local": { "resolved": "packages/local", "link": true }
+  }
+}`
+
+const packageLockV1 = `{
+  "lockfileVersion": 1,
+  "dependencies": {
+    "lodash": { "version": "4.17.21" },
+    "jest": {
+      "version": "29.7.0",
+      "dependencies": { "lodash": { "version": "3.10.1" } }
+    }
+  }
+}`
+
+const yarnLockV1 = `# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4":
+  version "7.22.13"
+  resolved "https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.22.13.tgz#sha"
+  dependencies:
+    "@babel/highlight" "^7.22.13"
+
+lodash@^4.17.0:
+  version "4.17.21"
+
+my-fork@npm:left-pad@^1.3.0:
+  version "1.3.0"
+`
+
+const yarnLockBerry = `__metadata:
+  version: 6
+  cacheKey: 8
+
+"lodash@npm:^4.17.0, lodash@npm:^4.17.21":
+  version: 4.17.21
+  resolution: "lodash@npm:4.17.21"
+  dependencies:
+    foo: ^1.0.0
+  checksum: abc
+  languageName: node
+  linkType: hard
+`
+
+// ─── ReadPackageJSON ──────────────────────────────────────────────────────────
+
+func TestReadPackageJSON(t *testing.T) {
+	m, err := ReadPackageJSON(strings.NewReader(packageJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Name != "app" || m.Version != "1.0.0" {
+		t.Errorf("name/version: got %q %q", m.Name, m.Version)
+	}
+	if len(m.Dependencies) != 8 {
+		t.Fatalf("dependencies: expected 8, got %d: %+v", len(m.Dependencies), m.Dependencies)
+	}
+	if d := m.Dependencies[0]; !reflect.DeepEqual(d, Dependency{Name: "gone", Constraint: "^1.0.0", Kind: "dependencies"}) {
+		t.Errorf("first dependency: got %+v", d)
+	}
+	if d := m.Dependencies[7]; d.Name != "fsevents" || d.Kind != "optionalDependencies" {
+		t.Errorf("last dependency: got %+v", d)
+	}
+	wantEngines := []Dependency{
+		{Name: "node", Constraint: ">=18", Kind: "engines"},
+		{Name: "npm", Constraint: ">=9", Kind: "engines"},
+	}
+	if !reflect.DeepEqual(m.Engines, wantEngines) {
+		t.Errorf("engines: got %+v", m.Engines)
+	}
+	wantOverrides := []Dependency{
+		{Name: "foo", Constraint: "1.0.0", Kind: "overrides"},
+		{Name: "foo>bar", Constraint: "2.0.0", Kind: "overrides"},
+		{Name: "semver", Constraint: "7.5.4", Kind: "overrides"},
+	}
+	if !reflect.DeepEqual(m.Overrides, wantOverrides) {
+		t.Errorf("overrides: got %+v", m.Overrides)
+	}
+}
+
+func TestReadPackageJSON_Invalid(t *testing.T) {
+	if _, err := ReadPackageJSON(strings.NewReader(`{"dependencies": ["a"]}`)); err == nil {
+		t.Error("expected error for non-object dependencies")
+	}
+	if _, err := ReadPackageJSON(strings.NewReader(`{`)); err == nil {
+		t.Error("expected error for truncated JSON")
+	}
+}
+
+// ─── ReadPackageLock ──────────────────────────────────────────────────────────
+
+func TestReadPackageLock_V3(t *testing.T) {
+	lock, err := ReadPackageLock(strings.NewReader(packageLockV3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lock) != 7 {
+		t.Fatalf("expected 7 packages, got %d: %+v", len(lock), lock)
+	}
+	nested := 0
+	for _, p := range lock {
+		if p.Nested {
+			nested++
+			if p.Name != "lodash" || p.Version != "3.10.1" {
+				t.Errorf("nested entry: got %+v", p)
+			}
+		}
+	}
+	if nested != 1 {
+		t.Errorf("expected 1 nested entry, got %d", nested)
+	}
+}
+
+func TestReadPackageLock_V1(t *testing.T) {
+	lock, err := ReadPackageLock(strings.NewReader(packageLockV1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Package{
+		{Name: "jest", Version: "29.7.0"},
+		{Name: "lodash", Version: "3.10.1", Nested: true},
+		{Name: "lodash", Version: "4.17.21"},
+	}
+	if !reflect.DeepEqual(lock, want) {
+		t.Errorf("got %+v", lock)
+	}
+}
+
+// ─── ReadYarnLock ─────────────────────────────────────────────────────────────
+
+func TestReadYarnLock_V1(t *testing.T) {
+	lock, err := ReadYarnLock(strings.NewReader(yarnLockV1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lock) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", lock)
+	}
+	if p := lock[0]; p.Name != "@babel/code-frame" || p.Version != "7.22.13" ||
+		!reflect.DeepEqual(p.Requested, []string{"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4"}) {
+		t.Errorf("scoped entry: got %+v", p)
+	}
+	if p := lock[2]; p.Name != "my-fork" || !reflect.DeepEqual(p.Requested, []string{"my-fork@npm:left-pad@^1.3.0"}) {
+		t.Errorf("alias entry: got %+v", p)
+	}
+}
+
+func TestReadYarnLock_Berry(t *testing.T) {
+	lock, err := ReadYarnLock(strings.NewReader(yarnLockBerry))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lock) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", lock)
+	}
+	p := lock[0]
+	if p.Version != "4.17.21" || p.Source != "lodash@npm:4.17.21" {
+		t.Errorf("got %+v", p)
+	}
+	if !reflect.DeepEqual(p.Requested, []string{"lodash@^4.17.0", "lodash@^4.17.21"}) {
+		t.Errorf("requested: got %v", p.Requested)
+	}
+}
+
+// ─── Verify (npm) ─────────────────────────────────────────────────────────────
+
+func TestVerify_NPM(t *testing.T) {
+	m, _ := ReadPackageJSON(strings.NewReader(packageJSON))
+	lock, _ := ReadPackageLock(strings.NewReader(packageLockV3))
+
+	got := map[string]Result{}
+	for _, r := range Verify(vars.StyleNPM, m.Dependencies, lock) {
+		got[r.Name] = r
+	}
+
+	want := map[string]Status{
+		"lodash":    StatusOK,
+		"react":     StatusDrift,
+		"my-fork":   StatusOK,
+		"local":     StatusUnsupported,
+		"gone":      StatusMissing,
+		"jest":      StatusOK,
+		"react-dom": StatusOK,
+		"fsevents":  StatusOK,
+	}
+	for name, status := range want {
+		if got[name].Status != status {
+			t.Errorf("%s: got %q (%s), want %q", name, got[name].Status, got[name].Detail, status)
+		}
+	}
+	if got["lodash"].Locked != "4.17.21" {
+		t.Errorf("lodash should resolve to the top-level entry, got %q", got["lodash"].Locked)
+	}
+	if got["my-fork"].Alias != "left-pad" {
+		t.Errorf("alias: got %q", got["my-fork"].Alias)
+	}
+	if got["react"].Detail != "locked 18.3.1 does not satisfy ~18.2.0" {
+		t.Errorf("drift detail: got %q", got["react"].Detail)
+	}
+}
+
+func TestVerify_NPMDistTag(t *testing.T) {
+	lock := []Package{{Name: "react", Version: "18.3.1"}, {Name: "next", Version: "14.2.0"}, {Name: "left-pad", Version: "1.3.0"}}
+	deps := []Dependency{
+		{Name: "react", Constraint: "latest"},
+		{Name: "next", Constraint: "canary"},
+		{Name: "pad", Constraint: "npm:left-pad@beta", Package: "left-pad"},
+	}
+	for _, r := range Verify(vars.StyleNPM, deps, lock) {
+		if r.Status != StatusUnresolved {
+			t.Errorf("%s: got %q (%s), want %q", r.Name, r.Status, r.Detail, StatusUnresolved)
+		}
+	}
+	if r := Verify(vars.StyleNPM, deps[:1], nil)[0]; r.Status != StatusMissing {
+		t.Errorf("unlocked tag: got %q, want %q", r.Status, StatusMissing)
+	}
+}
+
+func TestVerify_YarnRequested(t *testing.T) {
+	lock, _ := ReadYarnLock(strings.NewReader(yarnLockV1))
+	deps := []Dependency{{Name: "@babel/code-frame", Constraint: "^7.10.4"}}
+	rs := Verify(vars.StyleNPM, deps, lock)
+	if rs[0].Status != StatusOK || rs[0].Locked != "7.22.13" {
+		t.Errorf("got %+v", rs[0])
+	}
+}
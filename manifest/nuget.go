@@ -0,0 +1,321 @@
+package manifest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+/* ------------------------- */
+/*   .csproj / .props        */
+/* ------------------------- */
+
+// MSBuildProject is the package-related content of an MSBuild file: a
+// .csproj (or .fsproj, .vbproj), Directory.Packages.props or
+// Directory.Build.props. Values keep their original text; $(Property)
+// references are resolved against the file's own PropertyGroups.
+type MSBuildProject struct {
+	Properties map[string]string
+	// CentralPackageManagement reports ManagePackageVersionsCentrally=true.
+	CentralPackageManagement bool
+	PackageReferences        []PackageReference
+	// PackageVersions holds the central <PackageVersion> items.
+	PackageVersions []Dependency
+	// GlobalPackageReferences holds <GlobalPackageReference> items, which
+	// apply to every project using the central file.
+	GlobalPackageReferences []Dependency
+}
+
+// PackageReference is one <PackageReference> item.
+type PackageReference struct {
+	Name            string
+	Version         string
+	VersionOverride string
+}
+
+type msbuildXML struct {
+	PropertyGroups []xmlProperties `xml:"PropertyGroup"`
+	ItemGroups     []struct {
+		PackageReference       []msbuildItem `xml:"PackageReference"`
+		PackageVersion         []msbuildItem `xml:"PackageVersion"`
+		GlobalPackageReference []msbuildItem `xml:"GlobalPackageReference"`
+	} `xml:"ItemGroup"`
+}
+
+// msbuildItem accepts metadata both as attributes and as child elements.
+type msbuildItem struct {
+	Include             string `xml:"Include,attr"`
+	Update              string `xml:"Update,attr"`
+	Version             string `xml:"Version,attr"`
+	VersionOverride     string `xml:"VersionOverride,attr"`
+	VersionElem         string `xml:"Version"`
+	VersionOverrideElem string `xml:"VersionOverride"`
+}
+
+func (it msbuildItem) name() string {
+	if it.Include != "" {
+		return strings.TrimSpace(it.Include)
+	}
+	return strings.TrimSpace(it.Update)
+}
+
+var reMSBuildProperty = regexp.MustCompile(`\$\(([^)]+)\)`)
+
+// ReadMSBuild reads a .csproj, Directory.Packages.props or other MSBuild
+// file. Conditions are ignored: every item and property is read.
+func ReadMSBuild(r io.Reader) (*MSBuildProject, error) {
+	var x msbuildXML
+	if err := xml.NewDecoder(r).Decode(&x); err != nil {
+		return nil, fmt.Errorf("msbuild: %w", err)
+	}
+	p := &MSBuildProject{Properties: map[string]string{}}
+	for _, g := range x.PropertyGroups {
+		for k, v := range g {
+			p.Properties[k] = v
+		}
+	}
+	p.CentralPackageManagement = strings.EqualFold(p.property("ManagePackageVersionsCentrally"), "true")
+
+	for _, g := range x.ItemGroups {
+		for _, it := range g.PackageReference {
+			p.PackageReferences = append(p.PackageReferences, PackageReference{
+				Name:            it.name(),
+				Version:         p.expand(firstNonEmpty(it.Version, it.VersionElem)),
+				VersionOverride: p.expand(firstNonEmpty(it.VersionOverride, it.VersionOverrideElem)),
+			})
+		}
+		for _, it := range g.PackageVersion {
+			p.PackageVersions = append(p.PackageVersions, Dependency{
+				Name: it.name(), Constraint: p.expand(firstNonEmpty(it.Version, it.VersionElem)), Kind: "PackageVersion",
+			})
+		}
+		for _, it := range g.GlobalPackageReference {
+			p.GlobalPackageReferences = append(p.GlobalPackageReferences, Dependency{
+				Name: it.name(), Constraint: p.expand(firstNonEmpty(it.Version, it.VersionElem)), Kind: "GlobalPackageReference",
+			})
+		}
+	}
+	return p, nil
+}
+
+// property looks a property up case-insensitively, as MSBuild does.
+func (p *MSBuildProject) property(name string) string {
+	for k, v := range p.Properties {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+func (p *MSBuildProject) expand(s string) string {
+	s = strings.TrimSpace(s)
+	for range 10 {
+		next := reMSBuildProperty.ReplaceAllStringFunc(s, func(ref string) string {
+			if v := p.property(ref[2 : len(ref)-1]); v != "" {
+				return v
+			}
+			return ref
+		})
+		if next == s {
+			break
+		}
+		s = next
+	}
+	return s
+}
+
+// centralVersion returns the PackageVersion of name; NuGet ids are
+// case-insensitive.
+func (p *MSBuildProject) centralVersion(name string) (string, bool) {
+	for _, d := range p.PackageVersions {
+		if strings.EqualFold(d.Name, name) {
+			return d.Constraint, true
+		}
+	}
+	return "", false
+}
+
+// Dependencies returns the project's package references with their
+// effective version: VersionOverride, else Version, else the PackageVersion
+// of central (Directory.Packages.props, may be nil). Global package
+// references of central are appended. Kind tells where the version came
+// from: "VersionOverride", "PackageReference", "PackageVersion" or
+// "GlobalPackageReference".
+//
+// NuGet reads a bare version "1.2.3" as a minimum; it is written as
+// "[1.2.3, )" so that parser.ParseNuGet, which treats bare versions as
+// exact, agrees with NuGet.
+func (p *MSBuildProject) Dependencies(central *MSBuildProject) []Dependency {
+	var out []Dependency
+	for _, r := range p.PackageReferences {
+		d := Dependency{Name: r.Name, Kind: "PackageReference"}
+		switch {
+		case r.VersionOverride != "":
+			d.Constraint, d.Kind = r.VersionOverride, "VersionOverride"
+		case r.Version != "":
+			d.Constraint = r.Version
+		case central != nil:
+			if v, ok := central.centralVersion(r.Name); ok {
+				d.Constraint, d.Kind = v, "PackageVersion"
+			}
+		}
+		if central != nil {
+			d.Constraint = central.expand(d.Constraint)
+		}
+		d.Constraint = nugetMinimum(d.Constraint)
+		out = append(out, d)
+	}
+	if central != nil {
+		for _, g := range central.GlobalPackageReferences {
+			g.Constraint = nugetMinimum(g.Constraint)
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// OverrideMismatches reports, with StatusDrift, every VersionOverride in
+// projects that differs from the central PackageVersion of the same
+// package. Each result carries the central version as its constraint and
+// the override as Locked.
+func OverrideMismatches(central *MSBuildProject, projects ...*MSBuildProject) []Result {
+	var out []Result
+	for _, p := range projects {
+		for _, r := range p.PackageReferences {
+			if r.VersionOverride == "" {
+				continue
+			}
+			cv, ok := central.centralVersion(r.Name)
+			if !ok || strings.EqualFold(strings.TrimSpace(cv), strings.TrimSpace(r.VersionOverride)) {
+				continue
+			}
+			out = append(out, Result{
+				Dependency: Dependency{Name: r.Name, Constraint: nugetMinimum(cv), Kind: "VersionOverride"},
+				Locked:     r.VersionOverride,
+				Status:     StatusDrift,
+				Detail:     fmt.Sprintf("VersionOverride %s differs from central version %s", r.VersionOverride, cv),
+			})
+		}
+	}
+	return out
+}
+
+var reNuGetBare = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*(-[0-9A-Za-z.-]+)?$`)
+
+func nugetMinimum(s string) string {
+	s = strings.TrimSpace(s)
+	if reNuGetBare.MatchString(s) {
+		return "[" + s + ", )"
+	}
+	return s
+}
+
+func firstNonEmpty(ss ...string) string {
+	for _, s := range ss {
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+/* ------------------------- */
+/*      packages.config      */
+/* ------------------------- */
+
+// ReadPackagesConfig reads a legacy packages.config. Each package is
+// pinned: Version is the installed version and Constraint the
+// allowedVersions range, or the version itself when there is none. Kind
+// is the targetFramework.
+func ReadPackagesConfig(r io.Reader) ([]Dependency, error) {
+	var x struct {
+		Packages []struct {
+			ID              string `xml:"id,attr"`
+			Version         string `xml:"version,attr"`
+			AllowedVersions string `xml:"allowedVersions,attr"`
+			TargetFramework string `xml:"targetFramework,attr"`
+		} `xml:"package"`
+	}
+	if err := xml.NewDecoder(r).Decode(&x); err != nil {
+		return nil, fmt.Errorf("packages.config: %w", err)
+	}
+	out := make([]Dependency, 0, len(x.Packages))
+	for _, p := range x.Packages {
+		out = append(out, Dependency{
+			Name:       p.ID,
+			Constraint: firstNonEmpty(p.AllowedVersions, p.Version),
+			Kind:       p.TargetFramework,
+			Version:    p.Version,
+		})
+	}
+	return out, nil
+}
+
+/* ------------------------- */
+/*    packages.lock.json     */
+/* ------------------------- */
+
+// NuGetLock is the content of a packages.lock.json.
+type NuGetLock struct {
+	// Packages holds every resolved package once per version, across
+	// target frameworks. Project references are left out.
+	Packages []Package
+	// Direct holds the direct dependencies with their requested range as
+	// Constraint and their resolved version as Version. Kind is the target
+	// framework.
+	Direct []Dependency
+	// Requirements holds the ranges the resolved packages place on their
+	// own dependencies. Kind is the requiring package as "name (version)".
+	Requirements []Dependency
+}
+
+type nugetLockEntry struct {
+	Type         string            `json:"type"`
+	Requested    string            `json:"requested"`
+	Resolved     string            `json:"resolved"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// ReadPackagesLock reads a packages.lock.json. Bare versions in dependency
+// ranges are minimums in NuGet and are written as "[1.2.3, )".
+func ReadPackagesLock(r io.Reader) (*NuGetLock, error) {
+	var x struct {
+		Dependencies map[string]map[string]nugetLockEntry `json:"dependencies"`
+	}
+	if err := json.NewDecoder(r).Decode(&x); err != nil {
+		return nil, fmt.Errorf("packages.lock.json: %w", err)
+	}
+
+	lock := &NuGetLock{}
+	seen := map[string]bool{}
+	for _, fw := range sortedKeys(x.Dependencies) {
+		entries := x.Dependencies[fw]
+		for _, name := range sortedKeys(entries) {
+			e := entries[name]
+			if e.Type == "Project" || e.Resolved == "" {
+				continue
+			}
+			if e.Type == "Direct" {
+				lock.Direct = append(lock.Direct, Dependency{
+					Name: name, Constraint: nugetMinimum(e.Requested), Kind: fw, Version: e.Resolved,
+				})
+			}
+			id := name + " (" + e.Resolved + ")"
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			lock.Packages = append(lock.Packages, Package{Name: name, Version: e.Resolved})
+			for _, dep := range sortedKeys(e.Dependencies) {
+				lock.Requirements = append(lock.Requirements, Dependency{
+					Name: dep, Constraint: nugetMinimum(e.Dependencies[dep]), Kind: id,
+				})
+			}
+		}
+	}
+	return lock, nil
+}
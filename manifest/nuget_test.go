@@ -0,0 +1,188 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+const directoryPackagesProps = `<Project>
+  <PropertyGroup>
+    <ManagePackageVersionsCentrally>true</ManagePackageVersionsCentrally>
+    <SerilogVersion>3.1.1</SerilogVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageVersion Include="Newtonsoft.Json" Version="13.0.3" />
+    <PackageVersion Include="Serilog" Version="$(SerilogVersion)" />
+    <PackageVersion Include="xunit" Version="[2.6.0, 3.0.0)" />
+    <GlobalPackageReference Include="Nerdbank.GitVersioning" Version="3.6.133" />
+  </ItemGroup>
+</Project>`
+
+const csproj = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="newtonsoft.json" VersionOverride="12.0.3" />
+    <PackageReference Include="Serilog" />
+    <PackageReference Include="xunit" VersionOverride="[2.6.0, 3.0.0)" />
+    <PackageReference Include="Dapper">
+      <Version>2.1.24</Version>
+    </PackageReference>
+  </ItemGroup>
+</Project>`
+
+const packagesLockJSON = `{
+  "version": 1,
+  "dependencies": {
+    "net8.0": {
+      "Dapper": {
+        "type": "Direct",
+        "requested": "[2.1.24, )",
+        "resolved": "2.1.28",
+        "contentHash": "x"
+      },
+      "Serilog": {
+        "type": "Direct",
+        "requested": "[3.1.1, )",
+        "resolved": "3.0.0"
+      },
+      "Newtonsoft.Json": {
+        "type": "Transitive",
+        "resolved": "13.0.3"
+      },
+      "Microsoft.Extensions.Logging": {
+        "type": "Transitive",
+        "resolved": "8.0.0",
+        "dependencies": {
+          "Newtonsoft.Json": "13.0.1",
+          "Serilog": "[3.0.0]"
+        }
+      },
+      "mylib": {
+        "type": "Project"
+      }
+    },
+    "net6.0": {
+      "Dapper": {
+        "type": "Direct",
+        "requested": "[2.1.24, )",
+        "resolved": "2.1.28"
+      }
+    }
+  }
+}`
+
+// ─── ReadMSBuild ──────────────────────────────────────────────────────────────
+
+func TestReadMSBuild_CentralPackages(t *testing.T) {
+	central, err := ReadMSBuild(strings.NewReader(directoryPackagesProps))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !central.CentralPackageManagement {
+		t.Error("ManagePackageVersionsCentrally not detected")
+	}
+	if v, _ := central.centralVersion("serilog"); v != "3.1.1" {
+		t.Errorf("property not expanded: got %q", v)
+	}
+
+	project, err := ReadMSBuild(strings.NewReader(csproj))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Dependency{
+		{Name: "newtonsoft.json", Constraint: "[12.0.3, )", Kind: "VersionOverride"},
+		{Name: "Serilog", Constraint: "[3.1.1, )", Kind: "PackageVersion"},
+		{Name: "xunit", Constraint: "[2.6.0, 3.0.0)", Kind: "VersionOverride"},
+		{Name: "Dapper", Constraint: "[2.1.24, )", Kind: "PackageReference"},
+		{Name: "Nerdbank.GitVersioning", Constraint: "[3.6.133, )", Kind: "GlobalPackageReference"},
+	}
+	if got := project.Dependencies(central); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	mismatches := OverrideMismatches(central, project)
+	if len(mismatches) != 1 {
+		t.Fatalf("expected 1 mismatch, got %+v", mismatches)
+	}
+	if m := mismatches[0]; m.Name != "newtonsoft.json" || m.Locked != "12.0.3" || m.Status != StatusDrift ||
+		m.Detail != "VersionOverride 12.0.3 differs from central version 13.0.3" {
+		t.Errorf("got %+v", m)
+	}
+}
+
+func TestReadMSBuild_Invalid(t *testing.T) {
+	if _, err := ReadMSBuild(strings.NewReader("<Project><ItemGroup>")); err == nil {
+		t.Error("expected error for truncated XML")
+	}
+}
+
+// ─── ReadPackagesConfig ───────────────────────────────────────────────────────
+
+func TestReadPackagesConfig(t *testing.T) {
+	deps, err := ReadPackagesConfig(strings.NewReader(`<?xml version="1.0" encoding="utf-8"?>
+<packages>
+  <package id="Newtonsoft.Json" version="12.0.3" targetFramework="net472" />
+  <package id="NUnit" version="3.14.0" allowedVersions="[3,4)" targetFramework="net472" />
+  <package id="Moq" version="4.20.0" allowedVersions="[4.0,4.10)" />
+</packages>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := map[string]Status{}
+	for _, r := range Verify(vars.StyleNuGet, deps, nil) {
+		got[r.Name] = r.Status
+	}
+	want := map[string]Status{"Newtonsoft.Json": StatusOK, "NUnit": StatusOK, "Moq": StatusDrift}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// ─── ReadPackagesLock ─────────────────────────────────────────────────────────
+
+func TestReadPackagesLock(t *testing.T) {
+	lock, err := ReadPackagesLock(strings.NewReader(packagesLockJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lock.Packages) != 4 {
+		t.Errorf("expected 4 packages (deduplicated, no projects), got %+v", lock.Packages)
+	}
+	if len(lock.Direct) != 3 || lock.Direct[0].Kind != "net6.0" {
+		t.Errorf("direct: got %+v", lock.Direct)
+	}
+	wantReqs := []Dependency{
+		{Name: "Newtonsoft.Json", Constraint: "[13.0.1, )", Kind: "Microsoft.Extensions.Logging (8.0.0)"},
+		{Name: "Serilog", Constraint: "[3.0.0]", Kind: "Microsoft.Extensions.Logging (8.0.0)"},
+	}
+	if !reflect.DeepEqual(lock.Requirements, wantReqs) {
+		t.Errorf("requirements: got %+v", lock.Requirements)
+	}
+
+	problems := Problems(Verify(vars.StyleNuGet, lock.Direct, nil))
+	if len(problems) != 1 || problems[0].Name != "Serilog" || problems[0].Detail != "locked 3.0.0 does not satisfy [3.1.1, )" {
+		t.Errorf("requested vs resolved: got %+v", problems)
+	}
+	if ps := Problems(Verify(vars.StyleNuGet, lock.Requirements, lock.Packages)); len(ps) != 0 {
+		t.Errorf("requirements vs packages: got %+v", ps)
+	}
+}
+
+func TestLookupFor_NuGetCaseInsensitive(t *testing.T) {
+	lock := []Package{{Name: "newtonsoft.json", Version: "13.0.3"}}
+	d := Dependency{Name: "Newtonsoft.Json", Constraint: "[13.0.1, )"}
+	if p, ok := LookupFor(vars.StyleNuGet, lock, d); !ok || p.Version != "13.0.3" {
+		t.Errorf("got %+v, %v; want newtonsoft.json 13.0.3", p, ok)
+	}
+	if _, ok := LookupFor(vars.StyleNPM, lock, d); ok {
+		t.Error("npm names are case-sensitive")
+	}
+	if rs := Verify(vars.StyleNuGet, []Dependency{d}, lock); rs[0].Status != StatusOK || rs[0].Locked != "13.0.3" {
+		t.Errorf("verify: got %+v", rs[0])
+	}
+}
@@ -0,0 +1,531 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rng70/versions/v2/parser"
+)
+
+// Python readers report package names in their PEP 503 normalized form
+// ("Flask_SQLAlchemy" becomes "flask-sqlalchemy") so that manifests and
+// lockfiles written with different spellings line up in Verify.
+
+var (
+	rePyNameSep     = regexp.MustCompile(`[-_.]+`)
+	rePyComment     = regexp.MustCompile(`(^|\s)#.*$`)
+	rePyHash        = regexp.MustCompile(`\s--hash[=\s]\s*(\S+)`)
+	rePyDirectRef   = regexp.MustCompile(`^([A-Za-z0-9._-]+)(?:\[[^\]]*\])?\s*@\s*(\S+)$`)
+	rePyEgg         = regexp.MustCompile(`[#&]egg=([A-Za-z0-9._-]+)`)
+	rePyExtras      = regexp.MustCompile(`\[[^\]]*\]$`)
+	rePyOptionValue = regexp.MustCompile(`^(-[a-z]|--[a-z-]+)(?:=|\s+)?(.*)$`)
+)
+
+func pyName(name string) string {
+	return strings.ToLower(rePyNameSep.ReplaceAllString(strings.TrimSpace(name), "-"))
+}
+
+// pyPinned returns the version of an exact "==X" or "===X" specifier, or "".
+func pyPinned(spec string) string {
+	spec = strings.TrimSpace(spec)
+	if v, ok := strings.CutPrefix(spec, "==="); ok {
+		return strings.TrimSpace(v)
+	}
+	v, ok := strings.CutPrefix(spec, "==")
+	if !ok || strings.ContainsAny(v, ",*") {
+		return ""
+	}
+	return strings.TrimSpace(v)
+}
+
+// pySource makes sure a local path is recognised by parser.IsPythonSource.
+func pySource(s string) string {
+	if parser.IsPythonSource(s) {
+		return s
+	}
+	return "./" + s
+}
+
+// ParseRequirement reads one PEP 508 requirement line such as
+// `requests[security]>=2.8.1; python_version < "3.8"`. Environment markers
+// and extras are dropped; "--hash" options are collected into Hashes.
+// Direct references ("name @ url") keep "@ url" as their constraint.
+func ParseRequirement(line string) Dependency {
+	var d Dependency
+	line = " " + line
+	for _, m := range rePyHash.FindAllStringSubmatch(line, -1) {
+		d.Hashes = append(d.Hashes, m[1])
+	}
+	line = strings.TrimSpace(rePyHash.ReplaceAllString(line, ""))
+
+	if strings.Contains(line, "://") {
+		line, _, _ = strings.Cut(line, " ;")
+	} else {
+		line, _, _ = strings.Cut(line, ";")
+	}
+	line = strings.TrimSpace(line)
+
+	if m := rePyDirectRef.FindStringSubmatch(line); m != nil && strings.ContainsAny(m[2], ":/") {
+		d.Name, d.Constraint = pyName(m[1]), "@ "+m[2]
+		return d
+	}
+	if parser.IsPythonSource(line) {
+		d.Name, d.Constraint = line, line
+		if m := rePyEgg.FindStringSubmatch(line); m != nil {
+			d.Name = pyName(m[1])
+		}
+		return d
+	}
+
+	name, spec := parser.SplitRequirement(line)
+	spec = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(spec, "("), ")"))
+	d.Name = pyName(rePyExtras.ReplaceAllString(name, ""))
+	d.Constraint = spec
+	d.Version = pyPinned(spec)
+	return d
+}
+
+/* ------------------------- */
+/*     requirements.txt      */
+/* ------------------------- */
+
+// ReadRequirements reads the pip requirements file name from fsys. Files
+// named by -r/--requirement are read relative to the including file and
+// their entries appended in place. Files named by -c/--constraint only pin
+// versions: an exact "==X" constraint sets Version on the matching
+// requirement, so Verify reports constraints that contradict the
+// requirement. Editable and URL requirements are kept with their location
+// as the constraint, which Verify reports as unsupported. Each entry's Kind
+// is the file it was declared in.
+func ReadRequirements(fsys fs.FS, name string) ([]Dependency, error) {
+	rr := &reqReader{fsys: fsys, seen: map[string]bool{}, pins: map[string]string{}}
+	if err := rr.read(path.Clean(name), false); err != nil {
+		return nil, err
+	}
+	for i, d := range rr.deps {
+		if pin, ok := rr.pins[d.Name]; ok {
+			rr.deps[i].Version = pin
+		}
+	}
+	return rr.deps, nil
+}
+
+type reqReader struct {
+	fsys fs.FS
+	seen map[string]bool
+	deps []Dependency
+	pins map[string]string
+}
+
+func (rr *reqReader) read(name string, constraints bool) error {
+	if rr.seen[name] {
+		return nil
+	}
+	rr.seen[name] = true
+
+	data, err := fs.ReadFile(rr.fsys, name)
+	if err != nil {
+		return fmt.Errorf("requirements: %w", err)
+	}
+	for _, line := range requirementLines(string(data)) {
+		if strings.HasPrefix(line, "-") {
+			if err := rr.option(name, line, constraints); err != nil {
+				return err
+			}
+			continue
+		}
+		d := ParseRequirement(line)
+		d.Kind = name
+		if constraints {
+			if d.Version != "" {
+				rr.pins[d.Name] = d.Version
+			}
+			continue
+		}
+		rr.deps = append(rr.deps, d)
+	}
+	return nil
+}
+
+func (rr *reqReader) option(file, line string, constraints bool) error {
+	m := rePyOptionValue.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	value := strings.TrimSpace(m[2])
+	switch m[1] {
+	case "-r", "--requirement":
+		return rr.read(path.Join(path.Dir(file), value), constraints)
+	case "-c", "--constraint":
+		return rr.read(path.Join(path.Dir(file), value), true)
+	case "-e", "--editable":
+		if constraints || value == "" {
+			return nil
+		}
+		d := Dependency{Name: value, Constraint: pySource(value), Kind: file}
+		if m := rePyEgg.FindStringSubmatch(value); m != nil {
+			d.Name = pyName(m[1])
+		}
+		rr.deps = append(rr.deps, d)
+	}
+	return nil
+}
+
+// requirementLines joins backslash continuations and strips comments and
+// blank lines.
+func requirementLines(s string) []string {
+	var out []string
+	var cur strings.Builder
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasSuffix(line, `\`) {
+			cur.WriteString(strings.TrimSuffix(line, `\`))
+			cur.WriteByte(' ')
+			continue
+		}
+		cur.WriteString(line)
+		joined := strings.TrimSpace(rePyComment.ReplaceAllString(cur.String(), ""))
+		cur.Reset()
+		if joined != "" {
+			out = append(out, joined)
+		}
+	}
+	if rest := strings.TrimSpace(rePyComment.ReplaceAllString(cur.String(), "")); rest != "" {
+		out = append(out, rest)
+	}
+	return out
+}
+
+/* ------------------------- */
+/*      pyproject.toml       */
+/* ------------------------- */
+
+// PyProject is the dependency-related content of a pyproject.toml.
+type PyProject struct {
+	Name    string
+	Version string
+	// RequiresPython is project.requires-python, or the Poetry "python"
+	// dependency rewritten as a PEP 440 specifier.
+	RequiresPython string
+	// Dependencies holds PEP 621 dependencies and optional-dependencies,
+	// PEP 735 dependency-groups and Poetry dependency tables. Kind is the
+	// dotted table the entry came from, e.g. "optional-dependencies.test"
+	// or "tool.poetry.group.dev.dependencies".
+	Dependencies []Dependency
+}
+
+// ReadPyproject reads a pyproject.toml. Poetry constraints ("^1.2", "~1.2",
+// "1.2.*") are rewritten as PEP 440 specifiers so that they can be checked
+// with the Python parser; Poetry git, path and url dependencies are kept
+// with their location as the constraint.
+func ReadPyproject(r io.Reader) (*PyProject, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("pyproject.toml: %w", err)
+	}
+	doc, err := decodeTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("pyproject.toml: %w", err)
+	}
+
+	pp := &PyProject{}
+	project := tomlTable(doc, "project")
+	pp.Name = tomlString(project, "name")
+	pp.Version = tomlString(project, "version")
+	pp.RequiresPython = tomlString(project, "requires-python")
+	pp.Dependencies = append(pp.Dependencies, pep508List(project["dependencies"], "dependencies")...)
+
+	optional := tomlTable(project, "optional-dependencies")
+	for _, group := range sortedKeys(optional) {
+		pp.Dependencies = append(pp.Dependencies, pep508List(optional[group], "optional-dependencies."+group)...)
+	}
+	groups := tomlTable(doc, "dependency-groups")
+	for _, group := range sortedKeys(groups) {
+		pp.Dependencies = append(pp.Dependencies, pep508List(groups[group], "dependency-groups."+group)...)
+	}
+
+	poetry := tomlTable(tomlTable(doc, "tool"), "poetry")
+	if pp.Name == "" {
+		pp.Name = tomlString(poetry, "name")
+	}
+	if pp.Version == "" {
+		pp.Version = tomlString(poetry, "version")
+	}
+	tables := []string{"dependencies", "dev-dependencies"}
+	poetryGroups := tomlTable(poetry, "group")
+	for _, g := range sortedKeys(poetryGroups) {
+		tables = append(tables, "group."+g+".dependencies")
+	}
+	for _, t := range tables {
+		deps := poetry
+		for _, k := range strings.Split(t, ".") {
+			deps = tomlTable(deps, k)
+		}
+		for _, name := range sortedKeys(deps) {
+			if name == "python" {
+				if pp.RequiresPython == "" {
+					pp.RequiresPython = poetrySpecifier(tomlString(deps, name))
+				}
+				continue
+			}
+			pp.Dependencies = append(pp.Dependencies, poetryDependency(name, deps[name], "tool.poetry."+t)...)
+		}
+	}
+	return pp, nil
+}
+
+func pep508List(v any, kind string) []Dependency {
+	items, _ := v.([]any)
+	var out []Dependency
+	for _, it := range items {
+		// PEP 735 {include-group = "..."} entries are not requirements.
+		s, ok := it.(string)
+		if !ok {
+			continue
+		}
+		d := ParseRequirement(s)
+		d.Kind = kind
+		out = append(out, d)
+	}
+	return out
+}
+
+func poetryDependency(name string, v any, kind string) []Dependency {
+	d := Dependency{Name: pyName(name), Kind: kind}
+	switch v := v.(type) {
+	case string:
+		d.Constraint = poetrySpecifier(v)
+	case map[string]any:
+		d.Constraint = poetrySpecifier(tomlString(v, "version"))
+		if src := tomlSource(v); src != "" {
+			d.Constraint = src
+		}
+	case []any:
+		// Multiple-constraint dependencies: one entry per marker variant.
+		var out []Dependency
+		for _, alt := range v {
+			out = append(out, poetryDependency(name, alt, kind)...)
+		}
+		return out
+	}
+	d.Version = pyPinned(d.Constraint)
+	return []Dependency{d}
+}
+
+// tomlSource returns the location of a git, path, file or url dependency
+// table as used by Poetry and Pipenv, or "".
+func tomlSource(t map[string]any) string {
+	if git := tomlString(t, "git"); git != "" {
+		if !strings.HasPrefix(git, "git+") {
+			git = "git+" + git
+		}
+		for _, ref := range []string{"rev", "ref", "tag", "branch"} {
+			if r := tomlString(t, ref); r != "" {
+				return git + "@" + r
+			}
+		}
+		return git
+	}
+	for _, k := range []string{"url", "path", "file"} {
+		if s := tomlString(t, k); s != "" {
+			return pySource(s)
+		}
+	}
+	return ""
+}
+
+// poetrySpecifier rewrites a Poetry constraint as a PEP 440 specifier.
+// Caret, tilde, wildcard and bare versions follow Cargo's rules, which
+// Poetry shares. Constraints with "||" have no PEP 440 equivalent and are
+// returned unchanged.
+func poetrySpecifier(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "*" {
+		return ""
+	}
+	if s == "" || strings.Contains(s, "||") {
+		return s
+	}
+	var parts []string
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !poetryOnly(p) {
+			parts = append(parts, p)
+			continue
+		}
+		groups, err := parser.ParseRust(p)
+		if err != nil || len(groups) != 1 {
+			parts = append(parts, p)
+			continue
+		}
+		for _, c := range groups[0] {
+			switch c.Op {
+			case "=":
+				parts = append(parts, "=="+c.Ver)
+			case "<core":
+				parts = append(parts, "<"+c.Ver)
+			default:
+				parts = append(parts, c.Op+c.Ver)
+			}
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// poetryOnly reports whether p uses syntax PEP 440 reads differently.
+func poetryOnly(p string) bool {
+	switch {
+	case strings.HasPrefix(p, "^"), strings.HasPrefix(p, "~") && !strings.HasPrefix(p, "~="):
+		return true
+	case strings.HasPrefix(p, "==") || strings.HasPrefix(p, "!="):
+		return false
+	case strings.Contains(p, "*"):
+		return true
+	}
+	return p[0] >= '0' && p[0] <= '9'
+}
+
+/* ------------------------- */
+/*    Pipfile / .lock        */
+/* ------------------------- */
+
+var pipfileSections = []string{"packages", "dev-packages"}
+
+// ReadPipfile reads a Pipfile. Kind is "packages" or "dev-packages"; "*"
+// becomes an empty constraint.
+func ReadPipfile(r io.Reader) ([]Dependency, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("Pipfile: %w", err)
+	}
+	doc, err := decodeTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("Pipfile: %w", err)
+	}
+
+	var out []Dependency
+	for _, section := range pipfileSections {
+		deps := tomlTable(doc, section)
+		for _, name := range sortedKeys(deps) {
+			d := Dependency{Name: pyName(name), Kind: section}
+			switch v := deps[name].(type) {
+			case string:
+				d.Constraint = v
+			case map[string]any:
+				d.Constraint = tomlString(v, "version")
+				if src := tomlSource(v); src != "" {
+					d.Constraint = src
+				}
+			}
+			if d.Constraint == "*" {
+				d.Constraint = ""
+			}
+			d.Version = pyPinned(d.Constraint)
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+type pipfileLockEntry struct {
+	Version string `json:"version"`
+	Git     string `json:"git"`
+	Ref     string `json:"ref"`
+	Index   string `json:"index"`
+}
+
+// ReadPipfileLock reads a Pipfile.lock. Entries of the "default" and
+// "develop" sections are returned; VCS and path entries, which have no
+// version, are skipped.
+func ReadPipfileLock(r io.Reader) ([]Package, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("Pipfile.lock: %w", err)
+	}
+	var out []Package
+	for _, section := range []string{"default", "develop"} {
+		if len(raw[section]) == 0 {
+			continue
+		}
+		var entries map[string]pipfileLockEntry
+		if err := json.Unmarshal(raw[section], &entries); err != nil {
+			return nil, fmt.Errorf("Pipfile.lock %s: %w", section, err)
+		}
+		names := make([]string, 0, len(entries))
+		for n := range entries {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			e := entries[n]
+			v := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(e.Version, "=="), "="))
+			if v == "" {
+				continue
+			}
+			out = append(out, Package{Name: pyName(n), Version: v, Source: e.Index})
+		}
+	}
+	return out, nil
+}
+
+/* ------------------------- */
+/*        poetry.lock        */
+/* ------------------------- */
+
+// ReadPoetryLock reads a poetry.lock.
+func ReadPoetryLock(r io.Reader) ([]Package, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("poetry.lock: %w", err)
+	}
+	doc, err := decodeTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("poetry.lock: %w", err)
+	}
+	pkgs, _ := doc["package"].([]any)
+	out := make([]Package, 0, len(pkgs))
+	for _, it := range pkgs {
+		t, _ := it.(map[string]any)
+		p := Package{Name: pyName(tomlString(t, "name")), Version: tomlString(t, "version")}
+		if p.Name == "" || p.Version == "" {
+			continue
+		}
+		p.Source = tomlString(tomlTable(t, "source"), "url")
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+/* ------------------------- */
+/*     decoded TOML access   */
+/* ------------------------- */
+
+func tomlTable(t map[string]any, key string) map[string]any {
+	m, _ := t[key].(map[string]any)
+	return m
+}
+
+func tomlString(t map[string]any, key string) string {
+	s, _ := t[key].(string)
+	return s
+}
+
+func sortedKeys[V any](t map[string]V) []string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
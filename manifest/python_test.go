@@ -0,0 +1,275 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ─── ParseRequirement ─────────────────────────────────────────────────────────
+
+func TestParseRequirement(t *testing.T) {
+	cases := []struct {
+		line string
+		want Dependency
+	}{
+		{"requests>=2.8.1", Dependency{Name: "requests", Constraint: ">=2.8.1"}},
+		{"Flask_SQLAlchemy==3.0.5", Dependency{Name: "flask-sqlalchemy", Constraint: "==3.0.5", Version: "3.0.5"}},
+		{`requests[security,socks] >= 2.8.1, < 3 ; python_version < "3.8"`, Dependency{Name: "requests", Constraint: ">= 2.8.1, < 3"}},
+		{"django (>=4.0)", Dependency{Name: "django", Constraint: ">=4.0"}},
+		{"numpy==1.*", Dependency{Name: "numpy", Constraint: "==1.*"}},
+		{"pkg @ https://example.com/pkg-1.0.whl", Dependency{Name: "pkg", Constraint: "@ https://example.com/pkg-1.0.whl"}},
+		{"git+https://github.com/u/r.git@v1#egg=My_Pkg", Dependency{Name: "my-pkg", Constraint: "git+https://github.com/u/r.git@v1#egg=My_Pkg"}},
+		{"six==1.16.0 --hash=sha256:aaa --hash=sha256:bbb", Dependency{Name: "six", Constraint: "==1.16.0", Version: "1.16.0", Hashes: []string{"sha256:aaa", "sha256:bbb"}}},
+	}
+	for _, c := range cases {
+		if got := ParseRequirement(c.line); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q: got %+v, want %+v", c.line, got, c.want)
+		}
+	}
+}
+
+// ─── ReadRequirements ─────────────────────────────────────────────────────────
+
+func TestReadRequirements(t *testing.T) {
+	fsys := fstest.MapFS{
+		"requirements.txt": {Data: []byte(`
+# top-level
+--index-url https://pypi.org/simple
+-r base.txt
+-c constraints/pins.txt
+requests>=2.0,<3   # inline comment
+six==1.16.0 \
+    --hash=sha256:aaa
+-e ./local
+`)},
+		"base.txt":             {Data: []byte("Django~=4.2\n-r requirements.txt\n")},
+		"constraints/pins.txt": {Data: []byte("requests==2.31.0\ndjango==5.0\nunused==1.0\n-r more.txt\n")},
+		"constraints/more.txt": {Data: []byte("six==1.15.0\n")},
+	}
+	deps, err := ReadRequirements(fsys, "requirements.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Dependency{
+		{Name: "django", Constraint: "~=4.2", Kind: "base.txt", Version: "5.0"},
+		{Name: "requests", Constraint: ">=2.0,<3", Kind: "requirements.txt", Version: "2.31.0"},
+		{Name: "six", Constraint: "==1.16.0", Kind: "requirements.txt", Version: "1.15.0", Hashes: []string{"sha256:aaa"}},
+		{Name: "./local", Constraint: "./local", Kind: "requirements.txt"},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Fatalf("got  %+v\nwant %+v", deps, want)
+	}
+
+	got := map[string]Status{}
+	for _, r := range Verify(vars.StylePy, deps, nil) {
+		got[r.Name] = r.Status
+	}
+	wantStatus := map[string]Status{
+		"django":   StatusDrift,
+		"requests": StatusOK,
+		"six":      StatusDrift,
+		"./local":  StatusUnsupported,
+	}
+	if !reflect.DeepEqual(got, wantStatus) {
+		t.Errorf("verify: got %v, want %v", got, wantStatus)
+	}
+}
+
+func TestReadRequirements_MissingInclude(t *testing.T) {
+	fsys := fstest.MapFS{"requirements.txt": {Data: []byte("-r nope.txt\n")}}
+	if _, err := ReadRequirements(fsys, "requirements.txt"); err == nil {
+		t.Error("expected error for missing include")
+	}
+}
+
+// ─── ReadPyproject ────────────────────────────────────────────────────────────
+
+func TestReadPyproject_PEP621(t *testing.T) {
+	pp, err := ReadPyproject(strings.NewReader(`
+[project]
+name = "demo"
+version = "0.1.0"
+requires-python = ">=3.9"
+dependencies = [
+  "httpx>=0.27",
+  "attrs==23.2.0",
+]
+
+[project.optional-dependencies]
+test = ["pytest>=8"]
+
+[dependency-groups]
+lint = ["ruff==0.4.0", { include-group = "test" }]
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pp.Name != "demo" || pp.Version != "0.1.0" || pp.RequiresPython != ">=3.9" {
+		t.Errorf("metadata: got %+v", pp)
+	}
+	want := []Dependency{
+		{Name: "httpx", Constraint: ">=0.27", Kind: "dependencies"},
+		{Name: "attrs", Constraint: "==23.2.0", Kind: "dependencies", Version: "23.2.0"},
+		{Name: "pytest", Constraint: ">=8", Kind: "optional-dependencies.test"},
+		{Name: "ruff", Constraint: "==0.4.0", Kind: "dependency-groups.lint", Version: "0.4.0"},
+	}
+	if !reflect.DeepEqual(pp.Dependencies, want) {
+		t.Errorf("got  %+v\nwant %+v", pp.Dependencies, want)
+	}
+}
+
+func TestReadPyproject_Poetry(t *testing.T) {
+	pp, err := ReadPyproject(strings.NewReader(`
+[tool.poetry]
+name = "demo"
+version = "1.0.0"
+
+[tool.poetry.dependencies]
+python = "^3.10"
+requests = "^2.31"
+click = { version = "~8.1", optional = true }
+tomli = "*"
+mylib = { git = "https://github.com/u/mylib.git", tag = "v1.0" }
+local = { path = "libs/local", develop = true }
+
+[tool.poetry.group.dev.dependencies]
+pytest = ">=7.0,<9.0"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pp.Name != "demo" || pp.RequiresPython != ">=3.10.0,<4.0.0" {
+		t.Errorf("metadata: got %+v", pp)
+	}
+	want := []Dependency{
+		{Name: "click", Constraint: ">=8.1.0,<8.2.0", Kind: "tool.poetry.dependencies"},
+		{Name: "local", Constraint: "./libs/local", Kind: "tool.poetry.dependencies"},
+		{Name: "mylib", Constraint: "git+https://github.com/u/mylib.git@v1.0", Kind: "tool.poetry.dependencies"},
+		{Name: "requests", Constraint: ">=2.31.0,<3.0.0", Kind: "tool.poetry.dependencies"},
+		{Name: "tomli", Constraint: "", Kind: "tool.poetry.dependencies"},
+		{Name: "pytest", Constraint: ">=7.0,<9.0", Kind: "tool.poetry.group.dev.dependencies"},
+	}
+	if !reflect.DeepEqual(pp.Dependencies, want) {
+		t.Errorf("got  %+v\nwant %+v", pp.Dependencies, want)
+	}
+}
+
+func TestPoetrySpecifier(t *testing.T) {
+	cases := map[string]string{
+		"^1.2.3":      ">=1.2.3,<2.0.0",
+		"^0.2":        ">=0.2.0,<0.3.0",
+		"~1.2":        ">=1.2.0,<1.3.0",
+		"~=1.2":       "~=1.2",
+		"1.2.*":       ">=1.2.0,<1.3.0",
+		"1.2.3":       "==1.2.3",
+		">=1.0, ^1.5": ">=1.0,>=1.5.0,<2.0.0",
+		"^1 || ^2":    "^1 || ^2",
+		"*":           "",
+	}
+	for in, want := range cases {
+		if got := poetrySpecifier(in); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+// ─── Pipfile / Pipfile.lock / poetry.lock ─────────────────────────────────────
+
+func TestReadPipfile(t *testing.T) {
+	deps, err := ReadPipfile(strings.NewReader(`
+[[source]]
+url = "https://pypi.org/simple"
+verify_ssl = true
+name = "pypi"
+
+[packages]
+requests = "*"
+django = { version = "==4.2.7", extras = ["bcrypt"] }
+mylib = { git = "https://github.com/u/mylib.git", ref = "main" }
+
+[dev-packages]
+pytest = ">=7"
+
+[requires]
+python_version = "3.11"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Dependency{
+		{Name: "django", Constraint: "==4.2.7", Kind: "packages", Version: "4.2.7"},
+		{Name: "mylib", Constraint: "git+https://github.com/u/mylib.git@main", Kind: "packages"},
+		{Name: "requests", Constraint: "", Kind: "packages"},
+		{Name: "pytest", Constraint: ">=7", Kind: "dev-packages"},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("got  %+v\nwant %+v", deps, want)
+	}
+}
+
+func TestReadPipfileLock(t *testing.T) {
+	lock, err := ReadPipfileLock(strings.NewReader(`{
+  "_meta": {"hash": {"sha256": "x"}},
+  "default": {
+    "Django": {"hashes": ["sha256:a"], "index": "pypi", "version": "==4.2.7"},
+    "mylib": {"git": "https://github.com/u/mylib.git", "ref": "abc"}
+  },
+  "develop": {
+    "pytest": {"version": "==7.4.3"}
+  }
+}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Package{
+		{Name: "django", Version: "4.2.7", Source: "pypi"},
+		{Name: "pytest", Version: "7.4.3"},
+	}
+	if !reflect.DeepEqual(lock, want) {
+		t.Errorf("got %+v", lock)
+	}
+}
+
+func TestReadPoetryLock_Verify(t *testing.T) {
+	lock, err := ReadPoetryLock(strings.NewReader(`
+[[package]]
+name = "requests"
+version = "2.31.0"
+description = "HTTP"
+optional = false
+python-versions = ">=3.7"
+files = [
+    {file = "requests-2.31.0-py3-none-any.whl", hash = "sha256:aaa"},
+]
+
+[package.dependencies]
+idna = ">=2.5,<4"
+
+[[package]]
+name = "Click"
+version = "8.0.4"
+
+[metadata]
+lock-version = "2.0"
+content-hash = "abc"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lock) != 2 || lock[1].Name != "click" {
+		t.Fatalf("got %+v", lock)
+	}
+
+	deps := []Dependency{
+		{Name: "requests", Constraint: poetrySpecifier("^2.31")},
+		{Name: "click", Constraint: poetrySpecifier("~8.1")},
+	}
+	rs := Verify(vars.StylePy, deps, lock)
+	if rs[0].Status != StatusOK || rs[1].Status != StatusDrift {
+		t.Errorf("verify: got %+v", rs)
+	}
+}
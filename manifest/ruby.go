@@ -0,0 +1,291 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+/* ------------------------- */
+/*         Gemfile           */
+/* ------------------------- */
+
+// Gemfile is the dependency-related content of a Gemfile.
+type Gemfile struct {
+	// Ruby is the `ruby "x.y.z"` requirement, if any.
+	Ruby string
+	// Dependencies holds one entry per gem declaration. Multiple
+	// requirement arguments are joined with ", ", which ParseRuby reads as
+	// AND, as RubyGems does. Kind lists the gem's groups, comma-separated,
+	// or "default".
+	Dependencies []Dependency
+}
+
+var (
+	reGemfileBlock = regexp.MustCompile(`\bdo(\s*\|[^|]*\|)?\s*$`)
+	reGemfileCall  = regexp.MustCompile(`^(gem|group|ruby)\b\s*\(?\s*(.*?)\)?\s*$`)
+	reGemfileCond  = regexp.MustCompile(`\s+(if|unless)\s+.*$`)
+)
+
+// ReadGemfile reads a Gemfile. It understands `gem`, `group ... do`
+// blocks, `ruby` and the group/git/github/path options of `gem`; other
+// Ruby code is skipped. Git and path gems without a requirement get
+// "git+url" or "path+dir" as their constraint.
+func ReadGemfile(r io.Reader) (*Gemfile, error) {
+	gf := &Gemfile{}
+	// blocks holds the groups of each open do...end block; nil for blocks
+	// other than group.
+	var blocks [][]string
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(stripRubyComment(sc.Text()))
+		if line == "" {
+			continue
+		}
+		if line == "end" {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+
+		block := reGemfileBlock.MatchString(line)
+		if block {
+			line = strings.TrimSpace(reGemfileBlock.ReplaceAllString(line, ""))
+		}
+		line = reGemfileCond.ReplaceAllString(line, "")
+
+		m := reGemfileCall.FindStringSubmatch(line)
+		if m == nil {
+			if block {
+				blocks = append(blocks, nil)
+			}
+			continue
+		}
+		args := splitRubyArgs(m[2])
+		switch m[1] {
+		case "group":
+			var groups []string
+			for _, a := range args {
+				groups = append(groups, rubySymbol(a))
+			}
+			if block {
+				blocks = append(blocks, groups)
+			}
+		case "ruby":
+			if len(args) > 0 {
+				gf.Ruby = rubyString(args[0])
+			}
+		case "gem":
+			if len(args) == 0 {
+				continue
+			}
+			var groups []string
+			for _, b := range blocks {
+				groups = append(groups, b...)
+			}
+			gf.Dependencies = append(gf.Dependencies, gemDependency(args, groups))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("Gemfile: %w", err)
+	}
+	return gf, nil
+}
+
+func gemDependency(args, groups []string) Dependency {
+	d := Dependency{Name: rubyString(args[0])}
+	var reqs []string
+	var source string
+	for _, a := range args[1:] {
+		key, val, ok := rubyOption(a)
+		if !ok {
+			reqs = append(reqs, rubyString(a))
+			continue
+		}
+		switch key {
+		case "group", "groups":
+			for _, g := range splitRubyArgs(strings.Trim(val, "[]")) {
+				groups = append(groups, rubySymbol(g))
+			}
+		case "git":
+			source = "git+" + rubyString(val)
+		case "github":
+			source = "git+https://github.com/" + rubyString(val)
+		case "path":
+			source = "path+" + rubyString(val)
+		}
+	}
+	d.Constraint = strings.Join(reqs, ", ")
+	if d.Constraint == "" {
+		d.Constraint = source
+	}
+	d.Kind = "default"
+	if len(groups) > 0 {
+		d.Kind = strings.Join(groups, ",")
+	}
+	return d
+}
+
+// splitRubyArgs splits a Ruby argument list on top-level commas.
+func splitRubyArgs(s string) []string {
+	var out []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{' || c == '(':
+			depth++
+		case c == ']' || c == '}' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			out = append(out, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(s[start:]); rest != "" {
+		out = append(out, rest)
+	}
+	return out
+}
+
+// rubyOption splits `key: value` and `:key => value`.
+func rubyOption(a string) (string, string, bool) {
+	if a == "" || a[0] == '"' || a[0] == '\'' {
+		return "", "", false
+	}
+	if k, v, ok := strings.Cut(a, "=>"); ok {
+		return rubySymbol(k), strings.TrimSpace(v), true
+	}
+	if k, v, ok := strings.Cut(a, ":"); ok && k != "" && !strings.ContainsAny(k, " \"'") {
+		return k, strings.TrimSpace(v), true
+	}
+	return "", "", false
+}
+
+func rubyString(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func rubySymbol(s string) string {
+	return strings.TrimPrefix(rubyString(strings.TrimSpace(s)), ":")
+}
+
+// stripRubyComment removes a # comment that is not inside a string.
+func stripRubyComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return s[:i]
+		}
+	}
+	return s
+}
+
+/* ------------------------- */
+/*       Gemfile.lock        */
+/* ------------------------- */
+
+// GemfileLock is the content of a Gemfile.lock.
+type GemfileLock struct {
+	// Specs holds the locked gems of the GEM, GIT and PATH sections. Source
+	// is the section's remote. Platform suffixes ("-x86_64-linux") are
+	// removed from versions.
+	Specs []Package
+	// Requirements holds the requirements the locked gems place on each
+	// other. Kind is the requiring gem as "name (version)".
+	Requirements []Dependency
+	// Dependencies holds the DEPENDENCIES section: the Gemfile's
+	// requirements as Bundler recorded them.
+	Dependencies []Dependency
+	BundledWith  string
+}
+
+var reGemLockEntry = regexp.MustCompile(`^([^\s(]+?)(!)?(?:\s+\((.*)\))?$`)
+
+// ReadGemfileLock reads a Gemfile.lock.
+func ReadGemfileLock(r io.Reader) (*GemfileLock, error) {
+	gl := &GemfileLock{}
+	var section, remote, parent string
+	seen := map[string]bool{}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		text := strings.TrimSpace(line)
+		if indent == 0 {
+			section, remote, parent = text, "", ""
+			continue
+		}
+
+		switch section {
+		case "GEM", "GIT", "PATH":
+			if indent == 2 {
+				if v, ok := strings.CutPrefix(text, "remote:"); ok {
+					remote = strings.TrimSpace(v)
+				}
+				continue
+			}
+			m := reGemLockEntry.FindStringSubmatch(text)
+			if m == nil {
+				continue
+			}
+			switch indent {
+			case 4:
+				version, _, _ := strings.Cut(m[3], "-")
+				parent = m[1] + " (" + version + ")"
+				if seen[parent] {
+					// Another platform build of a gem already listed.
+					parent = ""
+					continue
+				}
+				seen[parent] = true
+				gl.Specs = append(gl.Specs, Package{Name: m[1], Version: version, Source: remote})
+			case 6:
+				if parent == "" {
+					continue
+				}
+				gl.Requirements = append(gl.Requirements, Dependency{Name: m[1], Constraint: m[3], Kind: parent})
+			}
+		case "DEPENDENCIES":
+			if m := reGemLockEntry.FindStringSubmatch(text); m != nil && indent == 2 {
+				gl.Dependencies = append(gl.Dependencies, Dependency{Name: m[1], Constraint: m[3], Kind: "DEPENDENCIES"})
+			}
+		case "BUNDLED WITH":
+			gl.BundledWith = text
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("Gemfile.lock: %w", err)
+	}
+	return gl, nil
+}
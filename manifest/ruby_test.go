@@ -0,0 +1,150 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+const gemfileLock = `GIT
+  remote: https://github.com/u/mygem.git
+  revision: 0123456789abcdef
+  specs:
+    mygem (0.2.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    actionpack (7.0.8)
+      rack (~> 2.0, >= 2.2.4)
+    nokogiri (1.15.4-x86_64-linux)
+      racc (~> 1.4)
+    nokogiri (1.15.4-arm64-darwin)
+      racc (~> 1.4)
+    pg (1.5.4)
+    rack (2.2.8)
+    racc (1.7.1)
+    rails (7.0.8)
+      actionpack (= 7.0.8)
+    rspec (3.12.0)
+
+PLATFORMS
+  arm64-darwin
+  x86_64-linux
+
+DEPENDENCIES
+  mygem!
+  nokogiri
+  pg (>= 0.18, < 2.0)
+  rails (~> 7.0.4)
+  rspec
+
+BUNDLED WITH
+   2.4.19
+`
+
+// ─── ReadGemfile ──────────────────────────────────────────────────────────────
+
+func TestReadGemfile(t *testing.T) {
+	gf, err := ReadGemfile(strings.NewReader(`
+source "https://rubygems.org"
+ruby "3.2.2"
+
+gem "rails", "~> 7.0.4"
+gem 'pg', '>= 0.18', '< 2.0' # database
+gem("nokogiri")
+gem "mygem", github: "u/mygem"
+gem "engine", :path => "engines/engine"
+gem "bootsnap", ">= 1.4.4", require: false
+
+group :development, :test do
+  gem "rspec", "~> 3.12"
+  platforms :mri do
+    gem "byebug"
+  end
+end
+
+gem "debug", group: [:development]
+gem "tzinfo-data" if Gem.win_platform?
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gf.Ruby != "3.2.2" {
+		t.Errorf("ruby: got %q", gf.Ruby)
+	}
+	want := []Dependency{
+		{Name: "rails", Constraint: "~> 7.0.4", Kind: "default"},
+		{Name: "pg", Constraint: ">= 0.18, < 2.0", Kind: "default"},
+		{Name: "nokogiri", Kind: "default"},
+		{Name: "mygem", Constraint: "git+https://github.com/u/mygem", Kind: "default"},
+		{Name: "engine", Constraint: "path+engines/engine", Kind: "default"},
+		{Name: "bootsnap", Constraint: ">= 1.4.4", Kind: "default"},
+		{Name: "rspec", Constraint: "~> 3.12", Kind: "development,test"},
+		{Name: "byebug", Kind: "development,test"},
+		{Name: "debug", Kind: "development"},
+		{Name: "tzinfo-data", Kind: "default"},
+	}
+	if !reflect.DeepEqual(gf.Dependencies, want) {
+		t.Errorf("got  %+v\nwant %+v", gf.Dependencies, want)
+	}
+}
+
+// ─── ReadGemfileLock ──────────────────────────────────────────────────────────
+
+func TestReadGemfileLock(t *testing.T) {
+	gl, err := ReadGemfileLock(strings.NewReader(gemfileLock))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gl.BundledWith != "2.4.19" {
+		t.Errorf("bundled with: got %q", gl.BundledWith)
+	}
+	if len(gl.Specs) != 8 {
+		t.Fatalf("expected 8 specs, got %d: %+v", len(gl.Specs), gl.Specs)
+	}
+	if p := gl.Specs[0]; !reflect.DeepEqual(p, Package{Name: "mygem", Version: "0.2.0", Source: "https://github.com/u/mygem.git"}) {
+		t.Errorf("git spec: got %+v", p)
+	}
+	if p := gl.Specs[2]; p.Name != "nokogiri" || p.Version != "1.15.4" {
+		t.Errorf("platform suffix not removed: got %+v", p)
+	}
+	wantReqs := []Dependency{
+		{Name: "rack", Constraint: "~> 2.0, >= 2.2.4", Kind: "actionpack (7.0.8)"},
+		{Name: "racc", Constraint: "~> 1.4", Kind: "nokogiri (1.15.4)"},
+		{Name: "actionpack", Constraint: "= 7.0.8", Kind: "rails (7.0.8)"},
+	}
+	if !reflect.DeepEqual(gl.Requirements, wantReqs) {
+		t.Errorf("requirements: got %+v", gl.Requirements)
+	}
+	if d := gl.Dependencies[2]; d.Name != "pg" || d.Constraint != ">= 0.18, < 2.0" {
+		t.Errorf("dependencies: got %+v", gl.Dependencies)
+	}
+	if ps := Problems(Verify(vars.StyleRuby, gl.Requirements, gl.Specs)); len(ps) != 0 {
+		t.Errorf("lockfile should be consistent, got %+v", ps)
+	}
+}
+
+func TestVerify_Gemfile(t *testing.T) {
+	gf, _ := ReadGemfile(strings.NewReader(`
+gem "rails", "~> 7.1"
+gem "pg", ">= 0.18", "< 2.0"
+gem "mygem", github: "u/mygem"
+gem "sidekiq"
+`))
+	gl, _ := ReadGemfileLock(strings.NewReader(gemfileLock))
+
+	want := map[string]Status{
+		"rails":   StatusDrift,
+		"pg":      StatusOK,
+		"mygem":   StatusUnsupported,
+		"sidekiq": StatusMissing,
+	}
+	for _, r := range Verify(vars.StyleRuby, gf.Dependencies, gl.Specs) {
+		if r.Status != want[r.Name] {
+			t.Errorf("%s: got %s (%s), want %s", r.Name, r.Status, r.Detail, want[r.Name])
+		}
+	}
+}
@@ -0,0 +1,456 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/* ------------------------- */
+/*      TOML (subset)        */
+/* ------------------------- */
+
+// decodeTOML parses the TOML used by manifests and lockfiles into nested
+// maps. Tables become map[string]any, arrays and arrays of tables []any.
+// Strings (all four forms), integers, floats, booleans, arrays and inline
+// tables are supported; dates and times are kept as their literal text.
+func decodeTOML(data []byte) (map[string]any, error) {
+	p := &tomlParser{s: string(data), line: 1}
+	root := map[string]any{}
+	cur := root
+
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return root, nil
+		}
+		var err error
+		if p.peek() == '[' {
+			cur, err = p.header(root)
+		} else {
+			err = p.keyValue(cur)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := p.endOfLine(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reTOMLDateTime matches offset date-times, local date-times, local dates and
+// local times.
+var reTOMLDateTime = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}([Tt ]\d{2}:\d{2}:\d{2}(\.\d+)?([Zz]|[+-]\d{2}:\d{2})?)?|\d{2}:\d{2}:\d{2}(\.\d+)?)$`)
+
+type tomlParser struct {
+	s    string
+	pos  int
+	line int
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("toml line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) eof() bool  { return p.pos >= len(p.s) }
+func (p *tomlParser) peek() byte { return p.s[p.pos] }
+
+func (p *tomlParser) advance(n int) {
+	p.line += strings.Count(p.s[p.pos:p.pos+n], "\n")
+	p.pos += n
+}
+
+// skipBlank skips spaces, tabs and comments, and newlines when newlines is set.
+func (p *tomlParser) skipBlank(newlines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && newlines:
+			p.advance(1)
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) endOfLine() error {
+	p.skipBlank(false)
+	if !p.eof() && p.peek() != '\n' {
+		return p.errorf("unexpected %q after value", p.peek())
+	}
+	return nil
+}
+
+func (p *tomlParser) expect(c byte) error {
+	p.skipBlank(false)
+	if p.eof() || p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// header reads a [table] or [[array.of.tables]] line and returns the table
+// that following key/value pairs belong to.
+func (p *tomlParser) header(root map[string]any) (map[string]any, error) {
+	array := strings.HasPrefix(p.s[p.pos:], "[[")
+	if array {
+		p.pos += 2
+	} else {
+		p.pos++
+	}
+	path, err := p.key()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(']'); err != nil {
+		return nil, err
+	}
+	if array {
+		if err := p.expect(']'); err != nil {
+			return nil, err
+		}
+	}
+
+	parent, err := p.descend(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	if !array {
+		return p.descend(parent, []string{last})
+	}
+	t := map[string]any{}
+	switch existing := parent[last].(type) {
+	case nil:
+		parent[last] = []any{t}
+	case []any:
+		parent[last] = append(existing, t)
+	default:
+		return nil, p.errorf("%q is not an array of tables", strings.Join(path, "."))
+	}
+	return t, nil
+}
+
+// descend walks path from t, creating missing tables. An array of tables on
+// the way resolves to its last element.
+func (p *tomlParser) descend(t map[string]any, path []string) (map[string]any, error) {
+	for _, k := range path {
+		switch next := t[k].(type) {
+		case nil:
+			m := map[string]any{}
+			t[k] = m
+			t = m
+		case map[string]any:
+			t = next
+		case []any:
+			if len(next) == 0 {
+				return nil, p.errorf("%q is not a table", k)
+			}
+			m, ok := next[len(next)-1].(map[string]any)
+			if !ok {
+				return nil, p.errorf("%q is not a table", k)
+			}
+			t = m
+		default:
+			return nil, p.errorf("%q is not a table", k)
+		}
+	}
+	return t, nil
+}
+
+func (p *tomlParser) keyValue(t map[string]any) error {
+	path, err := p.key()
+	if err != nil {
+		return err
+	}
+	if err := p.expect('='); err != nil {
+		return err
+	}
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+	parent, err := p.descend(t, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	last := path[len(path)-1]
+	if _, dup := parent[last]; dup {
+		return p.errorf("duplicate key %q", strings.Join(path, "."))
+	}
+	parent[last] = v
+	return nil
+}
+
+// key reads a possibly dotted key such as `a."b.c".d`.
+func (p *tomlParser) key() ([]string, error) {
+	var path []string
+	for {
+		p.skipBlank(false)
+		if p.eof() {
+			return nil, p.errorf("expected key")
+		}
+		var k string
+		var err error
+		switch p.peek() {
+		case '"':
+			k, err = p.basicString()
+		case '\'':
+			k, err = p.literalString()
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if p.pos == start {
+				return nil, p.errorf("expected key, got %q", p.peek())
+			}
+			k = p.s[start:p.pos]
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, k)
+		p.skipBlank(false)
+		if p.eof() || p.peek() != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) value() (any, error) {
+	p.skipBlank(false)
+	if p.eof() {
+		return nil, p.errorf("expected value")
+	}
+	rest := p.s[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, `"""`):
+		return p.multilineString(`"""`)
+	case strings.HasPrefix(rest, `'''`):
+		return p.multilineString(`'''`)
+	case rest[0] == '"':
+		return p.basicString()
+	case rest[0] == '\'':
+		return p.literalString()
+	case rest[0] == '[':
+		return p.array()
+	case rest[0] == '{':
+		return p.inlineTable()
+	}
+
+	end := strings.IndexAny(rest, ",]}#\n")
+	if end < 0 {
+		end = len(rest)
+	}
+	tok := strings.TrimSpace(rest[:end])
+	p.pos += end
+	switch tok {
+	case "":
+		return nil, p.errorf("expected value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	num := strings.ReplaceAll(tok, "_", "")
+	if n, err := strconv.ParseInt(num, 0, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(num, 64); err == nil {
+		return f, nil
+	}
+	if reTOMLDateTime.MatchString(tok) {
+		return tok, nil
+	}
+	return nil, p.errorf("invalid value %q", tok)
+}
+
+func (p *tomlParser) array() ([]any, error) {
+	p.pos++ // [
+	out := []any{}
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return out, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		p.skipBlank(true)
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected ',' or ']' in array, got %q", p.peek())
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() (map[string]any, error) {
+	p.pos++ // {
+	out := map[string]any{}
+	p.skipBlank(false)
+	if !p.eof() && p.peek() == '}' {
+		p.pos++
+		return out, nil
+	}
+	for {
+		if err := p.keyValue(out); err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return out, nil
+		default:
+			return nil, p.errorf("expected ',' or '}' in inline table, got %q", p.peek())
+		}
+	}
+}
+
+func (p *tomlParser) literalString() (string, error) {
+	end := strings.IndexAny(p.s[p.pos+1:], "'\n")
+	if end < 0 || p.s[p.pos+1+end] != '\'' {
+		return "", p.errorf("unterminated string")
+	}
+	s := p.s[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return s, nil
+}
+
+func (p *tomlParser) basicString() (string, error) {
+	var b strings.Builder
+	i := p.pos + 1
+	for i < len(p.s) {
+		c := p.s[i]
+		switch c {
+		case '"':
+			p.pos = i + 1
+			return b.String(), nil
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '\\':
+			n, err := p.escape(&b, i)
+			if err != nil {
+				return "", err
+			}
+			i += n
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// multilineString reads a """ or ”' string. A newline right after the
+// opening delimiter is dropped, and in basic strings a backslash at the end
+// of a line joins it with the next non-blank text.
+func (p *tomlParser) multilineString(delim string) (string, error) {
+	start := p.pos + 3
+	end := strings.Index(p.s[start:], delim)
+	if end < 0 {
+		return "", p.errorf("unterminated string")
+	}
+	// Up to two quotes may directly precede the closing delimiter.
+	for start+end+3 < len(p.s) && p.s[start+end+3] == delim[0] {
+		end++
+	}
+	raw := p.s[start : start+end]
+	p.advance(3 + end + 3)
+
+	raw = strings.TrimPrefix(strings.TrimPrefix(raw, "\r"), "\n")
+	if delim == `'''` {
+		return raw, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(raw); {
+		if raw[i] != '\\' {
+			b.WriteByte(raw[i])
+			i++
+			continue
+		}
+		if j := i + 1 + len(raw[i+1:]) - len(strings.TrimLeft(raw[i+1:], " \t\r")); j < len(raw) && raw[j] == '\n' {
+			i = j + len(raw[j:]) - len(strings.TrimLeft(raw[j:], " \t\r\n"))
+			continue
+		}
+		sub := &tomlParser{s: raw, line: p.line}
+		n, err := sub.escape(&b, i)
+		if err != nil {
+			return "", err
+		}
+		i += n
+	}
+	return b.String(), nil
+}
+
+// escape decodes the escape sequence at s[i] into b and returns its length.
+func (p *tomlParser) escape(b *strings.Builder, i int) (int, error) {
+	if i+1 >= len(p.s) {
+		return 0, p.errorf("unterminated escape")
+	}
+	switch c := p.s[i+1]; c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case 'e':
+		b.WriteByte(0x1b)
+	case '"', '\\':
+		b.WriteByte(c)
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if i+2+n > len(p.s) {
+			return 0, p.errorf("short unicode escape")
+		}
+		r, err := strconv.ParseUint(p.s[i+2:i+2+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return 0, p.errorf("invalid unicode escape %q", p.s[i:i+2+n])
+		}
+		b.WriteRune(rune(r))
+		return 2 + n, nil
+	default:
+		return 0, p.errorf("invalid escape \\%c", c)
+	}
+	return 2, nil
+}
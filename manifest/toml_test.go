@@ -0,0 +1,98 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ─── decodeTOML ───────────────────────────────────────────────────────────────
+
+func TestDecodeTOML(t *testing.T) {
+	doc, err := decodeTOML([]byte(`
+# comment
+title = "demo" # trailing comment
+count = 1_000
+ratio = 0.5
+enabled = true
+released = 1979-05-27T07:32:00Z
+literal = 'C:\path'
+escaped = "tab\there \u00e9"
+multi = """
+first \
+  second"""
+raw = '''
+keep \n'''
+list = [
+  "a",
+  "b", # comment
+]
+inline = { version = "1.0", extras = ["x"] }
+dotted.key = "v"
+"quoted key" = 2
+
+[tool.poetry.dependencies]
+python = "^3.8"
+
+[[package]]
+name = "a"
+
+[package.source]
+url = "https://example.com"
+
+[[package]]
+name = "b"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checks := map[string]any{
+		"title":      "demo",
+		"count":      int64(1000),
+		"ratio":      0.5,
+		"enabled":    true,
+		"released":   "1979-05-27T07:32:00Z",
+		"literal":    `C:\path`,
+		"escaped":    "tab\there é",
+		"multi":      "first second",
+		"raw":        `keep \n`,
+		"list":       []any{"a", "b"},
+		"inline":     map[string]any{"version": "1.0", "extras": []any{"x"}},
+		"dotted":     map[string]any{"key": "v"},
+		"quoted key": int64(2),
+	}
+	for k, want := range checks {
+		if got := doc[k]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %#v, want %#v", k, got, want)
+		}
+	}
+
+	deps := tomlTable(tomlTable(tomlTable(doc, "tool"), "poetry"), "dependencies")
+	if deps["python"] != "^3.8" {
+		t.Errorf("nested table: got %v", deps)
+	}
+	pkgs, _ := doc["package"].([]any)
+	if len(pkgs) != 2 {
+		t.Fatalf("array of tables: got %v", doc["package"])
+	}
+	first := pkgs[0].(map[string]any)
+	if tomlString(tomlTable(first, "source"), "url") != "https://example.com" {
+		t.Errorf("sub-table of array element: got %v", first)
+	}
+}
+
+func TestDecodeTOML_Errors(t *testing.T) {
+	for _, in := range []string{
+		`a = "unterminated`,
+		`a = [1, 2`,
+		`a = 1` + "\n" + `a = 2`,
+		`a = { b = 1`,
+		`[table`,
+		`a = nope`,
+		`a = 1 b = 2`,
+	} {
+		if _, err := decodeTOML([]byte(in)); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
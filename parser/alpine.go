@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/vars"
+)
+
+/* ------------------------- */
+/*      Alpine parser        */
+/* ------------------------- */
+
+// reAlpineDep matches an apk dependency: an optional "!" for a conflict,
+// an optional package name, possibly pinned to a repository with @, an
+// operator and a version, as in "musl>=1.2.3-r4" or "~1.2".
+var reAlpineDep = regexp.MustCompile(`^(!)?([^\s<>=~!]*)(><|<=|>=|=~|~=|<|>|=|~)(\S+)$`)
+
+// reAlpineName matches an apk package name, possibly pinned to a repository
+// with @, as in "musl", "py3-pip@edge" or "so:libc.musl-x86_64.so.1".
+var reAlpineName = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.+:-]*(@[A-Za-z0-9_.-]+)?$`)
+
+// reAlpineOpSpace matches an operator with spaces around it, as in
+// "musl >= 1.2".
+var reAlpineOpSpace = regexp.MustCompile(`\s*(><|<=|>=|=~|~=|<|>|=|~)\s*`)
+
+// ParseAlpine parses apk dependencies as written in /etc/apk/world and
+// APKBUILD depends: "musl=1.2.3-r4", "openssl<3", "zlib~1.2", a bare
+// version ("1.2.3-r0", which means =), or a bare package name (any
+// version). Dependencies separated by spaces or commas are ANDed; package
+// names are ignored, so they are read as constraints on a single package.
+//
+// ~ is apk's fuzzy match, the versions that start with the one given: ~1.2
+// is >=1.2_alpha <1.3_alpha. >< pins a package checksum, which no version
+// list can check, so it is read as the bare package name: any version. A
+// conflict ("!musl<1.2") negates its relation; a conflict with a checksum is
+// left out.
+func ParseAlpine(s string) ([][]vars.Constraint, error) {
+	s = reAlpineOpSpace.ReplaceAllString(strings.TrimSpace(s), "$1")
+	var ands []vars.Constraint
+	for _, dep := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		ands = append(ands, alpineDependency(dep)...)
+	}
+	if len(ands) == 0 {
+		return [][]vars.Constraint{}, nil
+	}
+	return [][]vars.Constraint{ands}, nil
+}
+
+// alpineDependency converts one dependency; it returns nil for one it does
+// not understand: a name that is not a package name, a version apk would
+// not accept, or a conflict without a version.
+func alpineDependency(dep string) []vars.Constraint {
+	m := reAlpineDep.FindStringSubmatch(dep)
+	if m == nil {
+		if _, err := canonicalized.ParseAlpineVersion(dep); err == nil {
+			return []vars.Constraint{{Op: "=", Ver: dep}}
+		}
+		if reAlpineName.MatchString(dep) {
+			return []vars.Constraint{{Op: ">=", Ver: "0"}}
+		}
+		return nil
+	}
+	conflict, name, op, ver := m[1] != "", m[2], m[3], m[4]
+	if name != "" && !reAlpineName.MatchString(name) {
+		return nil
+	}
+	if op != "><" {
+		if _, err := canonicalized.ParseAlpineVersion(ver); err != nil {
+			return nil
+		}
+	}
+
+	var out []vars.Constraint
+	switch op {
+	case "><":
+		if conflict {
+			return nil
+		}
+		return []vars.Constraint{{Op: ">=", Ver: "0"}}
+	case "~", "=~", "~=":
+		v, _ := canonicalized.ParseAlpineVersion(ver)
+		lo, hi, ok := v.FuzzyBounds()
+		if !ok {
+			out = []vars.Constraint{{Op: "=", Ver: ver}}
+			break
+		}
+		if conflict {
+			// Outside the range is an OR, which one group cannot hold;
+			// leave the conflict out.
+			return nil
+		}
+		return []vars.Constraint{{Op: ">=", Ver: lo}, {Op: "<", Ver: hi}}
+	default:
+		out = []vars.Constraint{{Op: op, Ver: ver}}
+	}
+	if conflict {
+		out[0].Op = negated[out[0].Op]
+	}
+	return out
+}
@@ -0,0 +1,295 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/vars"
+)
+
+/* ------------------------- */
+/*     Composer parser       */
+/* ------------------------- */
+
+// reComposerOr splits the || (or legacy |) alternatives of a constraint.
+var reComposerOr = regexp.MustCompile(`\s*\|\|?\s*`)
+
+// reComposerHyphen matches a hyphen range such as "1.0 - 2.0".
+var reComposerHyphen = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+
+// reComposerOpSpace matches an operator followed by spaces, as in ">= 1.2".
+var reComposerOpSpace = regexp.MustCompile(`(>=|<=|<>|!=|==|>|<|=|\^|~)\s+`)
+
+var reComposerOp = regexp.MustCompile(`^(>=|<=|<>|!=|==|>|<|=|\^|~)?(.*)$`)
+
+// reComposerNums splits a version into its numbers and the rest.
+var reComposerNums = regexp.MustCompile(`^[vV]?(\d+)(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(.*)$`)
+
+// reComposerFlag matches a stability flag such as "@dev" or "@beta".
+var reComposerFlag = regexp.MustCompile(`(?i)@(dev|alpha|beta|rc|stable)$`)
+
+// reComposerStable matches the "-stable" modifier, which adds nothing.
+var reComposerStable = regexp.MustCompile(`(?i)[._-]?stable$`)
+
+// ParseComposer parses a Composer constraint: alternatives joined by || or
+// |, comparators joined by spaces or commas, hyphen ranges, ~ and ^,
+// wildcards, stability flags and branch versions such as dev-main.
+//
+// Bounds are normalized as Composer does: an upper bound or a lower bound
+// without a modifier ends in -dev, the lowest pre-release of its version,
+// so ^1.2 is >=1.2-dev <2.0.0-dev. The range therefore excludes
+// 2.0.0-beta1 but admits 1.2.0-beta1; which pre-releases install is left
+// to the stability check, as in Composer. Stability flags do not change
+// the range; see ComposerStability and FilterStability.
+func ParseComposer(s string) ([][]vars.Constraint, error) {
+	// An inline alias, "dev-main as 1.0.x-dev", requires its left side.
+	s, _, ok := ComposerAlias(s)
+	if !ok || s == "" {
+		return [][]vars.Constraint{}, nil
+	}
+
+	out := [][]vars.Constraint{}
+	for _, block := range reComposerOr.Split(s, -1) {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		if m := reComposerHyphen.FindStringSubmatch(block); m != nil {
+			if ands := composerHyphen(m[1], m[2]); ands != nil {
+				out = append(out, ands)
+			}
+			continue
+		}
+		var ands []vars.Constraint
+		block = reComposerOpSpace.ReplaceAllString(block, "$1")
+		for _, tok := range strings.FieldsFunc(block, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			ands = append(ands, composerToken(tok)...)
+		}
+		if len(ands) > 0 {
+			out = append(out, ands)
+		}
+	}
+	return out, nil
+}
+
+// ComposerAlias splits an inline alias, "dev-feature/foo as 1.0.x-dev",
+// into the constraint it requires and the version the installed package is
+// also known as; alias is empty when s has none. ok is false when the alias
+// is not a single valid version, as in "dev-main as" or "1.0 as 2.0 as 3.0".
+func ComposerAlias(s string) (constraint, alias string, ok bool) {
+	constraint, alias, found := strings.Cut(" "+strings.TrimSpace(s)+" ", " as ")
+	if !found {
+		return strings.TrimSpace(s), "", true
+	}
+	constraint, alias = strings.TrimSpace(constraint), strings.TrimSpace(alias)
+	if _, err := canonicalized.ParseComposerVersion(alias); err != nil || strings.ContainsAny(alias, " \t") {
+		return "", "", false
+	}
+	if constraint == "" {
+		return "", "", false
+	}
+	return constraint, alias, true
+}
+
+// composerToken converts one comparator; it returns nil for one it does
+// not understand.
+func composerToken(tok string) []vars.Constraint {
+	tok = reComposerFlag.ReplaceAllString(tok, "")
+	switch tok {
+	case "", "*", "x", "X":
+		return []vars.Constraint{{Op: ">=", Ver: "0.0.0"}}
+	}
+	m := reComposerOp.FindStringSubmatch(tok)
+	op, ver := m[1], m[2]
+
+	// Branches and branch aliases compare by name only.
+	if v, err := canonicalized.ParseComposerVersion(ver); err == nil && (v.Branch != "" || v.Dev && strings.ContainsAny(ver, "xX*")) {
+		if i := strings.Index(ver, "#"); i >= 0 {
+			ver = ver[:i]
+		}
+		switch op {
+		case "", "=", "==":
+			return []vars.Constraint{{Op: "=", Ver: ver}}
+		case "!=", "<>":
+			return []vars.Constraint{{Op: "!=", Ver: ver}}
+		}
+		return nil
+	}
+
+	ver = strings.TrimPrefix(strings.TrimPrefix(reComposerStable.ReplaceAllString(ver, ""), "v"), "V")
+	nums, rest, ok := composerNums(ver)
+	if !ok {
+		return nil
+	}
+
+	// 1.2.* -> >=1.2.0-dev <1.3.0-dev
+	if w := composerWildcardAt(ver); w > 0 {
+		return []vars.Constraint{
+			{Op: ">=", Ver: composerJoin(nums[:w]) + "-dev"},
+			{Op: "<", Ver: composerBump(nums[:w], w-1)},
+		}
+	}
+
+	// The lowest version a lower bound admits.
+	low := ver
+	if rest == "" {
+		low += "-dev"
+	}
+
+	switch op {
+	case "~":
+		// ~1.2 -> <2.0, ~1.2.3 -> <1.3.0
+		return []vars.Constraint{{Op: ">=", Ver: low}, {Op: "<", Ver: composerBump(nums, max(len(nums)-2, 0))}}
+	case "^":
+		// ^1.2.3 -> <2.0.0, ^0.3 -> <0.4.0, ^0.0.3 -> <0.0.4
+		pos := 0
+		switch {
+		case nums[0] != 0 || len(nums) == 1:
+		case nums[1] != 0 || len(nums) == 2:
+			pos = 1
+		default:
+			pos = 2
+		}
+		return []vars.Constraint{{Op: ">=", Ver: low}, {Op: "<", Ver: composerBump(nums, pos)}}
+	case "<":
+		return []vars.Constraint{{Op: "<", Ver: low}}
+	case ">=":
+		return []vars.Constraint{{Op: ">=", Ver: low}}
+	case "<=", ">":
+		return []vars.Constraint{{Op: op, Ver: ver}}
+	case "!=", "<>":
+		return []vars.Constraint{{Op: "!=", Ver: ver}}
+	default:
+		return []vars.Constraint{{Op: "=", Ver: ver}}
+	}
+}
+
+// composerHyphen converts "lo - hi". A partial upper bound covers its whole
+// series: "1.0 - 2.0" is >=1.0-dev <2.1.0-dev.
+func composerHyphen(lo, hi string) []vars.Constraint {
+	lo, hi = reComposerFlag.ReplaceAllString(lo, ""), reComposerFlag.ReplaceAllString(hi, "")
+	lo, hi = strings.TrimLeft(lo, "vV"), strings.TrimLeft(hi, "vV")
+	if _, rest, ok := composerNums(lo); !ok {
+		return nil
+	} else if rest == "" {
+		lo += "-dev"
+	}
+	nums, rest, ok := composerNums(hi)
+	if !ok {
+		return nil
+	}
+	ands := []vars.Constraint{{Op: ">=", Ver: lo}}
+	if len(nums) < 3 && rest == "" {
+		return append(ands, vars.Constraint{Op: "<", Ver: composerBump(nums, len(nums)-1)})
+	}
+	return append(ands, vars.Constraint{Op: "<=", Ver: hi})
+}
+
+// composerNums returns the leading numbers of ver, stopping at a wildcard,
+// and what follows them.
+func composerNums(ver string) ([]int64, string, bool) {
+	m := reComposerNums.FindStringSubmatch(ver)
+	if m == nil {
+		return nil, "", false
+	}
+	var nums []int64
+	for _, p := range m[1:5] {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+	}
+	return nums, m[5], true
+}
+
+// composerWildcardAt returns the index of the x or * part of ver, or -1.
+func composerWildcardAt(ver string) int {
+	for i, p := range strings.Split(ver, ".") {
+		if p == "*" || p == "x" || p == "X" {
+			return i
+		}
+	}
+	return -1
+}
+
+// composerJoin writes nums as a version of at least three parts.
+func composerJoin(nums []int64) string {
+	parts := make([]string, 0, 3)
+	for _, n := range nums {
+		parts = append(parts, strconv.FormatInt(n, 10))
+	}
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return strings.Join(parts, ".")
+}
+
+// composerBump increments nums[pos], drops what follows it and returns the
+// lowest pre-release of the result.
+func composerBump(nums []int64, pos int) string {
+	up := append([]int64(nil), nums[:pos+1]...)
+	up[pos]++
+	return composerJoin(up) + "-dev"
+}
+
+/* ------------------------- */
+/*        Stability          */
+/* ------------------------- */
+
+// ComposerStability returns the minimum stability a root requirement
+// allows for its package, given the project's minimum-stability: a
+// stability flag ("^1.0@beta") or a version the constraint names
+// ("1.0.0-RC1", "dev-main") lowers minimum for that package, as Composer
+// does for root requirements.
+func ComposerStability(constraint, minimum string) string {
+	lowest := composerStabilityName(minimum)
+	lower := func(st string) {
+		if canonicalized.StabilityRank[st] < canonicalized.StabilityRank[lowest] {
+			lowest = st
+		}
+	}
+	for _, tok := range strings.FieldsFunc(constraint, func(r rune) bool { return strings.ContainsRune(" ,|", r) }) {
+		if m := reComposerFlag.FindStringSubmatch(tok); m != nil {
+			lower(composerStabilityName(m[1]))
+			continue
+		}
+		ver := reComposerOp.FindStringSubmatch(tok)[2]
+		if v, err := canonicalized.ParseComposerVersion(ver); err == nil {
+			lower(v.Stability())
+		}
+	}
+	return lowest
+}
+
+// FilterStability returns the versions whose Composer stability is at
+// least minimum.
+func FilterStability(versions []string, minimum string) []string {
+	minimum = composerStabilityName(minimum)
+	var out []string
+	for _, s := range versions {
+		v, err := canonicalized.ParseComposerVersion(s)
+		st := canonicalized.StabilityDev
+		if err == nil {
+			st = v.Stability()
+		}
+		if canonicalized.StabilityRank[st] >= canonicalized.StabilityRank[minimum] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// composerStabilityName returns the canonical spelling of a stability;
+// "" is Composer's default, stable.
+func composerStabilityName(s string) string {
+	if s == "" {
+		return canonicalized.StabilityStable
+	}
+	if strings.EqualFold(s, canonicalized.StabilityRC) {
+		return canonicalized.StabilityRC
+	}
+	return strings.ToLower(s)
+}
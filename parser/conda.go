@@ -0,0 +1,254 @@
+package parser
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/vars"
+)
+
+/* ------------------------- */
+/*      Conda MatchSpec      */
+/* ------------------------- */
+
+// CondaSpec is a conda MatchSpec split into its parts, as in
+// "conda-forge::pytorch=2.0=cuda118*".
+type CondaSpec struct {
+	Channel string
+	Name    string
+	// Version is the version spec, "" for any version. The single "=" form
+	// ("numpy=1.21") is kept as written; ParseConda reads it as 1.21.*.
+	Version string
+	// Build is the build string glob, such as "cuda118*" or "py*".
+	Build string
+}
+
+// reCondaName matches the package name at the start of a MatchSpec.
+var reCondaName = regexp.MustCompile(`^([A-Za-z0-9_][A-Za-z0-9_.-]*)\s*(.*)$`)
+
+// reCondaBracket matches the key=value pairs of a bracket, as in
+// numpy[version='>=1.21',build=py*].
+var reCondaBracket = regexp.MustCompile(`(\w+)\s*=\s*(?:'([^']*)'|"([^"]*)"|([^,\]]*))`)
+
+// reCondaOp matches the operator at the start of a version spec term.
+var reCondaOp = regexp.MustCompile(`^(==|!=|<=|>=|~=|<|>|=)?\s*(.*)$`)
+
+// ParseCondaSpec splits a MatchSpec into channel, name, version and build:
+// "numpy>=1.21,<2|1.19.*", "python 3.10.* *_cpython",
+// "pytorch=2.0=cuda118*", "conda-forge::numpy" and
+// "numpy[version='>=1.21',build=py*]". A spec that starts with a version
+// rather than a name is a bare version spec.
+func ParseCondaSpec(s string) CondaSpec {
+	var spec CondaSpec
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "#"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	if ch, rest, ok := strings.Cut(s, "::"); ok {
+		spec.Channel, s = ch, rest
+	}
+	if open := strings.Index(s, "["); open >= 0 && strings.HasSuffix(s, "]") {
+		for _, m := range reCondaBracket.FindAllStringSubmatch(s[open+1:len(s)-1], -1) {
+			value := strings.TrimSpace(m[2] + m[3] + m[4])
+			switch m[1] {
+			case "version":
+				spec.Version = value
+			case "build":
+				spec.Build = value
+			case "channel":
+				spec.Channel = value
+			}
+		}
+		s = strings.TrimSpace(s[:open])
+	}
+
+	rest := s
+	if s != "" && !strings.ContainsRune("0123456789<>=!~*(", rune(s[0])) {
+		m := reCondaName.FindStringSubmatch(s)
+		if m == nil {
+			return spec
+		}
+		spec.Name, rest = m[1], strings.TrimSpace(m[2])
+	}
+	if rest == "" {
+		return spec
+	}
+
+	// "python 3.10.* *_cpython". Spaces inside the version spec, as in
+	// ">= 1.21, < 2", do not start the build string.
+	if fields := strings.Fields(rest); len(fields) > 1 {
+		ver, i := fields[0], 1
+		// The last term of ver is empty or a bare operator while the spec
+		// goes on.
+		pending := func() bool { return reCondaOp.FindStringSubmatch(ver[strings.LastIndexAny(ver, ",|")+1:])[2] == "" }
+		for i < len(fields) && (pending() || strings.ContainsAny(fields[i][:1], ",|")) {
+			ver += fields[i]
+			i++
+		}
+		spec.Version = ver
+		if i < len(fields) {
+			spec.Build = fields[i]
+		}
+		return spec
+	}
+	// "pytorch=2.0=cuda118*"
+	if strings.HasPrefix(rest, "=") && !strings.HasPrefix(rest, "==") {
+		ver, build, _ := strings.Cut(rest[1:], "=")
+		spec.Version, spec.Build = "="+ver, build
+		return spec
+	}
+	spec.Version = rest
+	return spec
+}
+
+// MatchBuild reports whether build matches the spec's build string glob;
+// a spec without one matches every build.
+func (s CondaSpec) MatchBuild(build string) bool {
+	if s.Build == "" || s.Build == "*" {
+		return true
+	}
+	ok, err := path.Match(s.Build, build)
+	return err == nil && ok
+}
+
+// ParseConda parses a conda MatchSpec or a bare version spec into
+// constraints on its version; the channel, name and build are ignored. In
+// the version spec "|" joins alternatives, "," binds tighter and joins
+// terms, and parentheses group. A term is a version with an operator (==,
+// !=, <, <=, >, >=, ~=), a prefix match (1.19.*, or =1.19 as in
+// "numpy=1.19"), "*" for any version, or a bare version, which is exact.
+//
+// A prefix match becomes a range, 1.19.* being >=1.19* <1.20*; see
+// canonicalized.CondaPrefixBounds.
+//
+// The build string is not a constraint on the version, so
+// "pytorch=2.0=cuda118*" matches every build of 2.0; match builds with
+// ParseCondaSpec and CondaSpec.MatchBuild.
+func ParseConda(s string) ([][]vars.Constraint, error) {
+	spec := ParseCondaSpec(s)
+	if spec.Version == "" {
+		if spec.Name == "" {
+			return [][]vars.Constraint{}, nil
+		}
+		return [][]vars.Constraint{{{Op: ">=", Ver: "0"}}}, nil
+	}
+	p := &condaParser{s: strings.ReplaceAll(spec.Version, " ", "")}
+	out, ok := p.or()
+	if !ok || p.pos != len(p.s) {
+		return [][]vars.Constraint{}, nil
+	}
+	return out, nil
+}
+
+// condaParser reads a version spec by recursive descent; each rule returns
+// its constraints as an OR of ANDs.
+type condaParser struct {
+	s   string
+	pos int
+}
+
+func (p *condaParser) or() ([][]vars.Constraint, bool) {
+	out, ok := p.and()
+	for ok && p.pos < len(p.s) && p.s[p.pos] == '|' {
+		p.pos++
+		var next [][]vars.Constraint
+		next, ok = p.and()
+		out = append(out, next...)
+	}
+	return out, ok
+}
+
+func (p *condaParser) and() ([][]vars.Constraint, bool) {
+	out, ok := p.term()
+	for ok && p.pos < len(p.s) && p.s[p.pos] == ',' {
+		p.pos++
+		var next [][]vars.Constraint
+		next, ok = p.term()
+		var both [][]vars.Constraint
+		for _, a := range out {
+			for _, b := range next {
+				both = append(both, append(append([]vars.Constraint{}, a...), b...))
+			}
+		}
+		out = both
+	}
+	return out, ok
+}
+
+func (p *condaParser) term() ([][]vars.Constraint, bool) {
+	if p.pos < len(p.s) && p.s[p.pos] == '(' {
+		p.pos++
+		out, ok := p.or()
+		if !ok || p.pos >= len(p.s) || p.s[p.pos] != ')' {
+			return nil, false
+		}
+		p.pos++
+		return out, true
+	}
+	end := p.pos
+	for end < len(p.s) && !strings.ContainsRune("|,()", rune(p.s[end])) {
+		end++
+	}
+	tok := p.s[p.pos:end]
+	p.pos = end
+	return condaTerm(tok)
+}
+
+// condaTerm converts one operator and version.
+func condaTerm(tok string) ([][]vars.Constraint, bool) {
+	m := reCondaOp.FindStringSubmatch(tok)
+	op, ver := m[1], m[2]
+	switch ver {
+	case "*":
+		return [][]vars.Constraint{{{Op: ">=", Ver: "0"}}}, true
+	case "":
+		return nil, false
+	}
+	prefix := strings.HasSuffix(ver, "*")
+	if prefix {
+		ver = strings.TrimSuffix(strings.TrimSuffix(ver, "*"), ".")
+	}
+	if _, err := canonicalized.ParseCondaVersion(ver); err != nil {
+		return nil, false
+	}
+	lo, hi := canonicalized.CondaPrefixBounds(ver)
+	within := []vars.Constraint{{Op: ">=", Ver: lo}}
+	if hi != "" {
+		within = append(within, vars.Constraint{Op: "<", Ver: hi})
+	}
+
+	switch op {
+	case "", "==":
+		if prefix {
+			return [][]vars.Constraint{within}, true
+		}
+		return [][]vars.Constraint{{{Op: "=", Ver: ver}}}, true
+	case "=":
+		return [][]vars.Constraint{within}, true
+	case "!=":
+		if !prefix {
+			return [][]vars.Constraint{{{Op: "!=", Ver: ver}}}, true
+		}
+		out := [][]vars.Constraint{{{Op: "<", Ver: lo}}}
+		if hi != "" {
+			out = append(out, []vars.Constraint{{Op: ">=", Ver: hi}})
+		}
+		return out, true
+	case "~=":
+		// ~=1.4.5 is >=1.4.5 and 1.4.*, whose lower bound it is above.
+		i := strings.LastIndexAny(ver, "._")
+		if i < 0 {
+			return nil, false
+		}
+		ands := []vars.Constraint{{Op: ">=", Ver: ver}}
+		if _, hi := canonicalized.CondaPrefixBounds(ver[:i]); hi != "" {
+			ands = append(ands, vars.Constraint{Op: "<", Ver: hi})
+		}
+		return [][]vars.Constraint{ands}, true
+	default:
+		// An ordering against a prefix, ">=1.19.*", drops the ".*".
+		return [][]vars.Constraint{{{Op: op, Ver: ver}}}, true
+	}
+}
@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+/* ------------------------- */
+/*      Debian parser        */
+/* ------------------------- */
+
+// reDebianRelation matches an operator and version, such as ">= 2.17" or
+// "<<2.0".
+var reDebianRelation = regexp.MustCompile(`^(<<|<=|=|>=|>>|<|>)?\s*([0-9][^\s()]*)$`)
+
+// reDebianPackage matches a package name with an optional architecture
+// qualifier, such as "libc6" or "python3:any".
+var reDebianPackage = regexp.MustCompile(`^[a-z0-9][a-z0-9+.-]*(?::[a-z0-9-]+)?$`)
+
+// reDebianRestriction matches architecture restrictions ("[amd64 !i386]")
+// and build profiles ("<!nocheck>"), which do not constrain versions.
+var reDebianRestriction = regexp.MustCompile(`\[[^\]]*\]|<\s*!?[a-z][a-z0-9.+-]*(?:\s+!?[a-z][a-z0-9.+-]*)*\s*>`)
+
+// debianOps maps relation operators to comparators. The obsolete < and >
+// mean <= and >=.
+var debianOps = map[string]string{
+	"<<": "<",
+	"<=": "<=",
+	"=":  "=",
+	">=": ">=",
+	">>": ">",
+	"<":  "<=",
+	">":  ">=",
+	"":   "=",
+}
+
+// ParseDebian parses Debian relation syntax as found in Depends fields and
+// advisories: "libc6 (>= 2.17)", ">= 1.2", "<< 2.0", alternatives joined
+// by | and relations joined by commas, which bind looser than |. Package
+// names are ignored, so the relation is read as constraints on a single
+// package; an alternative without a version allows any version.
+func ParseDebian(s string) ([][]vars.Constraint, error) {
+	s = strings.TrimSpace(reDebianRestriction.ReplaceAllString(s, ""))
+	if s == "" {
+		return [][]vars.Constraint{}, nil
+	}
+
+	// A comma-separated list of alternatives is an AND of ORs; distribute
+	// it into the OR of ANDs the library uses.
+	out := [][]vars.Constraint{{}}
+	for _, rel := range strings.Split(s, ",") {
+		var alts [][]vars.Constraint
+		for _, alt := range strings.Split(rel, "|") {
+			if c, ok := debianRelation(strings.TrimSpace(alt)); ok {
+				alts = append(alts, []vars.Constraint{c})
+			}
+		}
+		if len(alts) == 0 {
+			continue
+		}
+		var next [][]vars.Constraint
+		for _, ands := range out {
+			for _, alt := range alts {
+				next = append(next, append(append([]vars.Constraint{}, ands...), alt...))
+			}
+		}
+		out = next
+	}
+	if len(out) == 1 && len(out[0]) == 0 {
+		return [][]vars.Constraint{}, nil
+	}
+	return out, nil
+}
+
+// debianRelation converts one alternative: "pkg (op version)", "op
+// version", a bare version, or a bare package name.
+func debianRelation(s string) (vars.Constraint, bool) {
+	if open := strings.Index(s, "("); open >= 0 && strings.HasSuffix(s, ")") {
+		name := strings.TrimSpace(s[:open])
+		if name != "" && !reDebianPackage.MatchString(name) {
+			return vars.Constraint{}, false
+		}
+		s = strings.TrimSpace(s[open+1 : len(s)-1])
+	} else if s != "" && reDebianPackage.MatchString(s) && (s[0] < '0' || s[0] > '9') {
+		// A bare package name: any version.
+		return vars.Constraint{Op: ">=", Ver: "0"}, true
+	}
+	m := reDebianRelation.FindStringSubmatch(s)
+	if m == nil {
+		return vars.Constraint{}, false
+	}
+	return vars.Constraint{Op: debianOps[m[1]], Ver: m[2]}, true
+}
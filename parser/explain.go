@@ -0,0 +1,183 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// FormatGroup renders an AND group in normalised comparator form,
+// e.g. ">=1.2.0 <2.0.0".
+func FormatGroup(ands []vars.Constraint) string {
+	parts := make([]string, 0, len(ands))
+	for _, c := range ands {
+		parts = append(parts, c.Op+c.Ver)
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatGroups renders parsed constraint groups, joining OR groups with "||".
+func FormatGroups(parsed [][]vars.Constraint) string {
+	parts := make([]string, 0, len(parsed))
+	for _, g := range parsed {
+		parts = append(parts, FormatGroup(g))
+	}
+	return strings.Join(parts, " || ")
+}
+
+// ExplainMatch checks version against the parsed groups the same way
+// FilterMatches does, recording the outcome of every comparator.
+func ExplainMatch(parsed [][]vars.Constraint, version string) vars.Explanation {
+	return explainMatch(parsed, version, "")
+}
+
+// ExplainMatchFor is ExplainMatch with versions ordered the way style
+// orders them, as FilterMatchesFor does.
+func ExplainMatchFor(style vars.Style, parsed [][]vars.Constraint, version string) vars.Explanation {
+	return explainMatch(parsed, version, style)
+}
+
+func explainMatch(parsed [][]vars.Constraint, version string, style vars.Style) vars.Explanation {
+	e := vars.Explanation{Version: version, Groups: make([]vars.GroupExplanation, 0, len(parsed))}
+	cmp := Comparator(style)
+	var pv canonicalized.Version
+	if cmp == nil {
+		pv = parseVersionFor(style, version)
+	}
+
+	for _, ands := range parsed {
+		g := vars.GroupExplanation{Normalized: FormatGroup(ands), Tried: !e.Satisfied}
+		if g.Tried {
+			g.Checks = explainGroup(&pv, version, ands, style)
+			g.Satisfied = len(g.Checks) > 0
+			for _, c := range g.Checks {
+				g.Satisfied = g.Satisfied && c.Satisfied
+			}
+			e.Satisfied = g.Satisfied
+		}
+		e.Groups = append(e.Groups, g)
+	}
+	return e
+}
+
+func explainGroup(pv *canonicalized.Version, v string, ands []vars.Constraint, style vars.Style) []vars.CheckExplanation {
+	cmp := Comparator(style)
+	for _, c := range ands {
+		if cmp == nil && c.Op == "=" && IsDistTag(c.Ver) {
+			return []vars.CheckExplanation{{Constraint: c, Satisfied: v == c.Ver, Component: "literal"}}
+		}
+	}
+	checks := make([]vars.CheckExplanation, 0, len(ands))
+	for i, pc := range parseGroup(ands, style) {
+		check := vars.CheckExplanation{Constraint: ands[i], Satisfied: satisfiesOne(pv, v, pc, cmp)}
+		if cmp != nil {
+			check.Component = DecidingComponent(style, v, pc.raw)
+		} else {
+			check.Component = decidingComponent(pv, &pc.ver, pc.op == "<core")
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// DecidingComponent names the first component in which versions a and b of
+// style differ: epoch, major, minor, patch, revision, prerelease or equal,
+// or literal for versions the style cannot order. For a style with its own
+// Compare, an epoch (Debian and RPM 1:, conda 1!) comes first and a
+// packaging revision (Debian and RPM -release, apk -rN, conda +local) last.
+func DecidingComponent(style vars.Style, a, b string) string {
+	cmp := Comparator(style)
+	if cmp == nil {
+		pa, pb := parseVersionFor(style, a), parseVersionFor(style, b)
+		return decidingComponent(&pa, &pb, false)
+	}
+	n, ok := cmp(a, b)
+	switch {
+	case !ok && a == b, ok && n == 0:
+		return "equal"
+	case !ok:
+		return "literal"
+	}
+	ea, ua, _ := splitRevision(style, a)
+	eb, ub, _ := splitRevision(style, b)
+	if ea != eb {
+		return "epoch"
+	}
+	if strings.EqualFold(ua, ub) {
+		return "revision"
+	}
+	pa, pb := canonicalized.NewVersion(ua), canonicalized.NewVersion(ub)
+	if c := decidingComponent(&pa, &pb, true); c != "equal" {
+		return c
+	}
+	// The cores agree; what follows them decides: a pre-release (~rc1, a1,
+	// -RC1) on either side, or else a patch level such as _p1 or -p1.
+	if IsPrereleaseFor(style, a) || IsPrereleaseFor(style, b) {
+		return "prerelease"
+	}
+	return "patch"
+}
+
+var (
+	reEpochColon = regexp.MustCompile(`^(\d+):(.*)$`)
+	reEpochBang  = regexp.MustCompile(`^(\d+)!(.*)$`)
+	reApkRev     = regexp.MustCompile(`^(.*)-r(\d+)$`)
+)
+
+// splitRevision splits a version of a style with its own Compare into its
+// epoch ("0" when absent), its upstream version and its packaging revision.
+func splitRevision(style vars.Style, v string) (epoch, upstream, revision string) {
+	epoch, upstream = "0", v
+	switch style {
+	case vars.StyleDebian, vars.StyleRPM:
+		if m := reEpochColon.FindStringSubmatch(v); m != nil {
+			epoch, upstream = m[1], m[2]
+		}
+		if i := strings.LastIndex(upstream, "-"); i >= 0 {
+			upstream, revision = upstream[:i], upstream[i+1:]
+		}
+	case vars.StyleAlpine:
+		if m := reApkRev.FindStringSubmatch(v); m != nil {
+			upstream, revision = m[1], m[2]
+		}
+	case vars.StyleConda:
+		if m := reEpochBang.FindStringSubmatch(v); m != nil {
+			epoch, upstream = m[1], m[2]
+		}
+		upstream, revision, _ = strings.Cut(upstream, "+")
+	}
+	if e := strings.TrimLeft(epoch, "0"); e != "" {
+		epoch = e
+	} else {
+		epoch = "0"
+	}
+	return epoch, upstream, revision
+}
+
+// decidingComponent names the first component in which a and b differ.
+func decidingComponent(a, b *canonicalized.Version, coreOnly bool) string {
+	g := func(p *int64) int64 {
+		if p == nil {
+			return 0
+		}
+		return *p
+	}
+	switch {
+	case g(a.Major) != g(b.Major):
+		return "major"
+	case g(a.Minor) != g(b.Minor):
+		return "minor"
+	case g(a.Patch) != g(b.Patch):
+		return "patch"
+	case coreOnly:
+		return "equal"
+	case g(a.Revision) != g(b.Revision):
+		return "revision"
+	case a.Compare(b) != 0:
+		return "prerelease"
+	default:
+		return "equal"
+	}
+}
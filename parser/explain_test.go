@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ─── Parse ────────────────────────────────────────────────────────────────────
+
+func TestParse_DispatchesByStyle(t *testing.T) {
+	cs, err := Parse(vars.StyleRuby, "~> 2.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := FormatGroups(cs); got != ">=2.1.0 <3.0.0" {
+		t.Errorf("got %q, want %q", got, ">=2.1.0 <3.0.0")
+	}
+}
+
+func TestParse_UnknownStyle(t *testing.T) {
+	if _, err := Parse("cobol", "1.0"); !errors.Is(err, vars.ErrUnknownStyle) {
+		t.Errorf("expected ErrUnknownStyle, got %v", err)
+	}
+}
+
+// ─── FormatGroups ─────────────────────────────────────────────────────────────
+
+func TestFormatGroups_Or(t *testing.T) {
+	cs := [][]vars.Constraint{
+		{{Op: ">=", Ver: "1.0.0"}, {Op: "<", Ver: "2.0.0"}},
+		{{Op: "=", Ver: "3.0.0"}},
+	}
+	if got := FormatGroups(cs); got != ">=1.0.0 <2.0.0 || =3.0.0" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFormatGroups_Empty(t *testing.T) {
+	if got := FormatGroups(nil); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+// ─── ExplainMatch ─────────────────────────────────────────────────────────────
+
+func TestExplainMatch_StopsAtFirstSatisfiedGroup(t *testing.T) {
+	cs := [][]vars.Constraint{
+		{{Op: "<", Ver: "1.0.0"}},
+		{{Op: ">=", Ver: "1.2.0"}, {Op: "<", Ver: "2.0.0"}},
+		{{Op: ">=", Ver: "3.0.0"}},
+	}
+	e := ExplainMatch(cs, "1.5.0")
+	if !e.Satisfied {
+		t.Fatal("1.5.0 should satisfy the second group")
+	}
+	if !e.Groups[0].Tried || e.Groups[0].Satisfied {
+		t.Errorf("group 1: got %+v", e.Groups[0])
+	}
+	if !e.Groups[1].Tried || !e.Groups[1].Satisfied {
+		t.Errorf("group 2: got %+v", e.Groups[1])
+	}
+	if e.Groups[2].Tried || e.Groups[2].Checks != nil {
+		t.Errorf("group 3 should not be tried: got %+v", e.Groups[2])
+	}
+}
+
+func TestExplainMatch_DecidingComponent(t *testing.T) {
+	cs := [][]vars.Constraint{{{Op: ">=", Ver: "1.2.0"}, {Op: "<", Ver: "2.0.0"}}}
+	e := ExplainMatch(cs, "1.5.0-beta.1")
+	checks := e.Groups[0].Checks
+	if len(checks) != 2 {
+		t.Fatalf("expected 2 checks, got %v", checks)
+	}
+	if checks[0].Component != "minor" || !checks[0].Satisfied {
+		t.Errorf("lower bound: got %+v", checks[0])
+	}
+	if checks[1].Component != "major" || !checks[1].Satisfied {
+		t.Errorf("upper bound: got %+v", checks[1])
+	}
+}
+
+func TestExplainMatch_Prerelease(t *testing.T) {
+	cs := [][]vars.Constraint{{{Op: ">=", Ver: "1.0.0"}}}
+	e := ExplainMatch(cs, "1.0.0-rc.1")
+	if e.Satisfied {
+		t.Error("1.0.0-rc.1 should not satisfy >=1.0.0")
+	}
+	if c := e.Groups[0].Checks[0]; c.Component != "prerelease" {
+		t.Errorf("component: got %q, want prerelease", c.Component)
+	}
+}
+
+func TestExplainMatch_CoreOperator(t *testing.T) {
+	cs := [][]vars.Constraint{{{Op: "<core", Ver: "2.0.0"}}}
+	e := ExplainMatch(cs, "2.0.0-rc.1")
+	if e.Satisfied {
+		t.Error("2.0.0-rc.1 should fail <core 2.0.0")
+	}
+	if c := e.Groups[0].Checks[0]; c.Component != "equal" {
+		t.Errorf("component: got %q, want equal", c.Component)
+	}
+}
+
+func TestExplainMatch_Latest(t *testing.T) {
+	cs := [][]vars.Constraint{{{Op: "=", Ver: "latest"}}}
+	e := ExplainMatch(cs, "latest")
+	if !e.Satisfied || e.Groups[0].Checks[0].Component != "literal" {
+		t.Errorf("got %+v", e)
+	}
+}
+
+func TestExplainMatch_AgreesWithFilterMatches(t *testing.T) {
+	cs, _ := ParseNPM("<1.0.0 || >=2.3.1 <2.4.5 || ~3.1")
+	for _, v := range []string{"0.9.0", "1.0.0", "2.3.1", "2.4.5", "3.1.9", "3.2.0", "2.4.0-beta"} {
+		want := len(FilterMatches(cs, []string{v})) == 1
+		if got := ExplainMatch(cs, v).Satisfied; got != want {
+			t.Errorf("%s: ExplainMatch=%v, FilterMatches=%v", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/vars"
+)
+
+/* ------------------------- */
+/*      Gradle parser        */
+/* ------------------------- */
+
+// reGradlePrefix matches a prefix version selector such as 1.+ or 1.2.+.
+var reGradlePrefix = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)*)\.\+$`)
+
+// reGradleRange matches a single range in Gradle's notation, which also
+// accepts ]lo,hi[ for exclusive bounds.
+var reGradleRange = regexp.MustCompile(`^([\[\]\(])([^\[\]\(\),]*),([^\[\]\(\),]*)([\[\]\)])$`)
+
+// ParseGradle parses a Gradle version declaration: an exact version, a
+// prefix selector ("1.+", "+"), latest.release or latest.integration, a
+// Maven-style range ("[1.0,2.0)" or "[1.0,2.0["), or the rich shorthand
+// "strictly!!prefer". See GradleVersion for declarations that also prefer
+// or reject versions.
+//
+// latest.release and latest.integration become the RELEASE and LATEST
+// meta-versions, which only match once resolved from metadata: pass the
+// constraints through ResolveDistTags with the tags of MavenMetaTags or of
+// maven-metadata.xml. Unresolved, they match nothing.
+func ParseGradle(s string) ([][]vars.Constraint, error) {
+	return ParseGradleRich(s).Constraints()
+}
+
+// parseGradleSelector parses one version selector, without rich syntax.
+func parseGradleSelector(s string) ([][]vars.Constraint, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "":
+		return [][]vars.Constraint{}, nil
+	case "+":
+		return [][]vars.Constraint{{{Op: ">=", Ver: "0.0.0"}}}, nil
+	case "latest.integration":
+		// Gradle's statuses map onto the Maven meta-versions of the same
+		// meaning; see MavenMetaTags.
+		return [][]vars.Constraint{{{Op: "=", Ver: "LATEST"}}}, nil
+	case "latest.release":
+		return [][]vars.Constraint{{{Op: "=", Ver: "RELEASE"}}}, nil
+	}
+
+	// 1.+ -> >=1.0.0-alpha <2.0.0, 1.2.+ -> >=1.2.0-alpha <1.3.0
+	if m := reGradlePrefix.FindStringSubmatch(s); m != nil {
+		nums := strings.Split(m[1], ".")
+		lower := ensureThree(m[1])
+		var upper string
+		switch len(nums) {
+		case 1:
+			upper = inc(lower, "major")
+		case 2:
+			upper = inc(lower, "minor")
+		default:
+			upper = inc(lower, "patch")
+		}
+		// The prefix admits the pre-releases of its first version, such as
+		// 1.0.0-rc1 for 1.+ (alpha is the lowest pre-release), and none of
+		// the next version.
+		return [][]vars.Constraint{{{Op: ">=", Ver: lower + "-alpha"}, {Op: "<core", Ver: upper}}}, nil
+	}
+
+	// ]1.0,2.0[ -> (1.0,2.0)
+	if m := reGradleRange.FindStringSubmatch(strings.ReplaceAll(s, " ", "")); m != nil {
+		open, close_ := m[1], m[4]
+		if open == "]" {
+			open = "("
+		}
+		if close_ == "[" {
+			close_ = ")"
+		}
+		s = open + m[2] + "," + m[3] + close_
+	}
+	return ParseMaven(s)
+}
+
+/* ------------------------- */
+/*      Rich versions        */
+/* ------------------------- */
+
+// GradleVersion is a rich version declaration, as written in a version { }
+// block or a version catalog.
+type GradleVersion struct {
+	// Strictly is the only range of versions allowed, even through
+	// conflict resolution.
+	Strictly string `json:"strictly,omitempty"`
+	// Require is the version or range required; with Strictly, a bare
+	// version is a lower bound within it.
+	Require string `json:"require,omitempty"`
+	// Prefer is the version chosen when it is allowed.
+	Prefer string `json:"prefer,omitempty"`
+	// Reject lists versions or ranges that are never chosen.
+	Reject []string `json:"reject,omitempty"`
+	// RejectAll rejects every version.
+	RejectAll bool `json:"rejectAll,omitempty"`
+}
+
+// ParseGradleRich reads a declaration string into a GradleVersion:
+// "1.5!!" is strictly 1.5, "[1.0,2.0[!!1.5" strictly that range preferring
+// 1.5, and anything else is required.
+func ParseGradleRich(s string) GradleVersion {
+	s = strings.TrimSpace(s)
+	if strict, prefer, ok := strings.Cut(s, "!!"); ok {
+		return GradleVersion{Strictly: strings.TrimSpace(strict), Prefer: strings.TrimSpace(prefer)}
+	}
+	return GradleVersion{Require: s}
+}
+
+// String returns the declaration in Gradle's shorthand. The shorthand has
+// no room for rejections, which are left out.
+func (g GradleVersion) String() string {
+	switch {
+	case g.Strictly != "" && g.Prefer != "":
+		return g.Strictly + "!!" + g.Prefer
+	case g.Strictly != "":
+		return g.Strictly + "!!"
+	case g.Require != "":
+		return g.Require
+	default:
+		return g.Prefer
+	}
+}
+
+// Constraints returns the versions the declaration allows: Strictly (else
+// Require) less every rejected version. A declaration with only Prefer
+// allows any version.
+func (g GradleVersion) Constraints() ([][]vars.Constraint, error) {
+	if g.RejectAll {
+		return [][]vars.Constraint{}, nil
+	}
+	var (
+		groups [][]vars.Constraint
+		err    error
+	)
+	switch {
+	case g.Strictly != "":
+		groups, err = parseGradleSelector(g.Strictly)
+		if err == nil && isBareVersion(g.Require) {
+			groups = andAll(groups, vars.Constraint{Op: ">=", Ver: ensureThreePrerelease(g.Require)})
+		}
+	case g.Require != "":
+		groups, err = parseGradleSelector(g.Require)
+	case g.Prefer != "":
+		groups = [][]vars.Constraint{{{Op: ">=", Ver: "0.0.0"}}}
+	default:
+		return [][]vars.Constraint{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range g.Reject {
+		rejected, err := parseGradleSelector(r)
+		if err != nil {
+			return nil, fmt.Errorf("reject %q: %w", r, err)
+		}
+		for _, ands := range rejected {
+			groups = exclude(groups, ands)
+		}
+	}
+	return groups, nil
+}
+
+// Select picks the version of versions the declaration resolves to: Prefer
+// when it is allowed, then a bare Require, otherwise the highest allowed
+// version. latest.release and latest.integration resolve through
+// MavenMetaTags. It returns "" when nothing is allowed.
+func (g GradleVersion) Select(versions []string) string {
+	groups, err := g.Constraints()
+	if err != nil {
+		return ""
+	}
+	groups = ResolveDistTags(groups, MavenMetaTags(versions))
+	allowed := FilterMatchesFor(vars.StyleGradle, groups, versions)
+	for _, want := range []string{g.Prefer, g.Require} {
+		if want == "" || !isBareVersion(want) {
+			continue
+		}
+		w := canonicalized.ParseMavenVersion(want)
+		for _, v := range allowed {
+			if pv := canonicalized.ParseMavenVersion(v); pv.Equal(&w) {
+				return v
+			}
+		}
+	}
+	if sorted := SortVersionsFor(vars.StyleGradle, allowed, true); len(sorted) > 0 {
+		return sorted[0]
+	}
+	return ""
+}
+
+// MavenMetaTags computes what maven-metadata.xml would tag from a version
+// list: LATEST is the highest version and RELEASE the highest that is not
+// a snapshot.
+func MavenMetaTags(versions []string) map[string]string {
+	tags := map[string]string{}
+	for _, v := range SortVersionsFor(vars.StyleMaven, versions, true) {
+		if _, ok := tags["LATEST"]; !ok {
+			tags["LATEST"] = v
+		}
+		if !strings.HasSuffix(strings.ToUpper(v), "-SNAPSHOT") && !reMavenSnapshotBuild.MatchString(v) {
+			tags["RELEASE"] = v
+			break
+		}
+	}
+	return tags
+}
+
+// andAll adds c to every group.
+func andAll(groups [][]vars.Constraint, c vars.Constraint) [][]vars.Constraint {
+	out := make([][]vars.Constraint, len(groups))
+	for i, ands := range groups {
+		out[i] = append(append([]vars.Constraint{}, ands...), c)
+	}
+	return out
+}
+
+// negated maps each operator to its complement. "<core" is complemented
+// by ">=", which leaves out the pre-releases of its version.
+var negated = map[string]string{
+	"=":     "!=",
+	"!=":    "=",
+	"<":     ">=",
+	"<=":    ">",
+	">":     "<=",
+	">=":    "<",
+	"<core": ">=",
+}
+
+// exclude removes the versions matched by the AND group rejected from
+// groups: G and not (c1 and c2) is (G and not c1) or (G and not c2).
+// Unresolved tags cannot be negated and are not excluded.
+func exclude(groups [][]vars.Constraint, rejected []vars.Constraint) [][]vars.Constraint {
+	if len(rejected) == 0 {
+		return groups
+	}
+	for _, c := range rejected {
+		if _, ok := negated[c.Op]; !ok || (c.Op == "=" && IsDistTag(c.Ver)) {
+			return groups
+		}
+	}
+	var out [][]vars.Constraint
+	for _, c := range rejected {
+		out = append(out, andAll(groups, vars.Constraint{Op: negated[c.Op], Ver: c.Ver})...)
+	}
+	return out
+}
@@ -11,6 +11,10 @@ import (
 /*      Maven parser         */
 /* ------------------------- */
 
+// reMavenSnapshotBuild matches a timestamped snapshot build such as
+// 1.0-20230815.123045-7.
+var reMavenSnapshotBuild = regexp.MustCompile(`^[0-9]+(?:\.[0-9]+)*(?:-[A-Za-z0-9.]+)?-[0-9]{8}\.[0-9]{6}-[0-9]+$`)
+
 // reMavenExact matches a single exact version in brackets: [1.2.3] or [1.2.3-rc.1]
 var reMavenExact = regexp.MustCompile(`^\s*\[\s*([0-9]+(?:\.[0-9]+)*(?:-[A-Za-z0-9]+(?:\.[A-Za-z0-9]+)*)?)\s*\]\s*$`)
 
@@ -20,41 +24,41 @@ func ParseMaven(s string) ([][]vars.Constraint, error) {
 		return [][]vars.Constraint{}, nil
 	}
 
+	// LATEST and RELEASE are meta-versions naming what maven-metadata.xml
+	// tags; they resolve like dist-tags (see ResolveDistTags).
+	if s == "LATEST" || s == "RELEASE" {
+		return [][]vars.Constraint{{{Op: "=", Ver: s}}}, nil
+	}
+
+	// Timestamped snapshot build, kept whole: 1.0-20230815.123045-7
+	if reMavenSnapshotBuild.MatchString(s) {
+		return [][]vars.Constraint{{{Op: "=", Ver: s}}}, nil
+	}
+
 	// Exact version: [1.2.3] or [1.2.3-rc.1]
 	if m := reMavenExact.FindStringSubmatch(s); m != nil {
 		return [][]vars.Constraint{{{Op: "=", Ver: ensureThreePrerelease(m[1])}}}, nil
 	}
 
-	// Try converting to bracket range.
-	// This handles both bracket ranges ([lo,hi), etc.) and comparison-operator
-	// forms like ">= 9.0.0-preview.1, <= 9.0.0-rc.1".
-	rangeStr, err := ConstraintToRange(s)
-	if err == nil && rangeStr != "" {
-		if m := vars.ReNuGetRange.FindStringSubmatch(rangeStr); m != nil {
-			open := m[1]
-			lo := strings.TrimSpace(m[2])
-			hi := strings.TrimSpace(m[3])
-			close_ := m[4]
-			var ands []vars.Constraint
-			if lo != "" {
-				if open == "[" {
-					ands = append(ands, vars.Constraint{Op: ">=", Ver: ensureThreePrerelease(lo)})
-				} else {
-					ands = append(ands, vars.Constraint{Op: ">", Ver: ensureThreePrerelease(lo)})
-				}
+	// Union of ranges: (,1.0],[1.2,) -> one OR group per range
+	if ranges := splitMavenRanges(s); len(ranges) > 1 {
+		var out [][]vars.Constraint
+		for _, r := range ranges {
+			if ands, ok := mavenRange(r); ok && len(ands) > 0 {
+				out = append(out, ands)
 			}
-			if hi != "" {
-				if close_ == "]" {
-					ands = append(ands, vars.Constraint{Op: "<=", Ver: ensureThreePrerelease(hi)})
-				} else {
-					ands = append(ands, vars.Constraint{Op: "<", Ver: ensureThreePrerelease(hi)})
-				}
-			}
-			if len(ands) == 0 {
-				return [][]vars.Constraint{}, nil
-			}
-			return [][]vars.Constraint{ands}, nil
 		}
+		if len(out) == 0 {
+			return [][]vars.Constraint{}, nil
+		}
+		return out, nil
+	}
+
+	if ands, ok := mavenRange(s); ok {
+		if len(ands) == 0 {
+			return [][]vars.Constraint{}, nil
+		}
+		return [][]vars.Constraint{ands}, nil
 	}
 
 	// Bare version (with optional pre-release) = exact constraint
@@ -64,3 +68,63 @@ func ParseMaven(s string) ([][]vars.Constraint, error) {
 
 	return [][]vars.Constraint{}, nil
 }
+
+// reMavenRangeItem matches one bracketed range or exact version inside a union.
+var reMavenRangeItem = regexp.MustCompile(`[\[\(][^\]\)]*[\]\)]`)
+
+// splitMavenRanges splits a comma-separated union of ranges like
+// "(,1.0],[1.2,)". It returns nil unless s consists only of ranges.
+func splitMavenRanges(s string) []string {
+	items := reMavenRangeItem.FindAllString(s, -1)
+	if len(items) == 0 {
+		return nil
+	}
+	rest := s
+	for _, it := range items {
+		rest = strings.Replace(rest, it, "", 1)
+	}
+	if strings.Trim(rest, ", \t") != "" {
+		return nil
+	}
+	return items
+}
+
+// mavenRange converts a single range, exact bracket or comparison-operator
+// form into one AND group.
+func mavenRange(s string) ([]vars.Constraint, bool) {
+	if m := reMavenExact.FindStringSubmatch(s); m != nil {
+		return []vars.Constraint{{Op: "=", Ver: ensureThreePrerelease(m[1])}}, true
+	}
+
+	// Try converting to bracket range.
+	// This handles both bracket ranges ([lo,hi), etc.) and comparison-operator
+	// forms like ">= 9.0.0-preview.1, <= 9.0.0-rc.1".
+	rangeStr, err := ConstraintToRange(s)
+	if err != nil || rangeStr == "" {
+		return nil, false
+	}
+	m := vars.ReNuGetRange.FindStringSubmatch(rangeStr)
+	if m == nil {
+		return nil, false
+	}
+	open := m[1]
+	lo := strings.TrimSpace(m[2])
+	hi := strings.TrimSpace(m[3])
+	close_ := m[4]
+	var ands []vars.Constraint
+	if lo != "" {
+		if open == "[" {
+			ands = append(ands, vars.Constraint{Op: ">=", Ver: ensureThreePrerelease(lo)})
+		} else {
+			ands = append(ands, vars.Constraint{Op: ">", Ver: ensureThreePrerelease(lo)})
+		}
+	}
+	if hi != "" {
+		if close_ == "]" {
+			ands = append(ands, vars.Constraint{Op: "<=", Ver: ensureThreePrerelease(hi)})
+		} else {
+			ands = append(ands, vars.Constraint{Op: "<", Ver: ensureThreePrerelease(hi)})
+		}
+	}
+	return ands, true
+}
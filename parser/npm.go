@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -48,26 +49,89 @@ func expandWildcardNpm(ver string) []vars.Constraint {
 	return []vars.Constraint{{Op: "=", Ver: ver}}
 }
 
+// reNpmRepoShorthand matches the GitHub "user/repo" shorthand, optionally
+// followed by a "#ref".
+var reNpmRepoShorthand = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+(#\S*)?$`)
+
+// npmSourcePrefixes are dependency specifiers that point at a location
+// rather than a registry version.
+var npmSourcePrefixes = []string{
+	"http://", "https://", "file:", "git+", "git://", "github:", "gitlab:",
+	"bitbucket:", "gist:", "link:", "portal:", "workspace:",
+}
+
+// IsNpmSource reports whether an npm dependency specifier is a URL, git,
+// file or workspace source instead of a version range.
+func IsNpmSource(s string) bool {
+	low := strings.ToLower(strings.TrimSpace(s))
+	for _, p := range npmSourcePrefixes {
+		if strings.HasPrefix(low, p) {
+			return true
+		}
+	}
+	return reNpmRepoShorthand.MatchString(low)
+}
+
+// reDistTag matches npm dist-tag names such as "latest", "next" or "beta-2".
+var reDistTag = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]*$`)
+
+// reNpmName matches a package name, optionally scoped.
+var reNpmName = regexp.MustCompile(`(?i)^(@[a-z0-9~][a-z0-9._~-]*/)?[a-z0-9~][a-z0-9._~-]*$`)
+
+// IsDistTag reports whether s names a dist-tag rather than a version or a
+// range. npm tags cannot look like versions, so "v1.2.0" and the "x"
+// wildcard are not tags.
+func IsDistTag(s string) bool {
+	if !reDistTag.MatchString(s) || s == "x" || s == "X" {
+		return false
+	}
+	return !((s[0] == 'v' || s[0] == 'V') && len(s) > 1 && s[1] >= '0' && s[1] <= '9')
+}
+
+// ResolveDistTags returns groups with every dist-tag constraint, such as
+// {"=", "next"}, replaced by the version tags maps it to. Tags missing from
+// tags are kept; they only match a version literally named after them.
+func ResolveDistTags(groups [][]vars.Constraint, tags map[string]string) [][]vars.Constraint {
+	if len(tags) == 0 {
+		return groups
+	}
+	out := make([][]vars.Constraint, len(groups))
+	for i, ands := range groups {
+		out[i] = make([]vars.Constraint, len(ands))
+		for j, c := range ands {
+			if v, ok := tags[c.Ver]; ok && c.Op == "=" && IsDistTag(c.Ver) {
+				c.Ver = v
+			}
+			out[i][j] = c
+		}
+	}
+	return out
+}
+
 func ParseNPM(s string) ([][]vars.Constraint, error) {
 	s = strings.TrimSpace(s)
 
-	// special literal: latest
-	if s == "latest" {
-		return [][]vars.Constraint{{{Op: "=", Ver: "latest"}}}, nil
+	// dist-tags: "latest", "next", ... stand for the version they point at
+	if IsDistTag(s) {
+		return [][]vars.Constraint{{{Op: "=", Ver: s}}}, nil
 	}
 
-	// npm:pkg@1.0.0 -> extract version
+	// npm:pkg@^1.0.0 -> parse the range of the aliased package
 	if strings.HasPrefix(s, "npm:") {
 		at := strings.LastIndex(s, "@")
-		if at > -1 && at+1 < len(s) {
-			ver := s[at+1:]
-			return [][]vars.Constraint{{{Op: "=", Ver: ver}}}, nil
+		if at > len("npm:") && at+1 < len(s) {
+			return ParseNPM(s[at+1:])
 		}
 		return [][]vars.Constraint{}, nil
 	}
 
-	// ignore http/file sources
-	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "file:") {
+	// pkg@next or pkg@^1.0.0, as given to npm install
+	if at := strings.LastIndex(s, "@"); at > 0 && at+1 < len(s) && reNpmName.MatchString(s[:at]) {
+		return ParseNPM(s[at+1:])
+	}
+
+	// ignore URL, git, file and workspace sources
+	if IsNpmSource(s) {
 		return nil, vars.ErrUnsupportedSource
 	}
 
@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"github.com/rng70/versions/v2/vars"
+)
+
+// Parse dispatches s to the constraint parser of style.
+func Parse(style vars.Style, s string) ([][]vars.Constraint, error) {
+	switch style {
+	case vars.StyleNPM:
+		return ParseNPM(s)
+	case vars.StylePy:
+		return ParsePython(s)
+	case vars.StyleNuGet:
+		return ParseNuGet(s)
+	case vars.StyleMaven:
+		return ParseMaven(s)
+	case vars.StyleGradle:
+		return ParseGradle(s)
+	case vars.StyleRuby:
+		return ParseRuby(s)
+	case vars.StyleRust:
+		return ParseRust(s)
+	case vars.StyleGo:
+		return ParseGo(s)
+	case vars.StyleComposer:
+		return ParseComposer(s)
+	case vars.StyleDebian:
+		return ParseDebian(s)
+	case vars.StyleRPM:
+		return ParseRPM(s)
+	case vars.StyleAlpine:
+		return ParseAlpine(s)
+	case vars.StyleConda:
+		return ParseConda(s)
+	default:
+		return nil, vars.ErrUnknownStyle
+	}
+}
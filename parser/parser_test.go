@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/rng70/versions/v2/vars"
@@ -840,6 +842,56 @@ func TestParseNPM_NpmAlias(t *testing.T) {
 	}
 }
 
+func TestParseNPM_DistTag(t *testing.T) {
+	for _, s := range []string{"next", "beta", "canary", "npm:alias@beta", "pkg@next", "@scope/pkg@next"} {
+		cs, err := ParseNPM(s)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", s, err)
+		}
+		want := s[strings.LastIndex(s, "@")+1:]
+		if len(cs) != 1 || len(cs[0]) != 1 || cs[0][0].Op != "=" || cs[0][0].Ver != want {
+			t.Errorf("%q: unexpected result %v", s, cs)
+		}
+	}
+}
+
+func TestParseNPM_PackageSpec(t *testing.T) {
+	cs, err := ParseNPM("@scope/pkg@^1.2.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cs) != 1 || len(cs[0]) != 2 || cs[0][0].Ver != "1.2.0" || cs[0][1].Ver != "2.0.0" {
+		t.Errorf("scoped spec: unexpected result %v", cs)
+	}
+}
+
+func TestIsDistTag(t *testing.T) {
+	for s, want := range map[string]bool{
+		"latest": true, "next": true, "beta-2": true, "release-8": true,
+		"v1.2.0": false, "v8.x": false, "x": false, "1.0.0": false, "^1.0.0": false, "": false, "a b": false,
+	} {
+		if got := IsDistTag(s); got != want {
+			t.Errorf("IsDistTag(%q): got %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestResolveDistTags(t *testing.T) {
+	cs := [][]vars.Constraint{{{Op: "=", Ver: "next"}}, {{Op: "=", Ver: "gone"}}, {{Op: ">=", Ver: "1.0.0"}}}
+	got := ResolveDistTags(cs, map[string]string{"next": "2.0.0-rc.1", "latest": "1.4.0"})
+	if got[0][0].Ver != "2.0.0-rc.1" || got[1][0].Ver != "gone" || got[2][0].Ver != "1.0.0" {
+		t.Errorf("unexpected result %v", got)
+	}
+	if cs[0][0].Ver != "next" {
+		t.Error("input groups were modified")
+	}
+
+	// An unresolved tag matches only a version literally named after it.
+	if m := FilterMatches(got, []string{"gone", "1.0.0", "2.0.0-rc.1"}); len(m) != 3 {
+		t.Errorf("matches: got %v", m)
+	}
+}
+
 func TestParseNPM_HttpURL(t *testing.T) {
 	_, err := ParseNPM("http://example.com/pkg.tgz")
 	if err == nil {
@@ -990,6 +1042,23 @@ func TestParsePython_GteOnly(t *testing.T) {
 	}
 }
 
+func TestParsePython_Sources(t *testing.T) {
+	for _, s := range []string{
+		"@ https://example.com/pkg-1.0.whl",
+		"git+https://github.com/user/repo.git@v1.0",
+		"file:///tmp/pkg",
+		"./vendor/pkg",
+		"../pkg",
+	} {
+		if _, err := ParsePython(s); !errors.Is(err, vars.ErrUnsupportedSource) {
+			t.Errorf("%q: got %v, want ErrUnsupportedSource", s, err)
+		}
+	}
+	if _, err := ParsePython(">=1.0, <2.0"); err != nil {
+		t.Errorf("range should not be a source: %v", err)
+	}
+}
+
 // ─── ParseNuGet ───────────────────────────────────────────────────────────────
 
 func TestParseNuGet_Empty(t *testing.T) {
@@ -1198,6 +1267,14 @@ func TestParseRuby_Compound(t *testing.T) {
 	}
 }
 
+func TestParseRuby_Sources(t *testing.T) {
+	for _, s := range []string{"git+https://github.com/rails/rails", "path+../engine"} {
+		if _, err := ParseRuby(s); !errors.Is(err, vars.ErrUnsupportedSource) {
+			t.Errorf("%q: got %v, want ErrUnsupportedSource", s, err)
+		}
+	}
+}
+
 func TestParseRuby_LT(t *testing.T) {
 	cs, err := ParseRuby("< 3.0.0")
 	if err != nil {
@@ -1230,6 +1307,14 @@ func TestParseRust_BareVersion(t *testing.T) {
 	}
 }
 
+func TestParseRust_Sources(t *testing.T) {
+	for _, s := range []string{"git+https://github.com/u/r?tag=v1", "path+file:///src/crate"} {
+		if _, err := ParseRust(s); !errors.Is(err, vars.ErrUnsupportedSource) {
+			t.Errorf("%q: got %v, want ErrUnsupportedSource", s, err)
+		}
+	}
+}
+
 func TestParseRust_WildcardStar(t *testing.T) {
 	cs, err := ParseRust("*")
 	if err != nil {
@@ -1544,6 +1629,33 @@ func TestParseMaven_BareVersion(t *testing.T) {
 	}
 }
 
+func TestParseMaven_MetaVersions(t *testing.T) {
+	for _, s := range []string{"LATEST", "RELEASE", "1.0-20230815.123045-7"} {
+		cs, err := ParseMaven(s)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", s, err)
+		}
+		if len(cs) != 1 || cs[0][0].Op != "=" || cs[0][0].Ver != s {
+			t.Errorf("%q: got %v", s, cs)
+		}
+	}
+}
+
+func TestFilterMatchesFor_MavenSnapshotBuilds(t *testing.T) {
+	versions := []string{"0.9", "1.0-20230815.123045-7", "1.0-20230816.010101-1", "1.0-SNAPSHOT", "1.0"}
+	cs, _ := ParseMaven("[1.0-20230816.010101-1,1.0)")
+	want := []string{"1.0-20230816.010101-1", "1.0-SNAPSHOT"}
+	if got := FilterMatchesFor(vars.StyleMaven, cs, versions); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := SortVersionsFor(vars.StyleGradle, versions, true); got[0] != "1.0" || got[1] != "1.0-SNAPSHOT" || got[4] != "0.9" {
+		t.Errorf("sorted: got %v", got)
+	}
+	if !IsPrereleaseFor(vars.StyleMaven, "1.0-20230815.123045-7") {
+		t.Error("a snapshot build is a pre-release")
+	}
+}
+
 func TestParseMaven_OperatorGTE(t *testing.T) {
 	cs, err := ParseMaven(">= 1.0.0")
 	if err != nil {
@@ -1575,3 +1687,647 @@ func assertLegacyNums(t *testing.T, nums []int, major, minor, patch int) {
 		t.Errorf("got %v, want [%d %d %d]", nums[:3], major, minor, patch)
 	}
 }
+
+// ─── ParseMaven unions ────────────────────────────────────────────────────────
+
+func TestParseMaven_Union(t *testing.T) {
+	cs, err := ParseMaven("(,1.0],[1.2,)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cs) != 2 {
+		t.Fatalf("union: expected 2 groups, got %v", cs)
+	}
+	if cs[0][0] != (vars.Constraint{Op: "<=", Ver: "1.0.0"}) || cs[1][0] != (vars.Constraint{Op: ">=", Ver: "1.2.0"}) {
+		t.Errorf("union: got %v", cs)
+	}
+}
+
+func TestParseMaven_UnionWithExact(t *testing.T) {
+	cs, err := ParseMaven("[1.0], [1.5,2.0)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cs) != 2 || cs[0][0].Op != "=" || len(cs[1]) != 2 {
+		t.Errorf("union with exact: got %v", cs)
+	}
+}
+
+func TestParseMaven_UnionWithGarbage(t *testing.T) {
+	cs, err := ParseMaven("[1.0,2.0) or [3.0,)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cs) != 0 {
+		t.Errorf("garbage between ranges: expected 0 groups, got %v", cs)
+	}
+}
+
+// ─── npm sources and aliases ──────────────────────────────────────────────────
+
+func TestParseNPM_AliasRange(t *testing.T) {
+	cs, err := ParseNPM("npm:@scope/pkg@^1.2.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cs) != 1 || len(cs[0]) != 2 || cs[0][0].Ver != "1.2.0" || cs[0][1].Ver != "2.0.0" {
+		t.Errorf("npm alias range: unexpected result %v", cs)
+	}
+}
+
+func TestParseNPM_GitSources(t *testing.T) {
+	for _, s := range []string{
+		"git+ssh://git@github.com/npm/cli.git#v1.0.27",
+		"github:user/repo",
+		"expressjs/express#4.x",
+		"link:../lib",
+		"workspace:*",
+	} {
+		if _, err := ParseNPM(s); !errors.Is(err, vars.ErrUnsupportedSource) {
+			t.Errorf("%q: expected ErrUnsupportedSource, got %v", s, err)
+		}
+	}
+}
+
+// ─── ParseGradle ──────────────────────────────────────────────────────────────
+
+var gradleVersions = []string{
+	"1.0.0", "1.4.0", "1.5.0", "1.9.0", "2.0.0-rc.1", "2.0.0", "2.1.0", "2.2.0-SNAPSHOT",
+}
+
+func TestParseGradle_Matches(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []string
+	}{
+		{"1.5.0", []string{"1.5.0"}},
+		{"+", gradleVersions},
+		{"1.+", []string{"1.0.0", "1.4.0", "1.5.0", "1.9.0"}},
+		{"2.0.+", []string{"2.0.0-rc.1", "2.0.0"}},
+		{"[1.4,2.0)", []string{"1.4.0", "1.5.0", "1.9.0", "2.0.0-rc.1"}},
+		{"[1.4,2.0[", []string{"1.4.0", "1.5.0", "1.9.0", "2.0.0-rc.1"}},
+		{"]1.4,1.9]", []string{"1.5.0", "1.9.0"}},
+		{"[2.0,)", []string{"2.0.0", "2.1.0", "2.2.0-SNAPSHOT"}},
+		{"[1.0,1.5]!!1.4", []string{"1.0.0", "1.4.0", "1.5.0"}},
+		{"1.9.0!!", []string{"1.9.0"}},
+		{"", nil},
+	} {
+		cs, err := ParseGradle(tc.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.in, err)
+		}
+		if got := FilterMatches(cs, gradleVersions); strings.Join(got, " ") != strings.Join(tc.want, " ") {
+			t.Errorf("%q: got %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseGradle_LatestStatus(t *testing.T) {
+	for in, want := range map[string]string{"latest.release": "RELEASE", "latest.integration": "LATEST"} {
+		cs, err := ParseGradle(in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", in, err)
+		}
+		if len(cs) != 1 || len(cs[0]) != 1 || cs[0][0] != (vars.Constraint{Op: "=", Ver: want}) {
+			t.Errorf("%q: got %v, want =%s", in, cs, want)
+		}
+	}
+}
+
+func TestParseGradle_PrefixPrerelease(t *testing.T) {
+	versions := []string{"0.9.0", "1.0.0-alpha1", "1.0.0-rc1", "1.0.0", "1.9.0", "2.0.0-rc1", "2.0.0"}
+	cs, _ := ParseGradle("1.+")
+	want := []string{"1.0.0-alpha1", "1.0.0-rc1", "1.0.0", "1.9.0"}
+	if got := FilterMatchesFor(vars.StyleGradle, cs, versions); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseGradle_LatestStatusNeedsTags(t *testing.T) {
+	for in, want := range map[string]string{"latest.release": "2.1.0", "latest.integration": "2.2.0-SNAPSHOT"} {
+		cs, _ := ParseGradle(in)
+		if got := FilterMatchesFor(vars.StyleGradle, cs, gradleVersions); len(got) != 0 {
+			t.Errorf("%q without tags: got %v, want no matches", in, got)
+		}
+		resolved := ResolveDistTags(cs, MavenMetaTags(gradleVersions))
+		if got := FilterMatchesFor(vars.StyleGradle, resolved, gradleVersions); len(got) != 1 || got[0] != want {
+			t.Errorf("%q with tags: got %v, want [%s]", in, got, want)
+		}
+	}
+}
+
+func TestMavenMetaTags(t *testing.T) {
+	tags := MavenMetaTags(gradleVersions)
+	if tags["LATEST"] != "2.2.0-SNAPSHOT" || tags["RELEASE"] != "2.1.0" {
+		t.Errorf("got %v, want LATEST=2.2.0-SNAPSHOT RELEASE=2.1.0", tags)
+	}
+}
+
+func TestParseGradleRich(t *testing.T) {
+	for in, want := range map[string]GradleVersion{
+		"1.5":            {Require: "1.5"},
+		"1.5!!":          {Strictly: "1.5"},
+		"[1.0,2.0[!!1.5": {Strictly: "[1.0,2.0[", Prefer: "1.5"},
+	} {
+		got := ParseGradleRich(in)
+		if got.Strictly != want.Strictly || got.Require != want.Require || got.Prefer != want.Prefer {
+			t.Errorf("%q: got %+v, want %+v", in, got, want)
+		}
+		if got.String() != in {
+			t.Errorf("%q: String got %q", in, got.String())
+		}
+	}
+}
+
+// ─── GradleVersion ────────────────────────────────────────────────────────────
+
+func TestGradleVersion_Reject(t *testing.T) {
+	g := GradleVersion{Require: "[1.0,2.0)", Reject: []string{"1.5.0", "[1.8,1.9]"}}
+	cs, err := g.Constraints()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := FilterMatches(cs, gradleVersions)
+	want := []string{"1.0.0", "1.4.0", "2.0.0-rc.1"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestGradleVersion_RejectAll(t *testing.T) {
+	cs, err := GradleVersion{Require: "+", RejectAll: true}.Constraints()
+	if err != nil || len(cs) != 0 {
+		t.Errorf("got %v, %v; want no groups", cs, err)
+	}
+}
+
+func TestGradleVersion_Select(t *testing.T) {
+	for _, tc := range []struct {
+		g    GradleVersion
+		want string
+	}{
+		// prefer wins when allowed, the highest allowed version otherwise
+		{GradleVersion{Strictly: "[1.0,2.0)", Prefer: "1.4"}, "1.4.0"},
+		{GradleVersion{Strictly: "[1.0,2.0)", Prefer: "2.1"}, "2.0.0-rc.1"},
+		{GradleVersion{Strictly: "1.+", Prefer: "1.4", Reject: []string{"1.4.0"}}, "1.9.0"},
+		// a required version within strictly is picked, and is a minimum
+		{GradleVersion{Strictly: "[1.0,2.0)", Require: "1.5"}, "1.5.0"},
+		{GradleVersion{Strictly: "[1.0,2.0)", Require: "1.5", Prefer: "1.4"}, "1.5.0"},
+		{GradleVersion{Prefer: "1.9"}, "1.9.0"},
+		{GradleVersion{Require: "latest.release"}, "2.1.0"},
+		{GradleVersion{Require: "latest.integration"}, "2.2.0-SNAPSHOT"},
+		{GradleVersion{Require: "3.0"}, ""},
+	} {
+		if got := tc.g.Select(gradleVersions); got != tc.want {
+			t.Errorf("%+v: got %q, want %q", tc.g, got, tc.want)
+		}
+	}
+}
+
+// ─── ParseComposer ────────────────────────────────────────────────────────────
+
+var composerVersions = []string{
+	"dev-main", "1.0.0", "1.1.0-beta1", "1.2.0", "1.2.5", "1.2.5-p1", "1.3.0", "2.0.0-RC1", "2.0.0", "2.1.0", "3.0.0",
+}
+
+func TestParseComposer_Matches(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []string
+	}{
+		{"1.2.5", []string{"1.2.5"}},
+		{"v1.2.5", []string{"1.2.5"}},
+		{"^1.2", []string{"1.2.0", "1.2.5", "1.2.5-p1", "1.3.0"}},
+		{"~1.2", []string{"1.2.0", "1.2.5", "1.2.5-p1", "1.3.0"}},
+		{"~1.2.3", []string{"1.2.5", "1.2.5-p1"}},
+		{"^0.3", nil},
+		{"1.2.*", []string{"1.2.0", "1.2.5", "1.2.5-p1"}},
+		{"2.x", []string{"2.0.0-RC1", "2.0.0", "2.1.0"}},
+		{"*", composerVersions[1:]},
+		{">=1.2 <2.0", []string{"1.2.0", "1.2.5", "1.2.5-p1", "1.3.0"}},
+		{">= 1.2, < 2.0", []string{"1.2.0", "1.2.5", "1.2.5-p1", "1.3.0"}},
+		{"<2.0.0-RC2", []string{"1.0.0", "1.1.0-beta1", "1.2.0", "1.2.5", "1.2.5-p1", "1.3.0", "2.0.0-RC1"}},
+		{"1.0 - 1.2", []string{"1.0.0", "1.1.0-beta1", "1.2.0", "1.2.5", "1.2.5-p1"}},
+		{"1.0.0 - 1.2.0", []string{"1.0.0", "1.1.0-beta1", "1.2.0"}},
+		{"^1.0 || ^3.0", []string{"1.0.0", "1.1.0-beta1", "1.2.0", "1.2.5", "1.2.5-p1", "1.3.0", "3.0.0"}},
+		{"1.0.0 | 3.0.0", []string{"1.0.0", "3.0.0"}},
+		{"^2.0@RC", []string{"2.0.0-RC1", "2.0.0", "2.1.0"}},
+		{"2.0.0-RC1", []string{"2.0.0-RC1"}},
+		{"!=1.2.5 ^1.2", []string{"1.2.0", "1.2.5-p1", "1.3.0"}},
+		{"dev-main", []string{"dev-main"}},
+		{"dev-main#8f3b2c1", []string{"dev-main"}},
+		{"dev-main as 1.0.x-dev", []string{"dev-main"}},
+		{"dev-feature/foo as 1.0.x-dev", nil},
+		{"1.2.5-stable", []string{"1.2.5"}},
+	} {
+		cs, err := ParseComposer(tc.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.in, err)
+		}
+		got := FilterMatchesFor(vars.StyleComposer, cs, composerVersions)
+		if strings.Join(got, " ") != strings.Join(tc.want, " ") {
+			t.Errorf("%q: got %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseComposer_Bounds(t *testing.T) {
+	for in, want := range map[string]string{
+		"^1.2.3":        ">=1.2.3-dev <2.0.0-dev",
+		"^0.3":          ">=0.3-dev <0.4.0-dev",
+		"^0.0.3":        ">=0.0.3-dev <0.0.4-dev",
+		"~1":            ">=1-dev <2.0.0-dev",
+		"~1.2.3":        ">=1.2.3-dev <1.3.0-dev",
+		"1.2.*":         ">=1.2.0-dev <1.3.0-dev",
+		">=1.2":         ">=1.2-dev",
+		">=1.2-beta1":   ">=1.2-beta1",
+		">1.2":          ">1.2",
+		"<1.2":          "<1.2-dev",
+		"1.0 - 2.0":     ">=1.0-dev <2.1.0-dev",
+		"1.0-RC1 - 2.0": ">=1.0-RC1 <2.1.0-dev",
+	} {
+		cs, _ := ParseComposer(in)
+		var parts []string
+		for _, ands := range cs {
+			for _, c := range ands {
+				parts = append(parts, c.Op+c.Ver)
+			}
+		}
+		if got := strings.Join(parts, " "); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestComposerAlias(t *testing.T) {
+	for _, tc := range []struct {
+		in, constraint, alias string
+		ok                    bool
+	}{
+		{"dev-feature/foo as 1.0.x-dev", "dev-feature/foo", "1.0.x-dev", true},
+		{"dev-main as 2.1.0", "dev-main", "2.1.0", true},
+		{"^1.2", "^1.2", "", true},
+		{"dev-main as", "", "", false},
+		{"dev-main as not-a-version", "", "", false},
+		{"1.0 as 2.0 as 3.0", "", "", false},
+		{" as 1.0.x-dev", "", "", false},
+	} {
+		constraint, alias, ok := ComposerAlias(tc.in)
+		if constraint != tc.constraint || alias != tc.alias || ok != tc.ok {
+			t.Errorf("%q: got %q, %q, %v; want %q, %q, %v", tc.in, constraint, alias, ok, tc.constraint, tc.alias, tc.ok)
+		}
+	}
+	if cs, err := ParseComposer("dev-main as not-a-version"); err != nil || len(cs) != 0 {
+		t.Errorf("malformed alias: got %v, %v; want no groups", cs, err)
+	}
+}
+
+func TestComposerStability(t *testing.T) {
+	for _, tc := range []struct{ constraint, minimum, want string }{
+		{"^1.0", "stable", "stable"},
+		{"^1.0", "", "stable"},
+		{"^1.0@beta", "stable", "beta"},
+		{"^1.0@beta", "dev", "dev"},
+		{"1.0.0-RC1", "stable", "RC"},
+		{"dev-main", "stable", "dev"},
+		{"^1.0 || 2.0.0-alpha1", "stable", "alpha"},
+	} {
+		if got := ComposerStability(tc.constraint, tc.minimum); got != tc.want {
+			t.Errorf("%q with %q: got %q, want %q", tc.constraint, tc.minimum, got, tc.want)
+		}
+	}
+}
+
+func TestParseComposer_StabilityFlag(t *testing.T) {
+	versions := []string{"1.9.0", "2.0.0-alpha1", "2.0.0-beta1", "2.0.0-RC1", "2.0.0", "3.0.0-beta1"}
+	for _, tc := range []struct {
+		in   string
+		want []string
+	}{
+		{"^2.0@beta", []string{"2.0.0-beta1", "2.0.0-RC1", "2.0.0"}},
+		{"^2.0", []string{"2.0.0"}},
+		{">=2.0@alpha", []string{"2.0.0-alpha1", "2.0.0-beta1", "2.0.0-RC1", "2.0.0", "3.0.0-beta1"}},
+		{"2.0.0-beta1 - 2.0", []string{"2.0.0-beta1", "2.0.0-RC1", "2.0.0"}},
+	} {
+		cs, err := ParseComposer(tc.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.in, err)
+		}
+		stable := FilterStability(versions, ComposerStability(tc.in, ""))
+		if got := FilterMatchesFor(vars.StyleComposer, cs, stable); strings.Join(got, " ") != strings.Join(tc.want, " ") {
+			t.Errorf("%q: got %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestFilterStability(t *testing.T) {
+	got := FilterStability(composerVersions, "RC")
+	want := []string{"1.0.0", "1.2.0", "1.2.5", "1.2.5-p1", "1.3.0", "2.0.0-RC1", "2.0.0", "2.1.0", "3.0.0"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSortVersionsFor_Composer(t *testing.T) {
+	got := SortVersionsFor(vars.StyleComposer, []string{"1.0.0-p1", "dev-main", "1.0.0", "1.0.0-RC1"}, true)
+	want := []string{"1.0.0-p1", "1.0.0", "1.0.0-RC1", "dev-main"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// ─── ParseDebian ──────────────────────────────────────────────────────────────
+
+var debianVersions = []string{"2.30-0ubuntu1~20.04.1", "2.30-0ubuntu1", "2.31-0ubuntu9.9", "1:1.0-1"}
+
+func TestParseDebian(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+		hits []string
+	}{
+		{">= 2.30-0ubuntu1", ">=2.30-0ubuntu1", []string{"2.30-0ubuntu1", "2.31-0ubuntu9.9", "1:1.0-1"}},
+		{"<< 2.30-0ubuntu1", "<2.30-0ubuntu1", []string{"2.30-0ubuntu1~20.04.1"}},
+		{">> 2.30-0ubuntu1", ">2.30-0ubuntu1", []string{"2.31-0ubuntu9.9", "1:1.0-1"}},
+		{"libc6 (>= 2.30)", ">=2.30", []string{"2.30-0ubuntu1~20.04.1", "2.30-0ubuntu1", "2.31-0ubuntu9.9", "1:1.0-1"}},
+		{"libc6:amd64 (= 2.31-0ubuntu9.9)", "=2.31-0ubuntu9.9", []string{"2.31-0ubuntu9.9"}},
+		{"(<< 2.31) | (>= 1:0)", "<2.31 | >=1:0", []string{"2.30-0ubuntu1~20.04.1", "2.30-0ubuntu1", "1:1.0-1"}},
+		{"libc6 (>= 2.30), libc6 (<< 2.31)", ">=2.30 <2.31", []string{"2.30-0ubuntu1~20.04.1", "2.30-0ubuntu1"}},
+		{"a (<< 2.30) | b (>> 2.31), c (<= 1:1.0-1)", "<2.30 <=1:1.0-1 | >2.31 <=1:1.0-1", []string{"2.31-0ubuntu9.9", "1:1.0-1"}},
+		{"2.30-0ubuntu1", "=2.30-0ubuntu1", []string{"2.30-0ubuntu1"}},
+		{"(< 2.30-0ubuntu1)", "<=2.30-0ubuntu1", []string{"2.30-0ubuntu1~20.04.1", "2.30-0ubuntu1"}},
+		{"libc6 (>= 2.31) [amd64] <!nocheck>", ">=2.31", []string{"2.31-0ubuntu9.9", "1:1.0-1"}},
+		{"libc6", ">=0", debianVersions},
+		{"", "", nil},
+	} {
+		cs, err := ParseDebian(tc.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.in, err)
+		}
+		var groups []string
+		for _, ands := range cs {
+			var parts []string
+			for _, c := range ands {
+				parts = append(parts, c.Op+c.Ver)
+			}
+			groups = append(groups, strings.Join(parts, " "))
+		}
+		if got := strings.Join(groups, " | "); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.in, got, tc.want)
+		}
+		if got := FilterMatchesFor(vars.StyleDebian, cs, debianVersions); strings.Join(got, " ") != strings.Join(tc.hits, " ") {
+			t.Errorf("%q: matched %v, want %v", tc.in, got, tc.hits)
+		}
+	}
+}
+
+func TestParseDebian_Garbage(t *testing.T) {
+	cs, err := ParseDebian("libc6 (~> 2.30)")
+	if err != nil || len(cs) != 0 {
+		t.Errorf("got %v, %v; want no groups", cs, err)
+	}
+}
+
+// ─── ParseRPM ─────────────────────────────────────────────────────────────────
+
+var rpmVersions = []string{"1:1.1.1k-5.el8_5", "1:1.1.1k-7.el8_6", "1:1.1.1k-12.el8_9", "1:3.0.7-1.el9", "1.1.1k-12.el8_9"}
+
+func TestParseRPM(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+		hits []string
+	}{
+		{"openssl >= 1:1.1.1k-7.el8_6", ">=1:1.1.1k-7.el8_6", []string{"1:1.1.1k-7.el8_6", "1:1.1.1k-12.el8_9", "1:3.0.7-1.el9"}},
+		{"< 1:1.1.1k-7.el8_6", "<1:1.1.1k-7.el8_6", []string{"1:1.1.1k-5.el8_5", "1.1.1k-12.el8_9"}},
+		{"openssl-libs = 1:1.1.1k", "=1:1.1.1k", []string{"1:1.1.1k-5.el8_5", "1:1.1.1k-7.el8_6", "1:1.1.1k-12.el8_9"}},
+		{"openssl == 1:1.1.1k-5.el8_5", "=1:1.1.1k-5.el8_5", []string{"1:1.1.1k-5.el8_5"}},
+		{"(openssl >= 1:1.1.1k-7.el8_6 with openssl < 1:3)", ">=1:1.1.1k-7.el8_6 <1:3", []string{"1:1.1.1k-7.el8_6", "1:1.1.1k-12.el8_9"}},
+		{"(openssl < 1:1.1.1k-7 or openssl >= 1:3)", "<1:1.1.1k-7 | >=1:3", []string{"1:1.1.1k-5.el8_5", "1:3.0.7-1.el9", "1.1.1k-12.el8_9"}},
+		{"openssl >= 1:1.1.1k, openssl < 1:1.1.1k-12", ">=1:1.1.1k <1:1.1.1k-12", []string{"1:1.1.1k-5.el8_5", "1:1.1.1k-7.el8_6"}},
+		{"less than 1:1.1.1k-12.el8_9", "<1:1.1.1k-12.el8_9", []string{"1:1.1.1k-5.el8_5", "1:1.1.1k-7.el8_6", "1.1.1k-12.el8_9"}},
+		{"greater than or equal 1:3.0.7-1.el9", ">=1:3.0.7-1.el9", []string{"1:3.0.7-1.el9"}},
+		{"perl(Carp) >= 1:3", ">=1:3", []string{"1:3.0.7-1.el9"}},
+		{"1.1.1k-12.el8_9", "=1.1.1k-12.el8_9", []string{"1.1.1k-12.el8_9"}},
+		{"openssl", ">=0", rpmVersions},
+		{"", "", nil},
+	} {
+		cs, err := ParseRPM(tc.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.in, err)
+		}
+		var groups []string
+		for _, ands := range cs {
+			var parts []string
+			for _, c := range ands {
+				parts = append(parts, c.Op+c.Ver)
+			}
+			groups = append(groups, strings.Join(parts, " "))
+		}
+		if got := strings.Join(groups, " | "); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.in, got, tc.want)
+		}
+		if got := FilterMatchesFor(vars.StyleRPM, cs, rpmVersions); strings.Join(got, " ") != strings.Join(tc.hits, " ") {
+			t.Errorf("%q: matched %v, want %v", tc.in, got, tc.hits)
+		}
+	}
+}
+
+func TestParseRPM_Nested(t *testing.T) {
+	versions := []string{"0.1-1", "0.5-1", "0.9-1", "1.0-1", "2.0-1", "3.0-1"}
+	for _, tc := range []struct {
+		in   string
+		hits []string
+	}{
+		{"((foo < 1.0 or foo >= 2.0) with foo >= 0.5)", []string{"0.5-1", "0.9-1", "2.0-1", "3.0-1"}},
+		{"(foo >= 0.5 with (foo < 1.0 or foo >= 2.0))", []string{"0.5-1", "0.9-1", "2.0-1", "3.0-1"}},
+		{"((foo >= 0.5 with foo < 1.0) or (foo >= 2.0 with foo < 3.0))", []string{"0.5-1", "0.9-1", "2.0-1"}},
+		{"(perl(Carp) >= 1.0 with perl(Carp) < 3.0)", []string{"1.0-1", "2.0-1"}},
+	} {
+		cs, err := ParseRPM(tc.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.in, err)
+		}
+		if got := FilterMatchesFor(vars.StyleRPM, cs, versions); strings.Join(got, " ") != strings.Join(tc.hits, " ") {
+			t.Errorf("%q: matched %v, want %v", tc.in, got, tc.hits)
+		}
+	}
+}
+
+func TestParseRPM_Unbalanced(t *testing.T) {
+	for _, in := range []string{"(foo >= 1.0 with foo < 2.0", "foo >= 1.0)", "((foo < 1.0) or foo >= 2.0"} {
+		if cs, err := ParseRPM(in); err == nil {
+			t.Errorf("%q: got %v, want an error", in, cs)
+		}
+	}
+}
+
+func TestParseRPM_Garbage(t *testing.T) {
+	cs, err := ParseRPM("openssl >= ")
+	if err != nil || len(cs) != 0 {
+		t.Errorf("got %v, %v; want no groups", cs, err)
+	}
+}
+
+// ─── ParseAlpine ──────────────────────────────────────────────────────────────
+
+var alpineVersions = []string{"1.2.3_rc1-r0", "1.2.3-r0", "1.2.3-r4", "1.2.3_p1-r0", "1.2.4-r0", "1.20.0-r0"}
+
+func TestParseAlpine(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+		hits []string
+	}{
+		{"musl=1.2.3-r4", "=1.2.3-r4", []string{"1.2.3-r4"}},
+		{"musl<1.2.3-r4", "<1.2.3-r4", []string{"1.2.3_rc1-r0", "1.2.3-r0"}},
+		{"musl>1.2.3-r4", ">1.2.3-r4", []string{"1.2.3_p1-r0", "1.2.4-r0", "1.20.0-r0"}},
+		{"musl >= 1.2.3_p1", ">=1.2.3_p1", []string{"1.2.3_p1-r0", "1.2.4-r0", "1.20.0-r0"}},
+		{"musl~1.2", ">=1.2_alpha <1.3_alpha", []string{"1.2.3_rc1-r0", "1.2.3-r0", "1.2.3-r4", "1.2.3_p1-r0", "1.2.4-r0"}},
+		{"musl=~1.2.3", ">=1.2.3_alpha <1.2.4_alpha", []string{"1.2.3_rc1-r0", "1.2.3-r0", "1.2.3-r4", "1.2.3_p1-r0"}},
+		{"~1.2.3-r4", "=1.2.3-r4", []string{"1.2.3-r4"}},
+		{"musl@edge>=1.2.4", ">=1.2.4", []string{"1.2.4-r0", "1.20.0-r0"}},
+		{"so:libc.musl-x86_64.so.1>=1.2.4", ">=1.2.4", []string{"1.2.4-r0", "1.20.0-r0"}},
+		{"musl>=1.2.3 musl<1.2.4", ">=1.2.3 <1.2.4", []string{"1.2.3-r0", "1.2.3-r4", "1.2.3_p1-r0"}},
+		{"!musl<1.2.4", ">=1.2.4", []string{"1.2.4-r0", "1.20.0-r0"}},
+		{"musl><Q1Xo7ldU0TQsOC2UMdcDnwsGsFXSE=", ">=0", alpineVersions},
+		{"musl>=1.2.4 !musl><Q1Xo7ldU0TQsOC2UMdcDnwsGsFXSE=", ">=1.2.4", []string{"1.2.4-r0", "1.20.0-r0"}},
+		{"1.2.3-r0", "=1.2.3-r0", []string{"1.2.3-r0"}},
+		{"musl", ">=0", alpineVersions},
+		{"", "", nil},
+	} {
+		cs, err := ParseAlpine(tc.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.in, err)
+		}
+		var groups []string
+		for _, ands := range cs {
+			var parts []string
+			for _, c := range ands {
+				parts = append(parts, c.Op+c.Ver)
+			}
+			groups = append(groups, strings.Join(parts, " "))
+		}
+		if got := strings.Join(groups, " | "); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.in, got, tc.want)
+		}
+		if got := FilterMatchesFor(vars.StyleAlpine, cs, alpineVersions); strings.Join(got, " ") != strings.Join(tc.hits, " ") {
+			t.Errorf("%q: matched %v, want %v", tc.in, got, tc.hits)
+		}
+	}
+}
+
+func TestParseAlpine_Garbage(t *testing.T) {
+	for _, in := range []string{
+		"musl~not-a-version !busybox",
+		"garbage!!",
+		"|||",
+		">= ",
+		"=> 1.0",
+		"musl>=1.0.0.x",
+		"mu$l>=1.0",
+	} {
+		cs, err := ParseAlpine(in)
+		if err != nil || len(cs) != 0 {
+			t.Errorf("%q: got %v, %v; want no groups", in, cs, err)
+		}
+	}
+}
+
+// ─── ParseConda ───────────────────────────────────────────────────────────────
+
+func TestParseCondaSpec(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want CondaSpec
+	}{
+		{"numpy>=1.21,<2|1.19.*", CondaSpec{Name: "numpy", Version: ">=1.21,<2|1.19.*"}},
+		{"python 3.10.*", CondaSpec{Name: "python", Version: "3.10.*"}},
+		{"python 3.10.* *_cpython", CondaSpec{Name: "python", Version: "3.10.*", Build: "*_cpython"}},
+		{"pytorch=2.0=cuda118*", CondaSpec{Name: "pytorch", Version: "=2.0", Build: "cuda118*"}},
+		{"conda-forge::numpy >= 1.21, < 2", CondaSpec{Channel: "conda-forge", Name: "numpy", Version: ">=1.21,<2"}},
+		{"numpy[version='>=1.21',build=py*]", CondaSpec{Name: "numpy", Version: ">=1.21", Build: "py*"}},
+		{"libgcc-ng  # runtime", CondaSpec{Name: "libgcc-ng"}},
+		{">=1.21", CondaSpec{Version: ">=1.21"}},
+	} {
+		if got := ParseCondaSpec(tc.in); got != tc.want {
+			t.Errorf("%q: got %+v, want %+v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestCondaSpec_MatchBuild(t *testing.T) {
+	spec := ParseCondaSpec("pytorch=2.0=cuda118*")
+	if !spec.MatchBuild("cuda118py310h1234567_0") || spec.MatchBuild("cpu_py310h1234567_0") {
+		t.Errorf("build glob %q matched wrongly", spec.Build)
+	}
+	if !ParseCondaSpec("pytorch").MatchBuild("anything") {
+		t.Error("a spec without a build should match every build")
+	}
+}
+
+var condaVersions = []string{"1.19.0rc1", "1.19.5", "1.20.3", "1.21.0", "1.26.4", "2.0.0", "2.0.1"}
+
+func TestParseConda(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+		hits []string
+	}{
+		{"numpy>=1.21,<2|1.19.*", ">=1.21 <2 | >=1.19* <1.20*", []string{"1.19.0rc1", "1.19.5", "1.21.0", "1.26.4"}},
+		{"numpy 1.19.*", ">=1.19* <1.20*", []string{"1.19.0rc1", "1.19.5"}},
+		{"numpy=1.19", ">=1.19* <1.20*", []string{"1.19.0rc1", "1.19.5"}},
+		{"pytorch=2.0=cuda118*", ">=2.0* <2.1*", []string{"2.0.0", "2.0.1"}},
+		{"numpy==2.0", "=2.0", []string{"2.0.0"}},
+		{"numpy 2.0.1", "=2.0.1", []string{"2.0.1"}},
+		{"numpy!=1.19.*", "<1.19* | >=1.20*", []string{"1.20.3", "1.21.0", "1.26.4", "2.0.0", "2.0.1"}},
+		{"numpy~=1.20.3", ">=1.20.3 <1.21*", []string{"1.20.3"}},
+		{"numpy>=1.20.*", ">=1.20", []string{"1.20.3", "1.21.0", "1.26.4", "2.0.0", "2.0.1"}},
+		{"numpy (>=1.21|<1.20),<2", ">=1.21 <2 | <1.20 <2", []string{"1.19.0rc1", "1.19.5", "1.21.0", "1.26.4"}},
+		{"numpy *", ">=0", condaVersions},
+		{"numpy", ">=0", condaVersions},
+		{"", "", nil},
+	} {
+		cs, err := ParseConda(tc.in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.in, err)
+		}
+		var groups []string
+		for _, ands := range cs {
+			var parts []string
+			for _, c := range ands {
+				parts = append(parts, c.Op+c.Ver)
+			}
+			groups = append(groups, strings.Join(parts, " "))
+		}
+		if got := strings.Join(groups, " | "); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.in, got, tc.want)
+		}
+		if got := FilterMatchesFor(vars.StyleConda, cs, condaVersions); strings.Join(got, " ") != strings.Join(tc.hits, " ") {
+			t.Errorf("%q: matched %v, want %v", tc.in, got, tc.hits)
+		}
+	}
+}
+
+func TestParseConda_BuildIgnored(t *testing.T) {
+	versions := []string{"1.13.1", "2.0.0", "2.0.1", "2.1.0"}
+	withBuild, _ := ParseConda("pytorch=2.0=cuda118*")
+	without, _ := ParseConda("pytorch=2.0")
+	got, want := FilterMatchesFor(vars.StyleConda, withBuild, versions), FilterMatchesFor(vars.StyleConda, without, versions)
+	if strings.Join(got, " ") != strings.Join(want, " ") || len(got) != 2 {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	spec := ParseCondaSpec("pytorch=2.0=cuda118*")
+	if !spec.MatchBuild("cuda118_py311h1234_0") || spec.MatchBuild("cpu_py311h1234_0") {
+		t.Errorf("build %q: MatchBuild disagrees", spec.Build)
+	}
+}
+
+func TestParseConda_Garbage(t *testing.T) {
+	for _, in := range []string{"numpy>=1.21,", "numpy (>=1.21", "numpy>=1-2"} {
+		cs, err := ParseConda(in)
+		if err != nil || len(cs) != 0 {
+			t.Errorf("%q: got %v, %v; want no groups", in, cs, err)
+		}
+	}
+}
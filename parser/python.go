@@ -11,11 +11,33 @@ import (
 /*      Python parser        */
 /* ------------------------- */
 
+// pySourcePrefixes are requirement specifiers that point at a location
+// rather than an index version.
+var pySourcePrefixes = []string{
+	"@", "http://", "https://", "file:", "git+", "hg+", "svn+", "bzr+", "./", "../", "/",
+}
+
+// IsPythonSource reports whether a Python requirement specifier is a PEP 508
+// direct reference ("@ https://..."), a VCS URL or a local path instead of a
+// version specifier.
+func IsPythonSource(s string) bool {
+	low := strings.ToLower(strings.TrimSpace(s))
+	for _, p := range pySourcePrefixes {
+		if strings.HasPrefix(low, p) {
+			return true
+		}
+	}
+	return low == "." || strings.Contains(low, "://")
+}
+
 func ParsePython(s string) ([][]vars.Constraint, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return [][]vars.Constraint{}, nil
 	}
+	if IsPythonSource(s) {
+		return nil, vars.ErrUnsupportedSource
+	}
 	parts := strings.Split(s, ",")
 	var ands []vars.Constraint
 	for _, part := range parts {
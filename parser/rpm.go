@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+/* ------------------------- */
+/*        RPM parser         */
+/* ------------------------- */
+
+// reRPMRelation matches an optional package name, an operator and an EVR,
+// such as "openssl >= 1:1.1.1k" or "<2.0".
+var reRPMRelation = regexp.MustCompile(`^(?:[A-Za-z_][^\s<>=!]*\s*)?(<=|>=|==|!=|=|<|>)\s*([0-9A-Za-z]\S*)$`)
+
+// reRPMName matches a bare package name or capability, such as "bash" or
+// "perl(Carp)".
+var reRPMName = regexp.MustCompile(`^[A-Za-z_][^\s<>=!]*$`)
+
+// reRPMOr splits the alternatives of a rich dependency.
+var reRPMOr = regexp.MustCompile(`\s+or\s+`)
+
+// reRPMAnd splits the terms of a rich dependency.
+var reRPMAnd = regexp.MustCompile(`\s+(?:and|with)\s+|,`)
+
+// rpmOvalOps maps the operations of OVAL rpminfo tests, longest first.
+var rpmOvalOps = []struct{ name, op string }{
+	{"greater than or equal", ">="},
+	{"less than or equal", "<="},
+	{"greater than", ">"},
+	{"less than", "<"},
+	{"not equal", "!="},
+	{"equals", "="},
+}
+
+// ParseRPM parses RPM dependency relations as found in Requires: and in
+// advisories: "openssl >= 1:1.1.1k", "< 2:1.8.0-3.el8_4", rich dependencies
+// such as "(foo >= 1.0 with foo < 2.0)" and "(foo < 1.0 or foo >= 2.0)",
+// and OVAL operations written out ("less than 0:1.8.0-3.el8_4"). Package
+// names are ignored, so the relation is read as constraints on a single
+// package. A relation without a release matches every release of its
+// version, as in rpm. Nested rich dependencies keep their grouping, and
+// unbalanced parentheses are an error.
+func ParseRPM(s string) ([][]vars.Constraint, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	for _, o := range rpmOvalOps {
+		if strings.HasPrefix(lower, o.name) {
+			s = o.op + s[len(o.name):]
+			break
+		}
+	}
+	if s == "" {
+		return [][]vars.Constraint{}, nil
+	}
+	return rpmExpr(s)
+}
+
+// rpmExpr parses a rich dependency into an OR of ANDs: "or" binds loosest,
+// and a parenthesised term is parsed recursively and distributed over the
+// terms beside it.
+func rpmExpr(s string) ([][]vars.Constraint, error) {
+	masked, err := rpmMask(s)
+	if err != nil {
+		return nil, err
+	}
+	out := [][]vars.Constraint{}
+	for _, alt := range rpmSplit(s, masked, reRPMOr) {
+		// The identity of AND: one empty group.
+		ands := [][]vars.Constraint{{}}
+		empty := true
+		for _, term := range rpmSplit(alt.s, alt.masked, reRPMAnd) {
+			sub, err := rpmTerm(strings.TrimSpace(term.s))
+			if err != nil {
+				return nil, err
+			}
+			if len(sub) == 0 {
+				continue
+			}
+			ands = rpmCross(ands, sub)
+			empty = false
+		}
+		if !empty {
+			out = append(out, ands...)
+		}
+	}
+	return out, nil
+}
+
+// rpmTerm parses one term: a parenthesised rich dependency, or a relation.
+func rpmTerm(s string) ([][]vars.Constraint, error) {
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		if inner := s[1 : len(s)-1]; rpmBalanced(inner) {
+			return rpmExpr(inner)
+		}
+	}
+	if c, ok := rpmRelation(s); ok {
+		return [][]vars.Constraint{{c}}, nil
+	}
+	return nil, nil
+}
+
+// rpmCross distributes AND over two OR-of-ANDs.
+func rpmCross(a, b [][]vars.Constraint) [][]vars.Constraint {
+	out := make([][]vars.Constraint, 0, len(a)*len(b))
+	for _, x := range a {
+		for _, y := range b {
+			group := make([]vars.Constraint, 0, len(x)+len(y))
+			out = append(out, append(append(group, x...), y...))
+		}
+	}
+	return out
+}
+
+// rpmPart is a slice of a relation and its masked copy.
+type rpmPart struct{ s, masked string }
+
+// rpmSplit splits s where sep matches its masked copy, so separators inside
+// parentheses are left to the recursive call.
+func rpmSplit(s, masked string, sep *regexp.Regexp) []rpmPart {
+	var parts []rpmPart
+	last := 0
+	for _, m := range sep.FindAllStringIndex(masked, -1) {
+		parts = append(parts, rpmPart{s[last:m[0]], masked[last:m[0]]})
+		last = m[1]
+	}
+	return append(parts, rpmPart{s[last:], masked[last:]})
+}
+
+// rpmMask blanks out everything inside parentheses, keeping the parentheses
+// themselves, so that only top-level separators remain visible.
+func rpmMask(s string) (string, error) {
+	b := []byte(s)
+	depth := 0
+	for i, c := range b {
+		switch {
+		case c == '(':
+			depth++
+			if depth > 1 {
+				b[i] = '_'
+			}
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return "", fmt.Errorf("unbalanced parentheses: %s", s)
+			}
+			if depth > 0 {
+				b[i] = '_'
+			}
+		case depth > 0:
+			b[i] = '_'
+		}
+	}
+	if depth != 0 {
+		return "", fmt.Errorf("unbalanced parentheses: %s", s)
+	}
+	return string(b), nil
+}
+
+// rpmBalanced reports whether the parentheses of s balance without the
+// depth dropping below zero, so "(a) or (b)" is not taken for one group.
+func rpmBalanced(s string) bool {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// rpmRelation converts one term: "name op evr", "op evr", a bare EVR, or a
+// bare name.
+func rpmRelation(s string) (vars.Constraint, bool) {
+	if m := reRPMRelation.FindStringSubmatch(s); m != nil {
+		op := m[1]
+		if op == "==" {
+			op = "="
+		}
+		return vars.Constraint{Op: op, Ver: m[2]}, true
+	}
+	switch {
+	case s == "" || strings.ContainsAny(s, " \t<>=!"):
+		return vars.Constraint{}, false
+	case reRPMName.MatchString(s):
+		// A bare name: any version.
+		return vars.Constraint{Op: ">=", Ver: "0"}, true
+	}
+	return vars.Constraint{Op: "=", Ver: s}, true
+}
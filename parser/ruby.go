@@ -16,11 +16,21 @@ import (
 // The version may carry a SemVer pre-release suffix like "-preview.1.24081.5".
 var reRubyPart = regexp.MustCompile(`^(~>|>=|<=|!=|>|<|=)?\s*([0-9]+(?:\.[0-9]+)*(?:-[A-Za-z0-9]+(?:\.[A-Za-z0-9]+)*)?)$`)
 
+// IsRubySource reports whether s is a git or path source ("git+url",
+// "path+dir") rather than a gem requirement.
+func IsRubySource(s string) bool {
+	low := strings.ToLower(strings.TrimSpace(s))
+	return strings.HasPrefix(low, "git+") || strings.HasPrefix(low, "path+")
+}
+
 func ParseRuby(s string) ([][]vars.Constraint, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return [][]vars.Constraint{}, nil
 	}
+	if IsRubySource(s) {
+		return nil, vars.ErrUnsupportedSource
+	}
 
 	parts := strings.Split(s, ",")
 	var ands []vars.Constraint
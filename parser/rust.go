@@ -35,11 +35,21 @@ func caretRust(token string) []vars.Constraint {
 	return []vars.Constraint{{Op: ">=", Ver: lower}, {Op: "<", Ver: upper}}
 }
 
+// IsRustSource reports whether s is a Cargo.lock style source ("git+url",
+// "path+file://...") rather than a version requirement.
+func IsRustSource(s string) bool {
+	low := strings.ToLower(strings.TrimSpace(s))
+	return strings.HasPrefix(low, "git+") || strings.HasPrefix(low, "path+")
+}
+
 func ParseRust(s string) ([][]vars.Constraint, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return [][]vars.Constraint{}, nil
 	}
+	if IsRustSource(s) {
+		return nil, vars.ErrUnsupportedSource
+	}
 
 	parts := strings.Split(s, ",")
 	var ands []vars.Constraint
@@ -3,10 +3,12 @@ package parser
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/semver"
 	"github.com/rng70/versions/v2/vars"
 )
 
@@ -69,32 +71,44 @@ func pyExpandWildcardEq(v string) []vars.Constraint {
 type parsedConstraint struct {
 	op  string
 	ver canonicalized.Version
-	raw string // original Ver string for "latest" check
+	raw string // original Ver string for the dist-tag check
 }
 
 // FilterMatches returns the subset of versions that satisfy at least one
 // constraint group. Versions and constraints are parsed once upfront.
 func FilterMatches(parsed [][]vars.Constraint, versions []string) []string {
+	return filterMatches(parsed, versions, "")
+}
+
+// FilterMatchesFor is FilterMatches with versions ordered the way style
+// orders them.
+func FilterMatchesFor(style vars.Style, parsed [][]vars.Constraint, versions []string) []string {
+	return filterMatches(parsed, versions, style)
+}
+
+// filterMatches is FilterMatches with versions ordered by the Compare of
+// style, or as canonicalized versions when it has none.
+func filterMatches(parsed [][]vars.Constraint, versions []string, style vars.Style) []string {
+	cmp := Comparator(style)
+
 	// Pre-parse all constraint versions once.
 	groups := make([][]parsedConstraint, len(parsed))
 	for i, ands := range parsed {
-		pg := make([]parsedConstraint, len(ands))
-		for j, c := range ands {
-			pg[j] = parsedConstraint{op: c.Op, raw: c.Ver, ver: canonicalized.NewVersion(c.Ver)}
-		}
-		groups[i] = pg
+		groups[i] = parseGroup(ands, style)
 	}
 
 	// Pre-parse all candidate versions once.
 	pvs := make([]canonicalized.Version, len(versions))
-	for i, v := range versions {
-		pvs[i] = canonicalized.NewVersion(v)
+	if cmp == nil {
+		for i, v := range versions {
+			pvs[i] = parseVersionFor(style, v)
+		}
 	}
 
 	var out []string
 	for i, v := range versions {
 		for _, group := range groups {
-			if satisfiesParsed(&pvs[i], v, group) {
+			if satisfiesParsed(&pvs[i], v, group, cmp) {
 				out = append(out, v)
 				break
 			}
@@ -103,66 +117,219 @@ func FilterMatches(parsed [][]vars.Constraint, versions []string) []string {
 	return out
 }
 
+// parseGroup pre-parses the comparators of an AND group; a style with its
+// own Compare needs only the raw strings.
+func parseGroup(ands []vars.Constraint, style vars.Style) []parsedConstraint {
+	cmp := Comparator(style)
+	pg := make([]parsedConstraint, len(ands))
+	for j, c := range ands {
+		pg[j] = parsedConstraint{op: c.Op, raw: c.Ver}
+		if cmp == nil {
+			pg[j].ver = parseVersionFor(style, c.Ver)
+		}
+	}
+	return pg
+}
+
+// Compare orders two versions of a style whose versions do not order like
+// canonicalized versions. ok is false when the two cannot be ordered, such
+// as a Composer branch and a release; they then only match = and != by
+// name.
+type Compare func(a, b string) (c int, ok bool)
+
+// comparators holds the Compare of each style that needs one.
+var comparators = map[vars.Style]Compare{
+	vars.StyleComposer: canonicalized.CompareComposer,
+	vars.StyleDebian:   canonicalized.CompareDebian,
+	vars.StyleRPM:      canonicalized.CompareRPM,
+	vars.StyleAlpine:   canonicalized.CompareAlpine,
+	vars.StyleConda:    canonicalized.CompareConda,
+}
+
+// Comparator returns the Compare of style, or nil when the versions of
+// style order like canonicalized versions.
+func Comparator(style vars.Style) Compare {
+	return comparators[style]
+}
+
+// prereleases holds, for each style with a Compare, whether a version is a
+// pre-release under that style's rules.
+var prereleases = map[vars.Style]func(string) bool{
+	vars.StyleComposer: func(v string) bool {
+		cv, err := canonicalized.ParseComposerVersion(v)
+		return err != nil || cv.Stability() != canonicalized.StabilityStable
+	},
+	vars.StyleDebian: func(v string) bool {
+		dv, err := canonicalized.ParseDebianVersion(v)
+		return err != nil || strings.Contains(dv.Upstream, "~")
+	},
+	vars.StyleRPM: func(v string) bool {
+		rv, err := canonicalized.ParseRPMVersion(v)
+		return err != nil || strings.Contains(rv.Version, "~")
+	},
+	vars.StyleAlpine: func(v string) bool {
+		av, err := canonicalized.ParseAlpineVersion(v)
+		return err != nil || av.IsPrerelease()
+	},
+	vars.StyleConda: func(v string) bool {
+		cv, err := canonicalized.ParseCondaVersion(v)
+		return err != nil || cv.IsPrerelease()
+	},
+}
+
+// versionParsers holds the parser of each style whose versions order as
+// canonicalized versions but read differently: Maven's timestamped
+// snapshot builds.
+var versionParsers = map[vars.Style]func(string) canonicalized.Version{
+	vars.StyleMaven:  canonicalized.ParseMavenVersion,
+	vars.StyleGradle: canonicalized.ParseMavenVersion,
+}
+
+// parseVersionFor parses v as a canonicalized version of style.
+func parseVersionFor(style vars.Style, v string) canonicalized.Version {
+	if parse, ok := versionParsers[style]; ok {
+		return parse(v)
+	}
+	return canonicalized.NewVersion(v)
+}
+
+// IsPrereleaseFor reports whether version is a pre-release under the rules
+// of style: a Debian or RPM version with ~, an apk _alpha to _rc suffix, a
+// conda dev, a, b or rc, a Composer version below stable, or for the other
+// styles a canonicalized version that is not stable. A version that does
+// not parse counts as a pre-release.
+func IsPrereleaseFor(style vars.Style, version string) bool {
+	if pre, ok := prereleases[style]; ok {
+		return pre(version)
+	}
+	pv := parseVersionFor(style, version)
+	return !pv.IsStable()
+}
+
+// SortVersionsFor returns versions sorted the way style orders them,
+// highest first when descending.
+func SortVersionsFor(style vars.Style, versions []string, descending bool) []string {
+	cmp := Comparator(style)
+	if parse, ok := versionParsers[style]; ok {
+		cmp = func(a, b string) (int, bool) {
+			pa, pb := parse(a), parse(b)
+			return pa.Compare(&pb), true
+		}
+	}
+	if cmp == nil {
+		return semver.SortedVersions(versions, descending)
+	}
+	out := append([]string(nil), versions...)
+	sort.SliceStable(out, func(i, j int) bool {
+		n, _ := cmp(out[i], out[j])
+		if descending {
+			return n > 0
+		}
+		return n < 0
+	})
+	return out
+}
+
 // satisfiesParsed checks whether the pre-parsed version pv (original string v)
-// satisfies every constraint in the AND group.
-func satisfiesParsed(pv *canonicalized.Version, v string, ands []parsedConstraint) bool {
+// satisfies every constraint in the AND group. With a cmp, v is compared as
+// a string and pv is not used.
+func satisfiesParsed(pv *canonicalized.Version, v string, ands []parsedConstraint, cmp Compare) bool {
+	// An unresolved dist-tag only matches a version named after it.
 	for _, c := range ands {
-		if c.raw == "latest" {
-			return v == "latest"
+		if cmp == nil && c.op == "=" && IsDistTag(c.raw) {
+			return v == c.raw
 		}
 	}
 	for _, c := range ands {
-		if c.raw == "" {
+		if !satisfiesOne(pv, v, c, cmp) {
 			return false
 		}
-		cc := c.ver // local copy so we can take address
+	}
+	return true
+}
+
+// satisfiesOne checks a single comparator of an AND group.
+func satisfiesOne(pv *canonicalized.Version, v string, c parsedConstraint, cmp Compare) bool {
+	if c.raw == "" {
+		return false
+	}
+	if cmp != nil {
+		return satisfiesCompare(v, c, cmp)
+	}
+	cc := c.ver // local copy so we can take address
+	switch c.op {
+	case "=":
+		return pv.Equal(&cc)
+	case "!=":
+		return !pv.Equal(&cc)
+	case "<":
+		return pv.LessThan(&cc)
+	case "<=":
+		return pv.LessThanOrEqual(&cc)
+	case ">":
+		return pv.GreaterThan(&cc)
+	case ">=":
+		return pv.GreaterThanOrEqual(&cc)
+	case "<core":
+		// Compare only major.minor.patch (ignore pre-release), used for compatible-release upper bound
+		return compareCore(pv, &cc) < 0
+	default:
+		return false
+	}
+}
+
+// satisfiesCompare checks a comparator with a style's Compare. Versions cmp
+// cannot order only match = and != by name.
+func satisfiesCompare(v string, c parsedConstraint, cmp Compare) bool {
+	n, ok := cmp(v, c.raw)
+	if !ok {
 		switch c.op {
 		case "=":
-			if !pv.Equal(&cc) {
-				return false
-			}
+			return v == c.raw
 		case "!=":
-			if pv.Equal(&cc) {
-				return false
-			}
-		case "<":
-			if !pv.LessThan(&cc) {
-				return false
-			}
-		case "<=":
-			if !pv.LessThanOrEqual(&cc) {
-				return false
-			}
-		case ">":
-			if !pv.GreaterThan(&cc) {
-				return false
-			}
-		case ">=":
-			if !pv.GreaterThanOrEqual(&cc) {
-				return false
-			}
-		case "<core":
-			// Compare only major.minor.patch (ignore pre-release), used for compatible-release upper bound
-			g := func(p *int64) int64 {
-				if p == nil {
-					return 0
-				}
-				return *p
-			}
-			vMaj, cMaj := g(pv.Major), g(cc.Major)
-			vMin, cMin := g(pv.Minor), g(cc.Minor)
-			vPat, cPat := g(pv.Patch), g(cc.Patch)
-			coreNotLess := vMaj > cMaj ||
-				(vMaj == cMaj && vMin > cMin) ||
-				(vMaj == cMaj && vMin == cMin && vPat >= cPat)
-			if coreNotLess {
-				return false
-			}
-		default:
-			return false
+			return v != c.raw
 		}
+		return false
 	}
-	return true
+	switch c.op {
+	case "=":
+		return n == 0
+	case "!=":
+		return n != 0
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	default:
+		return false
+	}
+}
+
+// compareCore compares only major.minor.patch of two versions.
+func compareCore(a, b *canonicalized.Version) int {
+	g := func(p *int64) int64 {
+		if p == nil {
+			return 0
+		}
+		return *p
+	}
+	for _, pair := range [][2]int64{
+		{g(a.Major), g(b.Major)},
+		{g(a.Minor), g(b.Minor)},
+		{g(a.Patch), g(b.Patch)},
+	} {
+		switch {
+		case pair[0] < pair[1]:
+			return -1
+		case pair[0] > pair[1]:
+			return 1
+		}
+	}
+	return 0
 }
 
 func splitVersionNumsLegacy(v string) []int {
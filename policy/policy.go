@@ -0,0 +1,233 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/resolver"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// Release channels a version can belong to. ChannelPrerelease matches every
+// non-stable version regardless of its stage.
+const (
+	ChannelStable     = "stable"
+	ChannelRC         = "rc"
+	ChannelBeta       = "beta"
+	ChannelAlpha      = "alpha"
+	ChannelPreview    = "preview"
+	ChannelPrerelease = "prerelease"
+)
+
+// Policy decides which upgrade an update bot may pick.
+//
+// The zero value allows any bump to a stable version of any age.
+type Policy struct {
+	// MaxBump is the largest allowed gap from the current version
+	// (patch, minor or major). Empty means major.
+	MaxBump vars.Bump `json:"max_bump,omitempty"`
+	// Channels lists the allowed release channels. Empty means stable only.
+	Channels []string `json:"channels,omitempty"`
+	// MinAge is the cooldown a version must have spent published before it
+	// may be picked.
+	MinAge Duration `json:"min_age,omitempty"`
+	// Ignore lists versions that are never picked.
+	Ignore []string `json:"ignore,omitempty"`
+}
+
+// Rejection records why a candidate was not eligible.
+type Rejection struct {
+	Version string `json:"version"`
+	Reason  string `json:"reason"`
+}
+
+// Decision is the outcome of evaluating a policy over a candidate list.
+type Decision struct {
+	Target   string      `json:"target"`
+	Rejected []Rejection `json:"rejected"`
+}
+
+// Parse decodes a JSON policy and validates it.
+func Parse(data []byte) (Policy, error) {
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Policy{}, err
+	}
+	if err := p.Validate(); err != nil {
+		return Policy{}, err
+	}
+	return p, nil
+}
+
+// Validate reports unknown bump levels and channels.
+func (p Policy) Validate() error {
+	if p.MaxBump != "" && bumpRank(p.MaxBump) < 0 {
+		return fmt.Errorf("policy: unknown max_bump %q", p.MaxBump)
+	}
+	for _, c := range p.Channels {
+		switch c {
+		case ChannelStable, ChannelRC, ChannelBeta, ChannelAlpha, ChannelPreview, ChannelPrerelease:
+		default:
+			return fmt.Errorf("policy: unknown channel %q", c)
+		}
+	}
+	return nil
+}
+
+// Evaluate picks the highest candidate above current that the policy allows.
+// Every newer candidate that is not allowed gets a Rejection; candidates that
+// are not newer than current are rejected as such, and yanked ones are never
+// picked. An empty current allows any bump.
+func (p Policy) Evaluate(current string, candidates []vars.Candidate, now time.Time) Decision {
+	d := Decision{Rejected: []Rejection{}}
+
+	cur := canonicalized.NewVersion(current)
+	var best *canonicalized.Version
+	for _, c := range candidates {
+		v := canonicalized.NewVersion(c.Version)
+		if reason := p.reject(current, &cur, &v, c, now); reason != "" {
+			d.Rejected = append(d.Rejected, Rejection{Version: c.Version, Reason: reason})
+			continue
+		}
+		if best == nil || v.GreaterThan(best) {
+			vv := v
+			best = &vv
+		}
+	}
+	if best != nil {
+		d.Target = best.Original
+	}
+	return d
+}
+
+func (p Policy) reject(current string, cur, v *canonicalized.Version, c vars.Candidate, now time.Time) string {
+	if current != "" && !v.GreaterThan(cur) {
+		return fmt.Sprintf("not newer than current %s", current)
+	}
+	if c.Yanked {
+		return "yanked"
+	}
+	for _, ig := range p.Ignore {
+		iv := canonicalized.NewVersion(ig)
+		if v.Equal(&iv) {
+			return fmt.Sprintf("ignored by policy (%s)", ig)
+		}
+	}
+	if ch := Channel(v); !p.allowsChannel(ch) {
+		return fmt.Sprintf("channel %s not allowed", ch)
+	}
+	if current != "" && p.MaxBump != "" {
+		if b := resolver.ClassifyBump(current, c.Version); bumpRank(b) > bumpRank(p.MaxBump) {
+			return fmt.Sprintf("%s bump exceeds allowed %s", b, p.MaxBump)
+		}
+	}
+	if p.MinAge > 0 {
+		if c.Published.IsZero() {
+			return "publish time unknown, cannot apply min_age"
+		}
+		if age := now.Sub(c.Published); age < time.Duration(p.MinAge) {
+			return fmt.Sprintf("published %s ago, younger than %s", Duration(age), p.MinAge)
+		}
+	}
+	return ""
+}
+
+func (p Policy) allowsChannel(ch string) bool {
+	if len(p.Channels) == 0 {
+		return ch == ChannelStable
+	}
+	for _, c := range p.Channels {
+		if c == ch || (c == ChannelPrerelease && ch != ChannelStable) {
+			return true
+		}
+	}
+	return false
+}
+
+// Channel returns the release channel of v.
+func Channel(v *canonicalized.Version) string {
+	switch {
+	case v.IsStable():
+		return ChannelStable
+	case v.IsRC():
+		return ChannelRC
+	case v.IsBeta():
+		return ChannelBeta
+	case v.IsAlpha():
+		return ChannelAlpha
+	case v.IsPreview():
+		return ChannelPreview
+	default:
+		return ChannelPrerelease
+	}
+}
+
+func bumpRank(b vars.Bump) int {
+	switch b {
+	case vars.BumpNone, vars.BumpPrerelease:
+		return 0
+	case vars.BumpPatch:
+		return 1
+	case vars.BumpMinor:
+		return 2
+	case vars.BumpMajor:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// Duration is a time.Duration that reads and writes JSON as a string such as
+// "7d", "36h" or "90m". Plain numbers are taken as seconds.
+type Duration time.Duration
+
+// ParseDuration extends time.ParseDuration with a "d" (24h) unit.
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("policy: invalid duration %q", s)
+		}
+		return Duration(n * float64(24*time.Hour)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("policy: invalid duration %q", s)
+	}
+	return Duration(d), nil
+}
+
+func (d Duration) String() string {
+	td := time.Duration(d)
+	if td != 0 && td%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", td/(24*time.Hour))
+	}
+	return td.Truncate(time.Second).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var secs float64
+		if err := json.Unmarshal(data, &secs); err != nil {
+			return fmt.Errorf("policy: invalid duration %s", data)
+		}
+		*d = Duration(secs * float64(time.Second))
+		return nil
+	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
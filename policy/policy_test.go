@@ -0,0 +1,189 @@
+package policy
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/vars"
+)
+
+var now = time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+func daysAgo(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+var candidates = []vars.Candidate{
+	{Version: "1.2.3", Published: daysAgo(100)},
+	{Version: "1.2.4", Published: daysAgo(30)},
+	{Version: "1.2.5", Published: daysAgo(2)},
+	{Version: "1.3.0", Published: daysAgo(20)},
+	{Version: "1.4.0-rc.1", Published: daysAgo(15)},
+	{Version: "2.0.0", Published: daysAgo(10)},
+}
+
+func reasonFor(d Decision, version string) string {
+	for _, r := range d.Rejected {
+		if r.Version == version {
+			return r.Reason
+		}
+	}
+	return ""
+}
+
+// ─── Evaluate ─────────────────────────────────────────────────────────────────
+
+func TestEvaluate_ZeroPolicyPicksHighestStable(t *testing.T) {
+	d := Policy{}.Evaluate("1.2.3", candidates, now)
+	if d.Target != "2.0.0" {
+		t.Errorf("target: got %q, want %q", d.Target, "2.0.0")
+	}
+	if !strings.Contains(reasonFor(d, "1.4.0-rc.1"), "channel rc") {
+		t.Errorf("rc reason: got %q", reasonFor(d, "1.4.0-rc.1"))
+	}
+	if !strings.Contains(reasonFor(d, "1.2.3"), "not newer") {
+		t.Errorf("current reason: got %q", reasonFor(d, "1.2.3"))
+	}
+}
+
+func TestEvaluate_PatchOnly(t *testing.T) {
+	d := Policy{MaxBump: vars.BumpPatch}.Evaluate("1.2.3", candidates, now)
+	if d.Target != "1.2.5" {
+		t.Errorf("target: got %q, want %q", d.Target, "1.2.5")
+	}
+	if got := reasonFor(d, "1.3.0"); got != "minor bump exceeds allowed patch" {
+		t.Errorf("1.3.0 reason: got %q", got)
+	}
+	if got := reasonFor(d, "2.0.0"); got != "major bump exceeds allowed patch" {
+		t.Errorf("2.0.0 reason: got %q", got)
+	}
+}
+
+func TestEvaluate_Cooldown(t *testing.T) {
+	d := Policy{MaxBump: vars.BumpPatch, MinAge: Duration(7 * 24 * time.Hour)}.Evaluate("1.2.3", candidates, now)
+	if d.Target != "1.2.4" {
+		t.Errorf("target: got %q, want %q", d.Target, "1.2.4")
+	}
+	if got := reasonFor(d, "1.2.5"); got != "published 2d ago, younger than 7d" {
+		t.Errorf("1.2.5 reason: got %q", got)
+	}
+}
+
+func TestEvaluate_SkipsYanked(t *testing.T) {
+	cands := []vars.Candidate{{Version: "1.2.4"}, {Version: "1.2.5", Yanked: true}}
+	d := Policy{}.Evaluate("1.2.3", cands, now)
+	if d.Target != "1.2.4" {
+		t.Errorf("target: got %q, want %q", d.Target, "1.2.4")
+	}
+	if got := reasonFor(d, "1.2.5"); got != "yanked" {
+		t.Errorf("1.2.5 reason: got %q", got)
+	}
+}
+
+func TestEvaluate_CooldownUnknownPublishTime(t *testing.T) {
+	d := Policy{MinAge: Duration(time.Hour)}.Evaluate("", []vars.Candidate{{Version: "1.0.0"}}, now)
+	if d.Target != "" {
+		t.Errorf("target: got %q, want empty", d.Target)
+	}
+	if len(d.Rejected) != 1 {
+		t.Errorf("expected one rejection, got %v", d.Rejected)
+	}
+}
+
+func TestEvaluate_Ignore(t *testing.T) {
+	d := Policy{Ignore: []string{"v2.0.0"}}.Evaluate("1.2.3", candidates, now)
+	if d.Target != "1.3.0" {
+		t.Errorf("target: got %q, want %q", d.Target, "1.3.0")
+	}
+	if got := reasonFor(d, "2.0.0"); !strings.Contains(got, "ignored") {
+		t.Errorf("2.0.0 reason: got %q", got)
+	}
+}
+
+func TestEvaluate_PrereleaseChannel(t *testing.T) {
+	p := Policy{MaxBump: vars.BumpMinor, Channels: []string{ChannelStable, ChannelRC}}
+	d := p.Evaluate("1.2.3", candidates, now)
+	if d.Target != "1.4.0-rc.1" {
+		t.Errorf("target: got %q, want %q", d.Target, "1.4.0-rc.1")
+	}
+}
+
+func TestEvaluate_NoCandidates(t *testing.T) {
+	d := Policy{}.Evaluate("1.0.0", nil, now)
+	if d.Target != "" || len(d.Rejected) != 0 {
+		t.Errorf("expected empty decision, got %+v", d)
+	}
+}
+
+// ─── Channel ──────────────────────────────────────────────────────────────────
+
+func TestChannel(t *testing.T) {
+	cases := map[string]string{
+		"1.0.0":           ChannelStable,
+		"1.0.0-rc.1":      ChannelRC,
+		"1.0.0-beta1":     ChannelBeta,
+		"1.0.0-alpha":     ChannelAlpha,
+		"1.0.0-preview.2": ChannelPreview,
+		"1.0.0-nightly":   ChannelPrerelease,
+	}
+	for in, want := range cases {
+		pv := canonicalized.NewVersion(in)
+		if got := Channel(&pv); got != want {
+			t.Errorf("Channel(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
+
+// ─── JSON ─────────────────────────────────────────────────────────────────────
+
+func TestParse_RoundTrip(t *testing.T) {
+	in := `{"max_bump":"patch","channels":["stable"],"min_age":"7d","ignore":["1.2.5"]}`
+	p, err := Parse([]byte(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.MaxBump != vars.BumpPatch || time.Duration(p.MinAge) != 7*24*time.Hour {
+		t.Errorf("decoded: got %+v", p)
+	}
+	out, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(out) != in {
+		t.Errorf("round trip:\n  got  %s\n  want %s", out, in)
+	}
+}
+
+func TestParse_DurationForms(t *testing.T) {
+	cases := map[string]time.Duration{
+		`"36h"`:  36 * time.Hour,
+		`"90m"`:  90 * time.Minute,
+		`"1.5d"`: 36 * time.Hour,
+		`3600`:   time.Hour,
+	}
+	for in, want := range cases {
+		p, err := Parse([]byte(`{"min_age":` + in + `}`))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", in, err)
+			continue
+		}
+		if time.Duration(p.MinAge) != want {
+			t.Errorf("%s: got %v, want %v", in, time.Duration(p.MinAge), want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, in := range []string{
+		`{"max_bump":"huge"}`,
+		`{"channels":["nightly"]}`,
+		`{"min_age":"soon"}`,
+		`{"min_age":true}`,
+		`not json`,
+	} {
+		if _, err := Parse([]byte(in)); err == nil {
+			t.Errorf("%s: expected error", in)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+var (
+	// ErrNotFound is returned when the registry does not know the package.
+	ErrNotFound = errors.New("package not found")
+	// ErrStatus is returned for any other unsuccessful HTTP response.
+	ErrStatus = errors.New("unexpected registry response")
+	// ErrUnsupportedStyle is returned for styles without a registry.
+	ErrUnsupportedStyle = errors.New("no registry for style")
+)
+
+// DefaultBaseURLs are the public registries, keyed by the style of their
+// constraints.
+var DefaultBaseURLs = map[vars.Style]string{
+	vars.StyleNPM:   "https://registry.npmjs.org",
+	vars.StylePy:    "https://pypi.org",
+	vars.StyleRust:  "https://index.crates.io",
+	vars.StyleRuby:  "https://rubygems.org",
+	vars.StyleNuGet: "https://api.nuget.org/v3/registration5-gz-semver2",
+	vars.StyleMaven: "https://repo1.maven.org/maven2",
+	vars.StyleGo:    "https://proxy.golang.org",
+}
+
+// Client fetches version lists from package registries.
+//
+// The zero value talks to DefaultBaseURLs through http.DefaultClient,
+// without a cache.
+type Client struct {
+	// HTTP is the client requests go through; nil means http.DefaultClient.
+	HTTP *http.Client
+	// BaseURLs overrides DefaultBaseURLs per style, e.g. for a mirror or a
+	// GOPROXY.
+	BaseURLs map[vars.Style]string
+	// CacheDir, when set, keeps responses on disk with their ETag and
+	// revalidates them with If-None-Match instead of downloading again.
+	CacheDir string
+	// UserAgent is sent with every request when set.
+	UserAgent string
+}
+
+// Versions returns the versions of name that are not yanked, in ascending
+// order, ready for resolver.AnalyzeConstraint.
+func (c *Client) Versions(ctx context.Context, style vars.Style, name string) ([]string, error) {
+	cands, err := c.Candidates(ctx, style, name)
+	if err != nil {
+		return nil, err
+	}
+	return Versions(WithoutYanked(cands)), nil
+}
+
+// Candidates fetches every published version of name from the registry of
+// style. Names are npm package names, PyPI projects, crates, gems, NuGet
+// ids, Maven "group:artifact" coordinates and Go module paths. Gradle
+// resolves from the Maven repository.
+func (c *Client) Candidates(ctx context.Context, style vars.Style, name string) ([]vars.Candidate, error) {
+	if style == vars.StyleGradle {
+		style = vars.StyleMaven
+	}
+	base := c.baseURL(style)
+	if base == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedStyle, style)
+	}
+
+	switch style {
+	case vars.StyleNPM:
+		// Scoped names escape their slash: @scope%2Fpkg.
+		return fetch(ctx, c, base+"/"+url.PathEscape(name), ReadNpmPackument)
+	case vars.StylePy:
+		return fetch(ctx, c, base+"/pypi/"+url.PathEscape(name)+"/json", ReadPyPIJSON)
+	case vars.StyleRust:
+		return fetch(ctx, c, base+"/"+cratesIndexPath(name), ReadCratesIndex)
+	case vars.StyleRuby:
+		return fetch(ctx, c, base+"/api/v1/versions/"+url.PathEscape(name)+".json", ReadRubyGemsAPI)
+	case vars.StyleNuGet:
+		return c.nuget(ctx, base+"/"+url.PathEscape(strings.ToLower(name))+"/index.json")
+	case vars.StyleMaven:
+		dir, err := mavenDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return fetch(ctx, c, base+"/"+dir+"/maven-metadata.xml", ReadMavenMetadata)
+	case vars.StyleGo:
+		return fetch(ctx, c, base+"/"+escapeModulePath(name)+"/@v/list", ReadGoProxyList)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedStyle, style)
+}
+
+// MavenSnapshot fetches the snapshot-level metadata of a -SNAPSHOT version
+// of the Maven artifact name ("group:artifact"); its Latest field is the
+// build the snapshot currently resolves to.
+func (c *Client) MavenSnapshot(ctx context.Context, name, version string) (MavenSnapshot, error) {
+	dir, err := mavenDir(name)
+	if err != nil {
+		return MavenSnapshot{}, err
+	}
+	body, err := c.get(ctx, c.baseURL(vars.StyleMaven)+"/"+dir+"/"+url.PathEscape(version)+"/maven-metadata.xml")
+	if err != nil {
+		return MavenSnapshot{}, err
+	}
+	return ReadMavenSnapshotMetadata(bytes.NewReader(body))
+}
+
+// mavenDir is the repository directory of a "group:artifact" coordinate.
+func mavenDir(name string) (string, error) {
+	group, artifact, ok := strings.Cut(name, ":")
+	if !ok || group == "" || artifact == "" {
+		return "", fmt.Errorf("maven coordinate %q: want group:artifact", name)
+	}
+	return strings.ReplaceAll(group, ".", "/") + "/" + artifact, nil
+}
+
+func (c *Client) baseURL(style vars.Style) string {
+	if u, ok := c.BaseURLs[style]; ok {
+		return strings.TrimSuffix(u, "/")
+	}
+	return DefaultBaseURLs[style]
+}
+
+// nuget reads a registration index and any pages it only links to.
+func (c *Client) nuget(ctx context.Context, index string) ([]vars.Candidate, error) {
+	body, err := c.get(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+	cands, pages, err := readNuGetRegistration(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for _, page := range pages {
+		body, err := c.get(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		more, _, err := readNuGetRegistration(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		cands = append(cands, more...)
+	}
+	return sortCandidates(cands), nil
+}
+
+func fetch(ctx context.Context, c *Client, u string, read func(io.Reader) ([]vars.Candidate, error)) ([]vars.Candidate, error) {
+	body, err := c.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return read(bytes.NewReader(body))
+}
+
+// cratesIndexPath is where the sparse index keeps a crate: 1/a, 2/ab,
+// 3/a/abc and ab/cd/abcd for longer names.
+func cratesIndexPath(name string) string {
+	name = strings.ToLower(name)
+	switch len(name) {
+	case 1, 2:
+		return fmt.Sprintf("%d/%s", len(name), name)
+	case 3:
+		return "3/" + name[:1] + "/" + name
+	default:
+		return name[:2] + "/" + name[2:4] + "/" + name
+	}
+}
+
+// escapeModulePath applies the module proxy's case encoding: each
+// upper-case letter becomes "!" and its lower-case form.
+func escapeModulePath(path string) string {
+	var sb strings.Builder
+	for _, r := range path {
+		if 'A' <= r && r <= 'Z' {
+			sb.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+/* ------------------------- */
+/*      HTTP and cache       */
+/* ------------------------- */
+
+// cacheEntry is a response kept in CacheDir.
+type cacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// get returns the body of u, revalidating a cached copy when there is one.
+func (c *Client) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	cached := c.load(u)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return cached.Body, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, u)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: %s: %s", ErrStatus, u, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.store(cacheEntry{
+		URL:          u,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Body:         body,
+	})
+	return body, nil
+}
+
+func (c *Client) cachePath(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return filepath.Join(c.CacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the cached response for u, or nil.
+func (c *Client) load(u string) *cacheEntry {
+	if c.CacheDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.cachePath(u))
+	if err != nil {
+		return nil
+	}
+	var e cacheEntry
+	if json.Unmarshal(data, &e) != nil || e.URL != u {
+		return nil
+	}
+	return &e
+}
+
+// store caches a response that can be revalidated. The cache is a
+// convenience: failing to write it does not fail the request.
+func (c *Client) store(e cacheEntry) {
+	if c.CacheDir == "" || (e.ETag == "" && e.LastModified == "") {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(c.CacheDir, 0o755) != nil {
+		return
+	}
+	// Write then rename, so a concurrent reader never sees half a file.
+	tmp, err := os.CreateTemp(c.CacheDir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil || os.Rename(tmp.Name(), c.cachePath(e.URL)) != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,234 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rng70/versions/v2/resolver"
+	"github.com/rng70/versions/v2/vars"
+)
+
+const mavenMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>org.example</groupId>
+  <artifactId>demo</artifactId>
+  <versioning>
+    <latest>2.0-SNAPSHOT</latest>
+    <release>1.10</release>
+    <versions>
+      <version>1.9</version>
+      <version>1.10</version>
+      <version>2.0-SNAPSHOT</version>
+    </versions>
+    <lastUpdated>20240101120000</lastUpdated>
+  </versioning>
+</metadata>`
+
+// registryServer stands in for every registry the client knows, under one
+// base URL.
+func registryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/@scope%2Fdemo":
+			fmt.Fprint(w, packument)
+		case "/pypi/demo/json":
+			fmt.Fprint(w, pypiJSON)
+		case "/de/mo/demo":
+			fmt.Fprint(w, cratesIndex)
+		case "/api/v1/versions/rack.json":
+			fmt.Fprint(w, `[{"number":"3.0.0","platform":"ruby","created_at":"2022-09-06T00:00:00.000Z"},
+				{"number":"2.2.8","platform":"java","created_at":"2023-07-31T00:00:00.000Z"},
+				{"number":"2.2.8","platform":"ruby","created_at":"2023-07-30T00:00:00.000Z"}]`)
+		case "/demo/index.json":
+			fmt.Fprintf(w, `{"count":2,"items":[
+				{"@id":"%[1]s/demo/page1.json","count":1,"items":[{"catalogEntry":{"version":"1.0.0","published":"2020-01-01T00:00:00Z"}}]},
+				{"@id":"%[1]s/demo/page2.json","count":1,"lower":"2.0.0","upper":"2.0.0"}]}`, srv.URL)
+		case "/demo/page2.json":
+			fmt.Fprint(w, `{"count":1,"items":[{"catalogEntry":{"version":"2.0.0","published":"2021-01-01T00:00:00Z","listed":false}}]}`)
+		case "/org/example/demo/maven-metadata.xml":
+			fmt.Fprint(w, mavenMetadata)
+		case "/org/example/demo/1.0-SNAPSHOT/maven-metadata.xml":
+			fmt.Fprint(w, mavenSnapshotMetadata)
+		case "/github.com/!azure/demo/@v/list":
+			fmt.Fprint(w, "v1.1.0\nv1.0.0\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testClient(srv *httptest.Server) *Client {
+	c := &Client{HTTP: srv.Client(), BaseURLs: map[vars.Style]string{}}
+	for style := range DefaultBaseURLs {
+		c.BaseURLs[style] = srv.URL + "/"
+	}
+	return c
+}
+
+// ─── Client ───────────────────────────────────────────────────────────────────
+
+func TestClient_Candidates(t *testing.T) {
+	c := testClient(registryServer(t))
+	cases := []struct {
+		style vars.Style
+		name  string
+		want  []string
+	}{
+		{vars.StyleNPM, "@scope/demo", []string{"1.0.0 @2020-01-01 deprecated(use 1.1.0)", "1.1.0 @2021-06-30 [latest,stable]", "2.0.0-rc.1 @2024-01-01 [next]"}},
+		{vars.StylePy, "demo", []string{"0.1", "1.0 @2020-01-31", "1.1 @2021-05-05", "1.2 @2022-01-01 yanked"}},
+		{vars.StyleRust, "Demo", []string{"0.9.0", "1.0.0", "1.1.0 yanked", "1.2.0-beta.1", "1.2.0 @2025-03-01"}},
+		{vars.StyleRuby, "rack", []string{"2.2.8 @2023-07-30", "3.0.0 @2022-09-06"}},
+		{vars.StyleNuGet, "Demo", []string{"1.0.0 @2020-01-01", "2.0.0 @2021-01-01 yanked"}},
+		{vars.StyleMaven, "org.example:demo", []string{"1.9", "1.10 [RELEASE]", "2.0-SNAPSHOT [LATEST]"}},
+		{vars.StyleGradle, "org.example:demo", []string{"1.9", "1.10 [RELEASE]", "2.0-SNAPSHOT [LATEST]"}},
+		{vars.StyleGo, "github.com/Azure/demo", []string{"v1.0.0", "v1.1.0"}},
+	}
+	for _, tc := range cases {
+		cands, err := c.Candidates(context.Background(), tc.style, tc.name)
+		if err != nil {
+			t.Errorf("%s %s: %v", tc.style, tc.name, err)
+			continue
+		}
+		if got := summarize(cands); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s %s:\n got  %q\n want %q", tc.style, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestClient_VersionsWithResolver(t *testing.T) {
+	c := testClient(registryServer(t))
+	a, err := resolver.AnalyzePackage(context.Background(), c, vars.StyleRust, "demo", ">=1.0.0, <1.1.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The yanked 1.1.0 is not listed.
+	if want := []string{"1.0.0"}; !reflect.DeepEqual(a.Matches, want) {
+		t.Errorf("matches: got %q, want %q", a.Matches, want)
+	}
+}
+
+func TestClient_DistTagsWithResolver(t *testing.T) {
+	c := testClient(registryServer(t))
+	a, err := resolver.AnalyzePackage(context.Background(), c, vars.StyleNPM, "@scope/demo", "next")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2.0.0-rc.1"}; !reflect.DeepEqual(a.Matches, want) {
+		t.Errorf("matches: got %q, want %q", a.Matches, want)
+	}
+}
+
+func TestClient_MavenSnapshot(t *testing.T) {
+	c := testClient(registryServer(t))
+	s, err := c.MavenSnapshot(context.Background(), "org.example:demo", "1.0-SNAPSHOT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Latest != "1.0-20230815.123045-7" {
+		t.Errorf("latest: got %q", s.Latest)
+	}
+	if _, err := c.MavenSnapshot(context.Background(), "demo", "1.0-SNAPSHOT"); err == nil {
+		t.Error("expected an error for a coordinate without a group")
+	}
+}
+
+func TestClient_NotFound(t *testing.T) {
+	c := testClient(registryServer(t))
+	_, err := c.Versions(context.Background(), vars.StyleNPM, "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("error: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestClient_Status(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c := testClient(srv)
+	_, err := c.Versions(context.Background(), vars.StyleNPM, "demo")
+	if !errors.Is(err, ErrStatus) {
+		t.Errorf("error: got %v, want ErrStatus", err)
+	}
+}
+
+func TestClient_UnsupportedStyle(t *testing.T) {
+	_, err := (&Client{}).Versions(context.Background(), vars.Style("cobol"), "demo")
+	if !errors.Is(err, ErrUnsupportedStyle) {
+		t.Errorf("error: got %v, want ErrUnsupportedStyle", err)
+	}
+}
+
+func TestClient_ContextCanceled(t *testing.T) {
+	c := testClient(registryServer(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.Versions(ctx, vars.StyleNPM, "@scope/demo")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error: got %v, want context.Canceled", err)
+	}
+}
+
+func TestClient_ETagCache(t *testing.T) {
+	var full, revalidated atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		fmt.Fprint(w, "v1.0.0\nv1.1.0\n")
+	}))
+	defer srv.Close()
+
+	c := testClient(srv)
+	c.CacheDir = t.TempDir()
+	for i := 0; i < 3; i++ {
+		versions, err := c.Versions(context.Background(), vars.StyleGo, "example.com/demo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"v1.0.0", "v1.1.0"}; !reflect.DeepEqual(versions, want) {
+			t.Errorf("round %d: got %q, want %q", i, versions, want)
+		}
+	}
+	if full.Load() != 1 || revalidated.Load() != 2 {
+		t.Errorf("got %d full and %d revalidated responses, want 1 and 2", full.Load(), revalidated.Load())
+	}
+
+	// Without a cache every request downloads again.
+	c.CacheDir = ""
+	if _, err := c.Versions(context.Background(), vars.StyleGo, "example.com/demo"); err != nil {
+		t.Fatal(err)
+	}
+	if full.Load() != 2 {
+		t.Errorf("got %d full responses, want 2", full.Load())
+	}
+}
+
+func TestCratesIndexPath(t *testing.T) {
+	cases := map[string]string{"a": "1/a", "ab": "2/ab", "abc": "3/a/abc", "Serde": "se/rd/serde"}
+	for name, want := range cases {
+		if got := cratesIndexPath(name); got != want {
+			t.Errorf("cratesIndexPath(%q): got %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	if got := escapeModulePath("github.com/BurntSushi/toml"); got != "github.com/!burnt!sushi/toml" {
+		t.Errorf("got %q", got)
+	}
+}
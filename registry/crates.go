@@ -0,0 +1,42 @@
+package registry
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ReadCratesIndex reads a crate's file from the crates.io index: one JSON
+// object per line with vers, yanked and, in newer entries, pubtime.
+func ReadCratesIndex(r io.Reader) ([]vars.Candidate, error) {
+	var out []vars.Candidate
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var entry struct {
+			Vers    string `json:"vers"`
+			Yanked  bool   `json:"yanked"`
+			Pubtime string `json:"pubtime"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("crates index line %d: %w", n, err)
+		}
+		out = append(out, vars.Candidate{
+			Version:   entry.Vers,
+			Published: parseTime(entry.Pubtime),
+			Yanked:    entry.Yanked,
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("crates index: %w", err)
+	}
+	return sortCandidates(out), nil
+}
@@ -0,0 +1,31 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+)
+
+const cratesIndex = `{"name":"demo","vers":"1.0.0","deps":[],"cksum":"aa","features":{},"yanked":false}
+{"name":"demo","vers":"0.9.0","deps":[],"cksum":"bb","features":{},"yanked":false}
+{"name":"demo","vers":"1.1.0","deps":[],"cksum":"cc","features":{},"yanked":true}
+{"name":"demo","vers":"1.2.0-beta.1","deps":[],"cksum":"dd","features":{},"yanked":false}
+
+{"name":"demo","vers":"1.2.0","deps":[],"cksum":"ee","features":{},"yanked":false,"pubtime":"2025-03-01T10:00:00Z"}
+`
+
+// ─── ReadCratesIndex ──────────────────────────────────────────────────────────
+
+func TestReadCratesIndex(t *testing.T) {
+	cands, err := ReadCratesIndex(strings.NewReader(cratesIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, cands, "0.9.0", "1.0.0", "1.1.0 yanked", "1.2.0-beta.1", "1.2.0 @2025-03-01")
+}
+
+func TestReadCratesIndex_BadLine(t *testing.T) {
+	_, err := ReadCratesIndex(strings.NewReader(`{"vers":"1.0.0"}` + "\nnot json\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("error: got %v, want line 2", err)
+	}
+}
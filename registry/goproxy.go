@@ -0,0 +1,50 @@
+package registry
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ReadGoProxyList reads the output of a module proxy's @v/list endpoint:
+// one version per line. The list carries no publish times; ReadGoProxyInfo
+// reads them from @v/<version>.info.
+func ReadGoProxyList(r io.Reader) ([]vars.Candidate, error) {
+	var out []vars.Candidate
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		// Some proxies append the time after the version.
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+		c := vars.Candidate{Version: fields[0]}
+		if len(fields) > 1 {
+			c.Published = parseTime(fields[1])
+		}
+		out = append(out, c)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("go proxy list: %w", err)
+	}
+	return sortCandidates(out), nil
+}
+
+// ReadGoProxyInfo reads a module proxy's @v/<version>.info or @latest
+// response.
+func ReadGoProxyInfo(r io.Reader) (vars.Candidate, error) {
+	var info struct {
+		Version string `json:"Version"`
+		Time    string `json:"Time"`
+	}
+	if err := json.NewDecoder(r).Decode(&info); err != nil {
+		return vars.Candidate{}, fmt.Errorf("go proxy info: %w", err)
+	}
+	return vars.Candidate{Version: info.Version, Published: parseTime(info.Time)}, nil
+}
@@ -0,0 +1,29 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ─── ReadGoProxyList ──────────────────────────────────────────────────────────
+
+func TestReadGoProxyList(t *testing.T) {
+	list := "v1.10.0\nv1.2.0\nv0.1.0\n\nv2.0.0-rc.1 2024-02-02T00:00:00Z\nv1.2.0\n"
+	cands, err := ReadGoProxyList(strings.NewReader(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, cands, "v0.1.0", "v1.2.0", "v1.10.0", "v2.0.0-rc.1 @2024-02-02")
+}
+
+// ─── ReadGoProxyInfo ──────────────────────────────────────────────────────────
+
+func TestReadGoProxyInfo(t *testing.T) {
+	c, err := ReadGoProxyInfo(strings.NewReader(`{"Version":"v1.2.0","Time":"2023-08-09T10:11:12Z"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, []vars.Candidate{c}, "v1.2.0 @2023-08-09")
+}
@@ -0,0 +1,123 @@
+package registry
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ReadMavenMetadata reads an artifact-level maven-metadata.xml. The versions
+// its latest and release elements name are tagged "LATEST" and "RELEASE",
+// so that those meta-versions resolve through resolver.AnalyzeCandidates.
+// The file carries no per-version publish times.
+func ReadMavenMetadata(r io.Reader) ([]vars.Candidate, error) {
+	var doc struct {
+		Versioning struct {
+			Latest   string   `xml:"latest"`
+			Release  string   `xml:"release"`
+			Versions []string `xml:"versions>version"`
+		} `xml:"versioning"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("maven-metadata.xml: %w", err)
+	}
+
+	v := doc.Versioning
+	out := make([]vars.Candidate, 0, len(v.Versions))
+	seen := map[string]bool{}
+	for _, version := range v.Versions {
+		version = strings.TrimSpace(version)
+		if version == "" || seen[version] {
+			continue
+		}
+		seen[version] = true
+		c := vars.Candidate{Version: version}
+		if version == strings.TrimSpace(v.Latest) {
+			c.Tags = append(c.Tags, "LATEST")
+		}
+		if version == strings.TrimSpace(v.Release) {
+			c.Tags = append(c.Tags, "RELEASE")
+		}
+		out = append(out, c)
+	}
+	return sortCandidatesWith(out, canonicalized.ParseMavenVersion), nil
+}
+
+// MavenSnapshot is what a snapshot-level maven-metadata.xml, the one next
+// to the builds of a -SNAPSHOT version, tells about them.
+type MavenSnapshot struct {
+	// Version is the snapshot version, e.g. "1.0-SNAPSHOT".
+	Version string `json:"version"`
+	// Latest is the newest timestamped build, e.g. "1.0-20230815.123045-7";
+	// Version itself for a local repository, which keeps no builds.
+	Latest string `json:"latest"`
+	// Builds lists the timestamped builds the file names, oldest first,
+	// with the time each was deployed.
+	Builds []vars.Candidate `json:"builds"`
+}
+
+// ReadMavenSnapshotMetadata reads a snapshot-level maven-metadata.xml.
+func ReadMavenSnapshotMetadata(r io.Reader) (MavenSnapshot, error) {
+	var doc struct {
+		Version    string `xml:"version"`
+		Versioning struct {
+			Snapshot struct {
+				Timestamp   string `xml:"timestamp"`
+				BuildNumber string `xml:"buildNumber"`
+				LocalCopy   bool   `xml:"localCopy"`
+			} `xml:"snapshot"`
+			LastUpdated      string `xml:"lastUpdated"`
+			SnapshotVersions []struct {
+				Value   string `xml:"value"`
+				Updated string `xml:"updated"`
+			} `xml:"snapshotVersions>snapshotVersion"`
+		} `xml:"versioning"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return MavenSnapshot{}, fmt.Errorf("maven-metadata.xml: %w", err)
+	}
+
+	v := doc.Versioning
+	s := MavenSnapshot{Version: strings.TrimSpace(doc.Version), Latest: strings.TrimSpace(doc.Version)}
+	base, isSnapshot := strings.CutSuffix(s.Version, "-SNAPSHOT")
+	if isSnapshot && v.Snapshot.Timestamp != "" && v.Snapshot.BuildNumber != "" && !v.Snapshot.LocalCopy {
+		s.Latest = base + "-" + strings.TrimSpace(v.Snapshot.Timestamp) + "-" + strings.TrimSpace(v.Snapshot.BuildNumber)
+	}
+
+	// Each classifier and extension of a build is listed; keep the build.
+	published := map[string]time.Time{}
+	var builds []string
+	for _, sv := range v.SnapshotVersions {
+		value := strings.TrimSpace(sv.Value)
+		if value == "" || value == s.Version {
+			continue
+		}
+		if _, ok := published[value]; !ok {
+			builds = append(builds, value)
+		}
+		published[value] = earlier(published[value], mavenTime(sv.Updated))
+	}
+	if _, ok := published[s.Latest]; !ok && s.Latest != s.Version {
+		builds = append(builds, s.Latest)
+		published[s.Latest] = mavenTime(v.LastUpdated)
+	}
+	for _, b := range builds {
+		s.Builds = append(s.Builds, vars.Candidate{Version: b, Published: published[b]})
+	}
+	s.Builds = sortCandidatesWith(s.Builds, canonicalized.ParseMavenVersion)
+	return s, nil
+}
+
+// mavenTime reads the yyyyMMddHHmmss timestamps of maven-metadata.xml.
+func mavenTime(s string) time.Time {
+	t, err := time.ParseInLocation("20060102150405", strings.TrimSpace(s), time.UTC)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/resolver"
+	"github.com/rng70/versions/v2/vars"
+)
+
+const mavenSnapshotMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>org.example</groupId>
+  <artifactId>demo</artifactId>
+  <version>1.0-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20230815.123045</timestamp>
+      <buildNumber>7</buildNumber>
+    </snapshot>
+    <lastUpdated>20230815123046</lastUpdated>
+    <snapshotVersions>
+      <snapshotVersion>
+        <extension>pom</extension>
+        <value>1.0-20230815.123045-7</value>
+        <updated>20230815123045</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <classifier>sources</classifier>
+        <extension>jar</extension>
+        <value>1.0-20230815.123045-7</value>
+        <updated>20230815123046</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <extension>jar</extension>
+        <value>1.0-20230801.090000-12</value>
+        <updated>20230801090000</updated>
+      </snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>`
+
+// ─── ReadMavenMetadata ────────────────────────────────────────────────────────
+
+func TestReadMavenMetadata_MetaVersions(t *testing.T) {
+	cands, err := ReadMavenMetadata(strings.NewReader(mavenMetadata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, cands, "1.9", "1.10 [RELEASE]", "2.0-SNAPSHOT [LATEST]")
+
+	for constraint, want := range map[string]string{"RELEASE": "1.10", "LATEST": "2.0-SNAPSHOT"} {
+		a := resolver.AnalyzeCandidates(vars.StyleMaven, constraint, cands)
+		if !reflect.DeepEqual(a.Matches, []string{want}) {
+			t.Errorf("%s: got %q, want [%q]", constraint, a.Matches, want)
+		}
+	}
+}
+
+// ─── ReadMavenSnapshotMetadata ────────────────────────────────────────────────
+
+func TestReadMavenSnapshotMetadata(t *testing.T) {
+	s, err := ReadMavenSnapshotMetadata(strings.NewReader(mavenSnapshotMetadata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != "1.0-SNAPSHOT" || s.Latest != "1.0-20230815.123045-7" {
+		t.Errorf("got version %q latest %q", s.Version, s.Latest)
+	}
+	assertCandidates(t, s.Builds, "1.0-20230801.090000-12 @2023-08-01", "1.0-20230815.123045-7 @2023-08-15")
+
+	// Builds order by timestamp before build number.
+	sorted := parser.SortVersionsFor(vars.StyleMaven, []string{"1.0", s.Latest, "1.0-20230801.090000-12", "1.0-SNAPSHOT"}, false)
+	want := []string{"1.0-20230801.090000-12", "1.0-20230815.123045-7", "1.0-SNAPSHOT", "1.0"}
+	if !reflect.DeepEqual(sorted, want) {
+		t.Errorf("sorted: got %q, want %q", sorted, want)
+	}
+}
+
+func TestReadMavenSnapshotMetadata_LocalCopy(t *testing.T) {
+	local := `<metadata><version>2.0-SNAPSHOT</version><versioning><snapshot><localCopy>true</localCopy></snapshot></versioning></metadata>`
+	s, err := ReadMavenSnapshotMetadata(strings.NewReader(local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Latest != "2.0-SNAPSHOT" || len(s.Builds) != 0 {
+		t.Errorf("got latest %q builds %v", s.Latest, s.Builds)
+	}
+}
@@ -0,0 +1,54 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ReadNpmPackument reads an npm packument, full or abbreviated. Publish
+// times come from its time field, deprecation messages from each version's
+// deprecated field and tags from dist-tags. Unpublished packages have no
+// versions.
+func ReadNpmPackument(r io.Reader) ([]vars.Candidate, error) {
+	var doc struct {
+		DistTags map[string]string `json:"dist-tags"`
+		Versions map[string]struct {
+			Deprecated json.RawMessage `json:"deprecated"`
+		} `json:"versions"`
+		Time map[string]json.RawMessage `json:"time"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("npm packument: %w", err)
+	}
+
+	tags := map[string][]string{}
+	for tag, v := range doc.DistTags {
+		tags[v] = append(tags[v], tag)
+	}
+
+	out := make([]vars.Candidate, 0, len(doc.Versions))
+	for v, meta := range doc.Versions {
+		c := vars.Candidate{Version: v}
+		// "time" also holds "created" and "modified", and an object once
+		// the package is unpublished; only string values are timestamps.
+		var ts string
+		if json.Unmarshal(doc.Time[v], &ts) == nil {
+			c.Published = parseTime(ts)
+		}
+		// deprecated is a message; some old packuments carry false.
+		var msg string
+		if json.Unmarshal(meta.Deprecated, &msg) == nil {
+			c.Deprecated = msg
+		}
+		if t := tags[v]; len(t) > 0 {
+			sort.Strings(t)
+			c.Tags = t
+		}
+		out = append(out, c)
+	}
+	return sortCandidates(out), nil
+}
@@ -0,0 +1,54 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+)
+
+const packument = `{
+  "name": "demo",
+  "dist-tags": { "latest": "1.1.0", "next": "2.0.0-rc.1", "stable": "1.1.0" },
+  "versions": {
+    "1.0.0": { "name": "demo", "version": "1.0.0", "deprecated": "use 1.1.0" },
+    "1.1.0": { "name": "demo", "version": "1.1.0", "deprecated": false },
+    "2.0.0-rc.1": { "name": "demo", "version": "2.0.0-rc.1" }
+  },
+  "time": {
+    "created": "2020-01-01T00:00:00.000Z",
+    "modified": "2024-01-01T00:00:00.000Z",
+    "1.0.0": "2020-01-01T00:00:00.000Z",
+    "1.1.0": "2021-06-30T12:00:00.000Z",
+    "2.0.0-rc.1": "2024-01-01T00:00:00.000Z"
+  }
+}`
+
+// ─── ReadNpmPackument ─────────────────────────────────────────────────────────
+
+func TestReadNpmPackument(t *testing.T) {
+	cands, err := ReadNpmPackument(strings.NewReader(packument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, cands,
+		"1.0.0 @2020-01-01 deprecated(use 1.1.0)",
+		"1.1.0 @2021-06-30 [latest,stable]",
+		"2.0.0-rc.1 @2024-01-01 [next]",
+	)
+}
+
+func TestReadNpmPackument_Unpublished(t *testing.T) {
+	doc := `{"name":"gone","time":{"created":"2020-01-01T00:00:00.000Z","unpublished":{"time":"2021-01-01T00:00:00.000Z","versions":["1.0.0"]}}}`
+	cands, err := ReadNpmPackument(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cands) != 0 {
+		t.Errorf("expected no candidates, got %v", cands)
+	}
+}
+
+func TestReadNpmPackument_Invalid(t *testing.T) {
+	if _, err := ReadNpmPackument(strings.NewReader("{")); err == nil {
+		t.Error("expected an error")
+	}
+}
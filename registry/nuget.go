@@ -0,0 +1,94 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+type nugetLeaf struct {
+	CatalogEntry struct {
+		Version     string `json:"version"`
+		Published   string `json:"published"`
+		Listed      *bool  `json:"listed"`
+		Deprecation *struct {
+			Message string   `json:"message"`
+			Reasons []string `json:"reasons"`
+		} `json:"deprecation"`
+	} `json:"catalogEntry"`
+}
+
+// ReadNuGetRegistration reads a NuGet registration index or page. Unlisted
+// versions are reported as yanked; NuGet dates them 1900-01-01, which is
+// dropped. An index whose pages are only linked returns ErrNotInlined,
+// since the versions are in the linked pages.
+func ReadNuGetRegistration(r io.Reader) ([]vars.Candidate, error) {
+	cands, pages, err := readNuGetRegistration(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotInlined, pages[0])
+	}
+	return cands, nil
+}
+
+// readNuGetRegistration returns the versions of a registration index or
+// page, and the URLs of pages that are only linked.
+func readNuGetRegistration(r io.Reader) ([]vars.Candidate, []string, error) {
+	// An index lists pages, a page lists leaves; both call them items.
+	var doc struct {
+		Items []struct {
+			ID    string      `json:"@id"`
+			Count int         `json:"count"`
+			Items []nugetLeaf `json:"items"`
+			nugetLeaf
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("nuget registration: %w", err)
+	}
+
+	var out []vars.Candidate
+	var pages []string
+	for _, item := range doc.Items {
+		switch {
+		case item.CatalogEntry.Version != "":
+			out = append(out, item.candidate())
+		case len(item.Items) > 0:
+			for _, leaf := range item.Items {
+				if leaf.CatalogEntry.Version != "" {
+					out = append(out, leaf.candidate())
+				}
+			}
+		case item.Count > 0:
+			pages = append(pages, item.ID)
+		}
+	}
+	return sortCandidates(out), pages, nil
+}
+
+func (leaf nugetLeaf) candidate() vars.Candidate {
+	e := leaf.CatalogEntry
+	c := vars.Candidate{
+		Version:   e.Version,
+		Published: parseTime(e.Published),
+		Yanked:    e.Listed != nil && !*e.Listed,
+	}
+	if c.Published.Year() <= 1900 {
+		// Feeds that omit listed still date unlisted versions 1900.
+		c.Yanked = c.Yanked || c.Published.Year() == 1900
+		c.Published = time.Time{}
+	}
+	if d := e.Deprecation; d != nil {
+		c.Deprecated = d.Message
+		if c.Deprecated == "" {
+			c.Deprecated = strings.Join(d.Reasons, ", ")
+		}
+	}
+	return c
+}
@@ -0,0 +1,53 @@
+package registry
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const nugetIndex = `{
+  "count": 1,
+  "items": [{
+    "@id": "https://api.example/registration/demo/index.json#page/1.0.0/2.0.0",
+    "count": 3, "lower": "1.0.0", "upper": "2.0.0",
+    "items": [
+      { "catalogEntry": { "id": "Demo", "version": "1.0.0", "published": "2019-03-01T10:00:00+00:00", "listed": true,
+          "deprecation": { "reasons": ["Legacy", "CriticalBugs"] } } },
+      { "catalogEntry": { "id": "Demo", "version": "1.5.0", "published": "1900-01-01T00:00:00+00:00", "listed": false } },
+      { "catalogEntry": { "id": "Demo", "version": "2.0.0", "published": "2022-07-04T08:30:00.123+00:00",
+          "deprecation": { "reasons": ["Other"], "message": "Moved to Demo.Core" } } }
+    ]
+  }]
+}`
+
+// ─── ReadNuGetRegistration ────────────────────────────────────────────────────
+
+func TestReadNuGetRegistration_Index(t *testing.T) {
+	cands, err := ReadNuGetRegistration(strings.NewReader(nugetIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, cands,
+		"1.0.0 @2019-03-01 deprecated(Legacy, CriticalBugs)",
+		"1.5.0 yanked",
+		"2.0.0 @2022-07-04 deprecated(Moved to Demo.Core)",
+	)
+}
+
+func TestReadNuGetRegistration_Page(t *testing.T) {
+	page := `{"count":1,"items":[{"catalogEntry":{"version":"3.0.0","published":"2023-01-01T00:00:00Z"}}]}`
+	cands, err := ReadNuGetRegistration(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, cands, "3.0.0 @2023-01-01")
+}
+
+func TestReadNuGetRegistration_NotInlined(t *testing.T) {
+	index := `{"count":1,"items":[{"@id":"https://api.example/page/1.json","count":64,"lower":"1.0.0","upper":"9.0.0"}]}`
+	_, err := ReadNuGetRegistration(strings.NewReader(index))
+	if !errors.Is(err, ErrNotInlined) {
+		t.Errorf("error: got %v, want ErrNotInlined", err)
+	}
+}
@@ -0,0 +1,166 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+/* ------------------------- */
+/*      JSON API             */
+/* ------------------------- */
+
+// pypiFile is one distribution file of a release, as listed by the JSON API
+// (upload_time_iso_8601, yanked) and the JSON simple API (upload-time,
+// yanked as false or a reason).
+type pypiFile struct {
+	Filename       string          `json:"filename"`
+	UploadTime     string          `json:"upload_time"`
+	UploadTimeISO  string          `json:"upload_time_iso_8601"`
+	UploadTimeDash string          `json:"upload-time"`
+	Yanked         json.RawMessage `json:"yanked"`
+}
+
+func (f pypiFile) uploaded() time.Time {
+	for _, s := range []string{f.UploadTimeISO, f.UploadTimeDash, f.UploadTime} {
+		if t := parseTime(s); !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// yanked accepts true, or a non-false value such as a reason string.
+func (f pypiFile) yanked() bool {
+	s := strings.TrimSpace(string(f.Yanked))
+	return s != "" && s != "false" && s != "null"
+}
+
+// ReadPyPIJSON reads a response of the PyPI JSON API
+// (/pypi/<project>/json). A release is published when its first file was
+// uploaded and yanked when all of its files are.
+func ReadPyPIJSON(r io.Reader) ([]vars.Candidate, error) {
+	var doc struct {
+		Releases map[string][]pypiFile `json:"releases"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("pypi json: %w", err)
+	}
+	out := make([]vars.Candidate, 0, len(doc.Releases))
+	for v, files := range doc.Releases {
+		out = append(out, release(v, files))
+	}
+	return sortCandidates(out), nil
+}
+
+func release(version string, files []pypiFile) vars.Candidate {
+	c := vars.Candidate{Version: version, Yanked: len(files) > 0}
+	for _, f := range files {
+		c.Published = earlier(c.Published, f.uploaded())
+		c.Yanked = c.Yanked && f.yanked()
+	}
+	return c
+}
+
+/* ------------------------- */
+/*      simple index         */
+/* ------------------------- */
+
+var (
+	reSimpleAnchor = regexp.MustCompile(`(?is)<a\s([^>]*)>(.*?)</a>`)
+	reSimpleYanked = regexp.MustCompile(`(?i)(^|\s)data-yanked(\s|=|$)`)
+	rePyPINameRun  = regexp.MustCompile(`[-_.]+`)
+)
+
+// ReadPyPISimple reads a simple-index page for project, in the HTML format
+// of PEP 503 or the JSON format of PEP 691. Versions are taken from the
+// distribution file names; upload times are only known to the JSON format.
+// A version is yanked when all of its files are.
+func ReadPyPISimple(project string, r io.Reader) ([]vars.Candidate, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("pypi simple: %w", err)
+	}
+
+	var files []pypiFile
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var doc struct {
+			Files []pypiFile `json:"files"`
+		}
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("pypi simple: %w", err)
+		}
+		files = doc.Files
+	} else {
+		for _, m := range reSimpleAnchor.FindAllSubmatch(data, -1) {
+			f := pypiFile{Filename: strings.TrimSpace(html.UnescapeString(string(m[2])))}
+			if reSimpleYanked.Match(m[1]) {
+				f.Yanked = json.RawMessage("true")
+			}
+			files = append(files, f)
+		}
+	}
+
+	byVersion := map[string][]pypiFile{}
+	var order []string
+	for _, f := range files {
+		v := fileVersion(project, f.Filename)
+		if v == "" {
+			continue
+		}
+		if _, ok := byVersion[v]; !ok {
+			order = append(order, v)
+		}
+		byVersion[v] = append(byVersion[v], f)
+	}
+	out := make([]vars.Candidate, 0, len(order))
+	for _, v := range order {
+		out = append(out, release(v, byVersion[v]))
+	}
+	return sortCandidates(out), nil
+}
+
+var sdistExtensions = []string{".tar.gz", ".tar.bz2", ".tar.xz", ".tar.Z", ".tgz", ".tar", ".zip"}
+
+// fileVersion extracts the version from a distribution file name, or
+// returns "" for files it does not recognise.
+func fileVersion(project, filename string) string {
+	// Wheels and eggs put the version in the second dash-separated field.
+	for _, ext := range []string{".whl", ".egg"} {
+		if stem, ok := strings.CutSuffix(filename, ext); ok {
+			parts := strings.Split(stem, "-")
+			if len(parts) < 2 {
+				return ""
+			}
+			return parts[1]
+		}
+	}
+	for _, ext := range sdistExtensions {
+		stem, ok := strings.CutSuffix(filename, ext)
+		if !ok {
+			continue
+		}
+		// Source distributions are "<name>-<version>", where the name may
+		// itself contain dashes and be spelled differently than project.
+		if n := len(project); len(stem) > n+1 && stem[n] == '-' && normalizeName(stem[:n]) == normalizeName(project) {
+			return stem[n+1:]
+		}
+		if i := strings.LastIndex(stem, "-"); i > 0 {
+			return stem[i+1:]
+		}
+		return ""
+	}
+	return ""
+}
+
+// normalizeName applies PEP 503 name normalization.
+func normalizeName(name string) string {
+	return strings.ToLower(rePyPINameRun.ReplaceAllString(name, "-"))
+}
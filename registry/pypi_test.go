@@ -0,0 +1,89 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+)
+
+const pypiJSON = `{
+  "info": { "name": "demo", "version": "1.1" },
+  "releases": {
+    "1.0": [
+      { "filename": "demo-1.0-py3-none-any.whl", "upload_time_iso_8601": "2020-02-01T09:00:00.123456Z", "yanked": false },
+      { "filename": "demo-1.0.tar.gz", "upload_time_iso_8601": "2020-01-31T09:00:00.000000Z", "yanked": false }
+    ],
+    "1.1": [
+      { "filename": "demo-1.1.tar.gz", "upload_time": "2021-05-05T05:05:05", "yanked": false }
+    ],
+    "1.2": [
+      { "filename": "demo-1.2.tar.gz", "upload_time_iso_8601": "2022-01-01T00:00:00Z", "yanked": true, "yanked_reason": "broken" }
+    ],
+    "0.1": []
+  }
+}`
+
+const pypiSimpleHTML = `<!DOCTYPE html>
+<html><body><h1>Links for python-dateutil</h1>
+<a href="https://files.example/python-dateutil-2.8.0.tar.gz#sha256=aa">python-dateutil-2.8.0.tar.gz</a><br/>
+<a href="https://files.example/python_dateutil-2.8.0-py2.py3-none-any.whl#sha256=bb" data-requires-python="&gt;=2.7">python_dateutil-2.8.0-py2.py3-none-any.whl</a><br/>
+<a href="https://files.example/python-dateutil-2.8.1.tar.gz" data-yanked="">python-dateutil-2.8.1.tar.gz</a><br/>
+<a href="https://files.example/python_dateutil-2.8.1-py2.py3-none-any.whl" data-yanked="bad build">python_dateutil-2.8.1-py2.py3-none-any.whl</a><br/>
+<a href="https://files.example/python-dateutil-2.9.0.post0.tar.gz">python-dateutil-2.9.0.post0.tar.gz</a><br/>
+<a href="https://files.example/python_dateutil-2.9.0.post0-py2.py3-none-any.whl" data-yanked="">python_dateutil-2.9.0.post0-py2.py3-none-any.whl</a><br/>
+<a href="https://files.example/README">README</a>
+</body></html>`
+
+const pypiSimpleJSON = `{
+  "meta": { "api-version": "1.1" },
+  "name": "demo",
+  "versions": ["1.0", "1.1"],
+  "files": [
+    { "filename": "demo-1.0.tar.gz", "url": "https://files.example/demo-1.0.tar.gz", "hashes": {}, "yanked": false, "upload-time": "2020-01-31T09:00:00.000000Z" },
+    { "filename": "demo-1.1-py3-none-any.whl", "url": "https://files.example/demo-1.1-py3-none-any.whl", "hashes": {}, "yanked": "security issue", "upload-time": "2021-05-05T05:05:05Z" }
+  ]
+}`
+
+// ─── ReadPyPIJSON ─────────────────────────────────────────────────────────────
+
+func TestReadPyPIJSON(t *testing.T) {
+	cands, err := ReadPyPIJSON(strings.NewReader(pypiJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, cands, "0.1", "1.0 @2020-01-31", "1.1 @2021-05-05", "1.2 @2022-01-01 yanked")
+}
+
+// ─── ReadPyPISimple ───────────────────────────────────────────────────────────
+
+func TestReadPyPISimple_HTML(t *testing.T) {
+	cands, err := ReadPyPISimple("python-dateutil", strings.NewReader(pypiSimpleHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, cands, "2.8.0", "2.8.1 yanked", "2.9.0.post0")
+}
+
+func TestReadPyPISimple_JSON(t *testing.T) {
+	cands, err := ReadPyPISimple("demo", strings.NewReader(pypiSimpleJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCandidates(t, cands, "1.0 @2020-01-31", "1.1 @2021-05-05 yanked")
+}
+
+func TestFileVersion(t *testing.T) {
+	cases := []struct{ project, file, want string }{
+		{"python-dateutil", "python-dateutil-2.8.2.tar.gz", "2.8.2"},
+		{"python-dateutil", "python_dateutil-2.8.2.tar.gz", "2.8.2"},
+		{"zope.interface", "zope.interface-6.0.zip", "6.0"},
+		{"demo", "demo-1.0rc1-cp312-cp312-manylinux_2_17_x86_64.whl", "1.0rc1"},
+		{"demo", "demo-0.9-py2.7.egg", "0.9"},
+		{"other", "demo-1.0.tar.gz", "1.0"},
+		{"demo", "demo.exe", ""},
+	}
+	for _, c := range cases {
+		if got := fileVersion(c.project, c.file); got != c.want {
+			t.Errorf("fileVersion(%q, %q): got %q, want %q", c.project, c.file, got, c.want)
+		}
+	}
+}
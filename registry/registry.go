@@ -0,0 +1,87 @@
+// Package registry turns archived registry responses into candidate lists.
+//
+// Each reader takes one response as the registry served it (an npm
+// packument, a PyPI JSON or simple-index page, crates.io index lines, a
+// RubyGems versions file, a NuGet registration page or a Go proxy version
+// list) and returns []vars.Candidate sorted by ascending version. Versions
+// turns such a list into the []string that resolver.AnalyzeConstraint and
+// semver.SortedVersions take.
+package registry
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ErrNotInlined is returned by ReadNuGetRegistration for registration pages
+// whose items are only linked, not included.
+var ErrNotInlined = errors.New("registration page not inlined")
+
+// Versions returns the version strings of cands, in order.
+func Versions(cands []vars.Candidate) []string {
+	out := make([]string, 0, len(cands))
+	for _, c := range cands {
+		out = append(out, c.Version)
+	}
+	return out
+}
+
+// WithoutYanked returns the candidates that are not yanked.
+func WithoutYanked(cands []vars.Candidate) []vars.Candidate {
+	out := make([]vars.Candidate, 0, len(cands))
+	for _, c := range cands {
+		if !c.Yanked {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// sortCandidates orders cands by ascending version, keeping the registry's
+// order among versions that compare equal.
+func sortCandidates(cands []vars.Candidate) []vars.Candidate {
+	return sortCandidatesWith(cands, canonicalized.NewVersion)
+}
+
+// sortCandidatesWith is sortCandidates with versions read by parse.
+func sortCandidatesWith(cands []vars.Candidate, parse func(string) canonicalized.Version) []vars.Candidate {
+	parsed := make([]canonicalized.Version, len(cands))
+	for i, c := range cands {
+		parsed[i] = parse(c.Version)
+	}
+	idx := make([]int, len(cands))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return parsed[idx[i]].LessThan(&parsed[idx[j]])
+	})
+	out := make([]vars.Candidate, len(cands))
+	for i, k := range idx {
+		out[i] = cands[k]
+	}
+	return out
+}
+
+// parseTime reads an RFC 3339 timestamp, with or without a zone; anything
+// else is the zero time.
+func parseTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// earlier keeps the earliest known time of a and b.
+func earlier(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
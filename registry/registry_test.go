@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/resolver"
+	"github.com/rng70/versions/v2/semver"
+	"github.com/rng70/versions/v2/vars"
+)
+
+func summarize(cands []vars.Candidate) []string {
+	var out []string
+	for _, c := range cands {
+		s := c.Version
+		if !c.Published.IsZero() {
+			s += " @" + c.Published.Format("2006-01-02")
+		}
+		if c.Yanked {
+			s += " yanked"
+		}
+		if c.Deprecated != "" {
+			s += " deprecated(" + c.Deprecated + ")"
+		}
+		if len(c.Tags) > 0 {
+			s += " [" + strings.Join(c.Tags, ",") + "]"
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func assertCandidates(t *testing.T, got []vars.Candidate, want ...string) {
+	t.Helper()
+	if g := summarize(got); !reflect.DeepEqual(g, want) {
+		t.Errorf("candidates:\n got  %q\n want %q", g, want)
+	}
+}
+
+// ─── Versions ─────────────────────────────────────────────────────────────────
+
+func TestVersions_PlugIntoResolverAndSemver(t *testing.T) {
+	cands, err := ReadCratesIndex(strings.NewReader(cratesIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions := Versions(WithoutYanked(cands))
+	if want := []string{"0.9.0", "1.0.0", "1.2.0-beta.1", "1.2.0"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("versions: got %q, want %q", versions, want)
+	}
+
+	// The yanked 1.1.0 is not offered.
+	a := resolver.AnalyzeConstraint(vars.StyleRust, ">=1.0.0, <1.1.5", versions)
+	if want := []string{"1.0.0"}; !reflect.DeepEqual(a.Matches, want) {
+		t.Errorf("matches: got %q, want %q", a.Matches, want)
+	}
+	if got := semver.SortedVersions(versions, true); got[0] != "1.2.0" {
+		t.Errorf("highest: got %q, want %q", got[0], "1.2.0")
+	}
+}
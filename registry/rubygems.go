@@ -0,0 +1,92 @@
+package registry
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ReadRubyGemsVersions reads the versions of gem from a compact index
+// versions file. The file is append-only: a gem may have several lines, and
+// a version prefixed with "-" on a later line has been yanked. Platform
+// builds such as "1.0.0-java" count as their version, which is yanked only
+// when every build of it is. The file carries no publish times.
+func ReadRubyGemsVersions(gem string, r io.Reader) ([]vars.Candidate, error) {
+	// yanked[build] is the latest state of each platform build.
+	yanked := map[string]bool{}
+	var builds []string
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	header := true
+	for sc.Scan() {
+		line := sc.Text()
+		if header {
+			// A created_at line and a "---" separator precede the gems.
+			header = line != "---"
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != gem {
+			continue
+		}
+		for _, v := range strings.Split(fields[1], ",") {
+			removed := strings.HasPrefix(v, "-")
+			v = strings.TrimPrefix(v, "-")
+			if v == "" {
+				continue
+			}
+			if _, ok := yanked[v]; !ok {
+				builds = append(builds, v)
+			}
+			yanked[v] = removed
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("rubygems versions: %w", err)
+	}
+
+	index := map[string]int{}
+	var out []vars.Candidate
+	for _, b := range builds {
+		v, _, _ := strings.Cut(b, "-")
+		i, ok := index[v]
+		if !ok {
+			i = len(out)
+			index[v] = i
+			out = append(out, vars.Candidate{Version: v, Yanked: true})
+		}
+		out[i].Yanked = out[i].Yanked && yanked[b]
+	}
+	return sortCandidates(out), nil
+}
+
+// ReadRubyGemsAPI reads the versions of a gem from the RubyGems JSON API
+// (/api/v1/versions/<gem>.json), which has publish times but leaves yanked
+// versions out. Platform builds count as their version, published with the
+// earliest build.
+func ReadRubyGemsAPI(r io.Reader) ([]vars.Candidate, error) {
+	var doc []struct {
+		Number    string `json:"number"`
+		CreatedAt string `json:"created_at"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("rubygems api: %w", err)
+	}
+	index := map[string]int{}
+	var out []vars.Candidate
+	for _, d := range doc {
+		i, ok := index[d.Number]
+		if !ok {
+			i = len(out)
+			index[d.Number] = i
+			out = append(out, vars.Candidate{Version: d.Number})
+		}
+		out[i].Published = earlier(out[i].Published, parseTime(d.CreatedAt))
+	}
+	return sortCandidates(out), nil
+}
@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+)
+
+const rubygemsVersions = `created_at: 2024-04-01T00:00:05Z
+---
+rack 0.1.0,0.2.0,1.0.0,1.0.0-java 0123456789abcdef0123456789abcdef
+rake 13.0.0 fedcba9876543210fedcba9876543210
+rack 1.1.0.beta1 00000000000000000000000000000000
+rack -0.2.0 11111111111111111111111111111111
+rack -1.0.0 22222222222222222222222222222222
+`
+
+// ─── ReadRubyGemsVersions ─────────────────────────────────────────────────────
+
+func TestReadRubyGemsVersions(t *testing.T) {
+	cands, err := ReadRubyGemsVersions("rack", strings.NewReader(rubygemsVersions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1.0.0 keeps its java build, so it is not yanked.
+	assertCandidates(t, cands, "0.1.0", "0.2.0 yanked", "1.0.0", "1.1.0.beta1")
+}
+
+func TestReadRubyGemsVersions_UnknownGem(t *testing.T) {
+	cands, err := ReadRubyGemsVersions("rails", strings.NewReader(rubygemsVersions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cands) != 0 {
+		t.Errorf("expected no candidates, got %v", cands)
+	}
+}
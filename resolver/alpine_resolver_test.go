@@ -0,0 +1,48 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+var alpineVersions = []string{"3.0.8-r0", "3.0.12-r0", "3.0.12-r4", "3.1.0_rc1-r0", "3.1.0-r0", "3.1.0_p1-r0"}
+
+func TestAlpine_FixedIn(t *testing.T) {
+	// A secdb entry fixed in 3.0.12-r4: installed versions below it are
+	// affected.
+	a := AnalyzeConstraint(vars.StyleAlpine, "openssl<3.0.12-r4", alpineVersions)
+	assertParsedCount(t, a, 1)
+	assertMatches(t, a, []string{"3.0.8-r0", "3.0.12-r0"})
+}
+
+func TestAlpine_Fuzzy(t *testing.T) {
+	a := AnalyzeConstraint(vars.StyleAlpine, "openssl~3.1", alpineVersions)
+	assertMatches(t, a, []string{"3.1.0_rc1-r0", "3.1.0-r0", "3.1.0_p1-r0"})
+}
+
+func TestAlpine_OutdatedWanted(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleAlpine, "openssl~3.0", "3.0.8-r0", alpineVersions)
+	if o.Wanted != "3.0.12-r4" {
+		t.Errorf("wanted: got %q, want %q", o.Wanted, "3.0.12-r4")
+	}
+}
+
+func TestAlpine_Explain(t *testing.T) {
+	if e := Explain(vars.StyleAlpine, ">=1.2.3", "1.2.3_rc1-r0"); e.Satisfied {
+		t.Error("1.2.3_rc1-r0 sorts before 1.2.3 and should not satisfy >=1.2.3")
+	}
+	if e := Explain(vars.StyleAlpine, ">1.2.3-r0", "1.2.3_p1-r0"); !e.Satisfied {
+		t.Error("1.2.3_p1-r0 sorts after 1.2.3-r0 and should satisfy >1.2.3-r0")
+	}
+}
+
+func TestAlpine_OutdatedPatchSuffix(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleAlpine, "zlib", "1.2.3-r0", []string{"1.2.3-r0", "1.2.3_p1-r0", "1.2.4_rc1-r0"})
+	if o.Latest != "1.2.3_p1-r0" {
+		t.Errorf("latest: got %q, want %q", o.Latest, "1.2.3_p1-r0")
+	}
+	if o.Bump == vars.BumpNone || o.Breaking {
+		t.Errorf("1.2.3-r0 -> 1.2.3_p1-r0: got bump=%q breaking=%v", o.Bump, o.Breaking)
+	}
+}
@@ -0,0 +1,69 @@
+package resolver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/vars"
+)
+
+var composerVersions = []string{"dev-main", "5.4.0", "6.3.0", "6.3.12", "6.4.0", "7.0.0-BETA1", "7.0.0", "7.0.0-p1"}
+
+func TestComposer_TildeOrCaret(t *testing.T) {
+	a := AnalyzeConstraint(vars.StyleComposer, "~6.3.0 || ^7.0", composerVersions)
+	assertParsedCount(t, a, 2)
+	assertMatches(t, a, []string{"6.3.0", "6.3.12", "7.0.0-BETA1", "7.0.0", "7.0.0-p1"})
+	if got := parser.FilterStability(a.Matches, parser.ComposerStability("~6.3.0 || ^7.0", "")); len(got) != 4 {
+		t.Errorf("stable matches: got %v, want 4", got)
+	}
+}
+
+func TestComposer_UpperBoundExcludesPrerelease(t *testing.T) {
+	a := AnalyzeConstraint(vars.StyleComposer, ">=6.0 <7.0", composerVersions)
+	assertMatches(t, a, []string{"6.3.0", "6.3.12", "6.4.0"})
+}
+
+func TestComposer_Branch(t *testing.T) {
+	a := AnalyzeConstraint(vars.StyleComposer, "dev-main", composerVersions)
+	assertMatches(t, a, []string{"dev-main"})
+}
+
+func TestComposer_OutdatedWanted(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleComposer, "^7.0", "7.0.0", composerVersions)
+	if o.Wanted != "7.0.0-p1" {
+		t.Errorf("wanted: got %q, want %q", o.Wanted, "7.0.0-p1")
+	}
+}
+
+func TestComposer_Explain(t *testing.T) {
+	if e := Explain(vars.StyleComposer, ">=7.0.0", "7.0.0-p1"); !e.Satisfied {
+		t.Error("7.0.0-p1 is a patch release after 7.0.0 and should satisfy >=7.0.0")
+	}
+	if e := Explain(vars.StyleComposer, ">=7.0.0", "6.4.0"); e.Satisfied {
+		t.Error("6.4.0 sorts before 7.0.0 and should not satisfy >=7.0.0")
+	}
+	// Like Composer, >=7.0.0 is >=7.0.0-dev; stability decides the rest.
+	if e := Explain(vars.StyleComposer, ">=7.0.0", "7.0.0-BETA1"); !e.Satisfied {
+		t.Error("7.0.0-BETA1 is within >=7.0.0-dev and should satisfy the range")
+	}
+}
+
+func TestComposer_StabilityFlag(t *testing.T) {
+	a := AnalyzeConstraint(vars.StyleComposer, "^7.0@beta", composerVersions)
+	got := parser.FilterStability(a.Matches, parser.ComposerStability("^7.0@beta", ""))
+	want := []string{"7.0.0-BETA1", "7.0.0", "7.0.0-p1"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestComposer_OutdatedLatest(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleComposer, "~6.3.0", "6.3.0", composerVersions)
+	if o.Latest != "7.0.0-p1" {
+		t.Errorf("latest: got %q, want %q", o.Latest, "7.0.0-p1")
+	}
+	if o.Bump != vars.BumpMajor || !o.Breaking {
+		t.Errorf("6.3.0 -> 7.0.0-p1: got bump=%q breaking=%v", o.Bump, o.Breaking)
+	}
+}
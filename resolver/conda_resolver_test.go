@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+var condaVersions = []string{"1.19.0rc1", "1.19.5", "1.21.0", "1.26.4", "2.0.0rc1", "2.0.0", "2.0.0.post1"}
+
+func TestConda_MatchSpec(t *testing.T) {
+	a := AnalyzeConstraint(vars.StyleConda, "numpy>=1.21,<2|1.19.*", condaVersions)
+	assertParsedCount(t, a, 2)
+	assertMatches(t, a, []string{"1.19.0rc1", "1.19.5", "1.21.0", "1.26.4", "2.0.0rc1"})
+}
+
+func TestConda_SingleEquals(t *testing.T) {
+	a := AnalyzeConstraint(vars.StyleConda, "numpy=2.0=py311*", condaVersions)
+	assertMatches(t, a, []string{"2.0.0rc1", "2.0.0", "2.0.0.post1"})
+}
+
+func TestConda_OutdatedWanted(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleConda, "numpy 1.*", "1.19.5", condaVersions)
+	if o.Wanted != "1.26.4" {
+		t.Errorf("wanted: got %q, want %q", o.Wanted, "1.26.4")
+	}
+}
+
+func TestConda_Explain(t *testing.T) {
+	e := Explain(vars.StyleConda, ">=1.0", "1!0.1")
+	if !e.Satisfied {
+		t.Fatal("1!0.1 has a higher epoch and should satisfy >=1.0")
+	}
+	if c := e.Groups[0].Checks[0].Component; c != "epoch" {
+		t.Errorf("component: got %q, want %q", c, "epoch")
+	}
+}
+
+func TestConda_OutdatedEpoch(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleConda, ">=0.1", "1.0", []string{"1.0", "1.5", "1!0.1"})
+	if o.Wanted != "1!0.1" || o.Latest != "1!0.1" {
+		t.Errorf("got wanted=%q latest=%q, want both 1!0.1", o.Wanted, o.Latest)
+	}
+	if o.Bump != vars.BumpMajor || !o.Breaking {
+		t.Errorf("epoch change: got bump=%q breaking=%v", o.Bump, o.Breaking)
+	}
+}
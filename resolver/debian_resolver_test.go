@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+var debianVersions = []string{"2.31-0ubuntu9", "2.31-0ubuntu9.2", "2.31-0ubuntu9.9", "2.31-0ubuntu9.10", "2.35-0ubuntu3~rc1", "2.35-0ubuntu3"}
+
+func TestDebian_FixedIn(t *testing.T) {
+	// An advisory fixed in 2.31-0ubuntu9.9: installed versions below it are
+	// affected.
+	a := AnalyzeConstraint(vars.StyleDebian, "<< 2.31-0ubuntu9.9", debianVersions)
+	assertParsedCount(t, a, 1)
+	assertMatches(t, a, []string{"2.31-0ubuntu9", "2.31-0ubuntu9.2"})
+}
+
+func TestDebian_Tilde(t *testing.T) {
+	a := AnalyzeConstraint(vars.StyleDebian, ">= 2.35", debianVersions)
+	assertMatches(t, a, []string{"2.35-0ubuntu3~rc1", "2.35-0ubuntu3"})
+}
+
+func TestDebian_OutdatedWanted(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleDebian, "libc6 (<< 2.32)", "2.31-0ubuntu9", debianVersions)
+	if o.Wanted != "2.31-0ubuntu9.10" {
+		t.Errorf("wanted: got %q, want %q", o.Wanted, "2.31-0ubuntu9.10")
+	}
+}
+
+func TestDebian_Explain(t *testing.T) {
+	if e := Explain(vars.StyleDebian, ">= 1.0", "1.0~rc1"); e.Satisfied {
+		t.Error("1.0~rc1 sorts before 1.0 and should not satisfy >= 1.0")
+	}
+	e := Explain(vars.StyleDebian, ">= 1.0", "1:0.5")
+	if !e.Satisfied {
+		t.Fatal("1:0.5 has a higher epoch and should satisfy >= 1.0")
+	}
+	if c := e.Groups[0].Checks[0].Component; c != "epoch" {
+		t.Errorf("component: got %q, want %q", c, "epoch")
+	}
+}
+
+func TestDebian_OutdatedEpoch(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleDebian, ">= 0.1", "0.9-1", []string{"0.9-1", "1.0-1", "1:0.5"})
+	if o.Wanted != "1:0.5" || o.Latest != "1:0.5" {
+		t.Errorf("got wanted=%q latest=%q, want both 1:0.5", o.Wanted, o.Latest)
+	}
+	if o.Bump != vars.BumpMajor || !o.Breaking {
+		t.Errorf("epoch change: got bump=%q breaking=%v", o.Bump, o.Breaking)
+	}
+}
@@ -0,0 +1,27 @@
+package resolver
+
+import (
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// Explain reports why version does or does not satisfy constraint: the
+// normalised bounds of every OR group, which groups were tried and the
+// outcome of each comparator. Use the String method for a text rendering.
+func Explain(style vars.Style, constraint, version string) vars.Explanation {
+	parsed, err := parser.Parse(style, constraint)
+	if err != nil {
+		return vars.Explanation{
+			Style:      style,
+			Constraint: constraint,
+			Version:    version,
+			Groups:     []vars.GroupExplanation{},
+			Error:      err.Error(),
+		}
+	}
+
+	e := parser.ExplainMatchFor(style, parsed, version)
+	e.Style = style
+	e.Constraint = constraint
+	return e
+}
@@ -0,0 +1,62 @@
+package resolver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+func TestExplain_CaretNormalised(t *testing.T) {
+	e := Explain(vars.StyleNPM, "^1.2.0", "1.5.0-beta.1")
+	if !e.Satisfied {
+		t.Fatal("1.5.0-beta.1 should satisfy ^1.2.0")
+	}
+	if len(e.Groups) != 1 || e.Groups[0].Normalized != ">=1.2.0 <2.0.0" {
+		t.Errorf("normalised: got %+v", e.Groups)
+	}
+	if e.Style != vars.StyleNPM || e.Constraint != "^1.2.0" {
+		t.Errorf("header: got style=%q constraint=%q", e.Style, e.Constraint)
+	}
+}
+
+func TestExplain_Text(t *testing.T) {
+	text := Explain(vars.StyleNPM, "^1.2.0", "2.1.0").String()
+	for _, want := range []string{
+		"2.1.0 does not satisfy ^1.2.0 (npm)",
+		"group 1: >=1.2.0 <2.0.0 -> failed",
+		">=1.2.0: pass (decided by major)",
+		"<2.0.0: fail (decided by minor)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestExplain_UnsupportedSource(t *testing.T) {
+	e := Explain(vars.StyleNPM, "https://example.com/pkg.tgz", "1.0.0")
+	if e.Satisfied || e.Error == "" {
+		t.Errorf("expected error explanation, got %+v", e)
+	}
+	if !strings.Contains(e.String(), "error: unsupported version source") {
+		t.Errorf("text: %s", e.String())
+	}
+}
+
+func TestExplain_UnknownStyle(t *testing.T) {
+	e := Explain("unknown", ">=1.0.0", "1.0.0")
+	if e.Error == "" {
+		t.Error("expected an error for unknown style")
+	}
+}
+
+func TestExplain_EmptyConstraint(t *testing.T) {
+	e := Explain(vars.StylePy, "", "1.0.0")
+	if e.Satisfied || len(e.Groups) != 0 {
+		t.Errorf("got %+v", e)
+	}
+	if !strings.Contains(e.String(), "no groups") {
+		t.Errorf("text: %s", e.String())
+	}
+}
@@ -66,6 +66,25 @@ func TestNPM_Latest(t *testing.T) {
 	assertMatches(t, a, []string{}) // "latest" not in TestVersions
 }
 
+func TestNPM_DistTags(t *testing.T) {
+	versions := []string{"1.0.0", "1.1.0", "2.0.0-rc.1"}
+	tags := map[string]string{"latest": "1.1.0", "next": "2.0.0-rc.1"}
+	assertMatches(t, AnalyzeConstraintWithTags(vars.StyleNPM, "latest", versions, tags), []string{"1.1.0"})
+	assertMatches(t, AnalyzeConstraintWithTags(vars.StyleNPM, "npm:alias@next", versions, tags), []string{"2.0.0-rc.1"})
+	assertMatches(t, AnalyzeConstraintWithTags(vars.StyleNPM, "canary", versions, tags), []string{})
+}
+
+func TestNPM_AnalyzeCandidates(t *testing.T) {
+	cands := []vars.Candidate{
+		{Version: "1.0.0"},
+		{Version: "1.1.0", Tags: []string{"latest"}},
+		{Version: "1.2.0", Yanked: true},
+		{Version: "3.0.0-beta.1", Tags: []string{"beta"}},
+	}
+	assertMatches(t, AnalyzeCandidates(vars.StyleNPM, "beta", cands), []string{"3.0.0-beta.1"})
+	assertMatches(t, AnalyzeCandidates(vars.StyleNPM, "^1.0.0", cands), []string{"1.0.0", "1.1.0"})
+}
+
 func TestNPM_PkgAlias(t *testing.T) {
 	a := AnalyzeConstraint(vars.StyleNPM, "npm:pkg@1.0.0", vars.TestVersions)
 	assertParsedCount(t, a, 1)
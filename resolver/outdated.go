@@ -0,0 +1,173 @@
+package resolver
+
+import (
+	"regexp"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// AnalyzeOutdated reports the current, wanted and latest versions of a
+// dependency, the way `npm outdated` does.
+//
+// Wanted is the highest stable version satisfying constraint, or the highest
+// pre-release when constraint names one; Latest is the highest stable
+// version. Both fall back to the highest version when nothing is stable.
+// Bump and Breaking describe the move from current to Latest and are left
+// empty when current is not set.
+func AnalyzeOutdated(style vars.Style, constraint, current string, versions []string) vars.Outdated {
+	out := vars.Outdated{Constraint: constraint, Current: current, Bump: vars.BumpNone}
+
+	a := AnalyzeConstraint(style, constraint, versions)
+	out.Wanted = highestOf(style, a.Matches, namesPrerelease(style, constraint))
+	out.Latest = highestOf(style, versions, false)
+
+	if current == "" || out.Latest == "" {
+		return out
+	}
+	if cmp := parser.Comparator(style); cmp != nil {
+		if n, ok := cmp(out.Latest, current); !ok || n <= 0 {
+			return out
+		}
+		out.Bump = ClassifyBumpFor(style, current, out.Latest)
+		out.Breaking = IsBreaking(style, current, out.Latest)
+		return out
+	}
+	cur := canonicalized.NewVersion(current)
+	latest := canonicalized.NewVersion(out.Latest)
+	if !latest.GreaterThan(&cur) {
+		return out
+	}
+	out.Bump = classifyBump(&cur, &latest)
+	out.Breaking = isBreaking(style, &cur, &latest)
+	return out
+}
+
+// ClassifyBump returns the most significant component that differs between
+// from and to, regardless of direction.
+func ClassifyBump(from, to string) vars.Bump {
+	f := canonicalized.NewVersion(from)
+	t := canonicalized.NewVersion(to)
+	return classifyBump(&f, &t)
+}
+
+// ClassifyBumpFor is ClassifyBump under the ordering of style. For styles
+// with their own comparator (see parser.Comparator) an epoch change counts
+// as major, a packaging revision as patch, and a move between versions it
+// cannot parse as major.
+func ClassifyBumpFor(style vars.Style, from, to string) vars.Bump {
+	if parser.Comparator(style) == nil {
+		return ClassifyBump(from, to)
+	}
+	switch parser.DecidingComponent(style, from, to) {
+	case "epoch", "major", "literal":
+		return vars.BumpMajor
+	case "minor":
+		return vars.BumpMinor
+	case "patch", "revision":
+		return vars.BumpPatch
+	case "prerelease":
+		return vars.BumpPrerelease
+	default:
+		return vars.BumpNone
+	}
+}
+
+// IsBreaking reports whether moving from one version to another crosses a
+// breaking-change boundary under the rules of style.
+//
+// npm and Cargo follow caret semantics, so for 0.x releases the minor (and for
+// 0.0.x the patch) component is the breaking one. Every other ecosystem treats
+// only a major change as breaking; for styles with their own comparator an
+// epoch change is breaking too.
+func IsBreaking(style vars.Style, from, to string) bool {
+	if parser.Comparator(style) != nil {
+		return ClassifyBumpFor(style, from, to) == vars.BumpMajor
+	}
+	f := canonicalized.NewVersion(from)
+	t := canonicalized.NewVersion(to)
+	return isBreaking(style, &f, &t)
+}
+
+func classifyBump(from, to *canonicalized.Version) vars.Bump {
+	switch {
+	case valueOf(from.Major) != valueOf(to.Major):
+		return vars.BumpMajor
+	case valueOf(from.Minor) != valueOf(to.Minor):
+		return vars.BumpMinor
+	case valueOf(from.Patch) != valueOf(to.Patch), valueOf(from.Revision) != valueOf(to.Revision):
+		return vars.BumpPatch
+	case from.Compare(to) != 0:
+		return vars.BumpPrerelease
+	default:
+		return vars.BumpNone
+	}
+}
+
+func isBreaking(style vars.Style, from, to *canonicalized.Version) bool {
+	if valueOf(from.Major) != valueOf(to.Major) {
+		return true
+	}
+	switch style {
+	case vars.StyleNPM, vars.StyleRust:
+		if valueOf(from.Major) != 0 {
+			return false
+		}
+		if valueOf(from.Minor) != valueOf(to.Minor) {
+			return true
+		}
+		return valueOf(from.Minor) == 0 && valueOf(from.Patch) != valueOf(to.Patch)
+	default:
+		return false
+	}
+}
+
+// highestOf returns the highest version in versions under the ordering of
+// style. Unless pre is set it skips pre-releases, falling back to the
+// highest version when none is stable.
+func highestOf(style vars.Style, versions []string, pre bool) string {
+	sorted := parser.SortVersionsFor(style, versions, true)
+	for _, v := range sorted {
+		if pre || !parser.IsPrereleaseFor(style, v) {
+			return v
+		}
+	}
+	if len(sorted) > 0 {
+		return sorted[0]
+	}
+	return ""
+}
+
+// reVersionToken matches a version as written in a constraint: a digit,
+// optionally after a v, that does not continue a name such as libc6.
+var reVersionToken = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.-])(v?\d[^\s,|()<>=!^\[\]@]*)`)
+
+// reWildcardTail matches the wildcard components of 1.x or 1.19.*.
+var reWildcardTail = regexp.MustCompile(`(\.[xX*])+$`)
+
+// namesPrerelease reports whether constraint spells out a pre-release, as
+// in ^1.2.0-beta.1 or >= 1.0~rc1. Only the versions as written count, not
+// the bounds a parser derives from them, such as the 2.0.0-dev of
+// Composer's ^1.0.
+func namesPrerelease(style vars.Style, constraint string) bool {
+	for _, m := range reVersionToken.FindAllStringSubmatch(constraint, -1) {
+		v := reWildcardTail.ReplaceAllString(m[1], "")
+		if cmp := parser.Comparator(style); cmp != nil {
+			if _, ok := cmp(v, v); !ok {
+				continue
+			}
+		}
+		if parser.IsPrereleaseFor(style, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func valueOf(p *int64) int64 {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
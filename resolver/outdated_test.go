@@ -0,0 +1,158 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+var outdatedVersions = []string{
+	"1.0.0",
+	"1.2.0",
+	"1.4.2",
+	"1.5.0-beta.1",
+	"2.0.0",
+	"2.1.0",
+	"3.0.0-rc.1",
+}
+
+// ─── AnalyzeOutdated ──────────────────────────────────────────────────────────
+
+func TestOutdated_NPMCaret(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleNPM, "^1.2.0", "1.2.0", outdatedVersions)
+	if o.Wanted != "1.4.2" {
+		t.Errorf("wanted: got %q, want %q", o.Wanted, "1.4.2")
+	}
+	if o.Latest != "2.1.0" {
+		t.Errorf("latest: got %q, want %q", o.Latest, "2.1.0")
+	}
+	if o.Bump != vars.BumpMajor {
+		t.Errorf("bump: got %q, want %q", o.Bump, vars.BumpMajor)
+	}
+	if !o.Breaking {
+		t.Error("1.2.0 -> 2.1.0 should be breaking")
+	}
+}
+
+func TestOutdated_UpToDate(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleNPM, "~2.1.0", "2.1.0", outdatedVersions)
+	if o.Wanted != "2.1.0" || o.Latest != "2.1.0" {
+		t.Errorf("got wanted=%q latest=%q, want both 2.1.0", o.Wanted, o.Latest)
+	}
+	if o.Bump != vars.BumpNone || o.Breaking {
+		t.Errorf("up to date: got bump=%q breaking=%v", o.Bump, o.Breaking)
+	}
+}
+
+func TestOutdated_NoCurrent(t *testing.T) {
+	o := AnalyzeOutdated(vars.StylePy, ">=1.0,<2.0", "", outdatedVersions)
+	if o.Wanted != "1.4.2" {
+		t.Errorf("wanted: got %q", o.Wanted)
+	}
+	if o.Bump != vars.BumpNone {
+		t.Errorf("bump without current: got %q", o.Bump)
+	}
+}
+
+func TestOutdated_WantedNamedPrerelease(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleNPM, "^1.4.0-beta.1", "1.4.2", outdatedVersions)
+	if o.Wanted != "1.5.0-beta.1" {
+		t.Errorf("wanted: got %q, want %q", o.Wanted, "1.5.0-beta.1")
+	}
+}
+
+func TestOutdated_NamesPrerelease(t *testing.T) {
+	cases := []struct {
+		style      vars.Style
+		constraint string
+		want       bool
+	}{
+		{vars.StyleNPM, "^1.2.0-beta.1", true},
+		{vars.StyleNPM, "1.x || >=2.0.0", false},
+		{vars.StylePy, ">=1.0rc1", true},
+		{vars.StyleComposer, "^7.0", false},
+		{vars.StyleDebian, "libc6 (>= 2.35~rc1)", true},
+		{vars.StyleDebian, "libc6 (<< 2.32)", false},
+		{vars.StyleAlpine, "openssl~3.0", false},
+		{vars.StyleConda, "numpy 1.19.*", false},
+	}
+	for _, c := range cases {
+		if got := namesPrerelease(c.style, c.constraint); got != c.want {
+			t.Errorf("namesPrerelease(%s, %q): got %v, want %v", c.style, c.constraint, got, c.want)
+		}
+	}
+}
+
+func TestOutdated_NoMatch(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleNPM, "^9.0.0", "1.0.0", outdatedVersions)
+	if o.Wanted != "" {
+		t.Errorf("wanted: got %q, want empty", o.Wanted)
+	}
+	if o.Bump != vars.BumpMajor {
+		t.Errorf("bump: got %q", o.Bump)
+	}
+}
+
+func TestOutdated_OnlyPrereleases(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleNPM, "*", "", []string{"1.0.0-alpha.1", "1.0.0-beta.1"})
+	if o.Latest != "1.0.0-beta.1" {
+		t.Errorf("latest: got %q, want %q", o.Latest, "1.0.0-beta.1")
+	}
+}
+
+func TestOutdated_MinorNotBreakingForPython(t *testing.T) {
+	o := AnalyzeOutdated(vars.StylePy, "~=2.0", "2.0.0", outdatedVersions)
+	if o.Bump != vars.BumpMinor {
+		t.Errorf("bump: got %q, want %q", o.Bump, vars.BumpMinor)
+	}
+	if o.Breaking {
+		t.Error("minor bump should not be breaking for python")
+	}
+}
+
+// ─── ClassifyBump ─────────────────────────────────────────────────────────────
+
+func TestClassifyBump(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     vars.Bump
+	}{
+		{"1.0.0", "2.0.0", vars.BumpMajor},
+		{"1.0.0", "1.1.0", vars.BumpMinor},
+		{"1.0.0", "1.0.1", vars.BumpPatch},
+		{"1.0.0.1", "1.0.0.2", vars.BumpPatch},
+		{"1.0.0-beta.1", "1.0.0", vars.BumpPrerelease},
+		{"1.0.0-beta.1", "1.0.0-beta.2", vars.BumpPrerelease},
+		{"1.0.0", "v1.0.0", vars.BumpNone},
+		{"2.0.0", "1.0.0", vars.BumpMajor},
+	}
+	for _, c := range cases {
+		if got := ClassifyBump(c.from, c.to); got != c.want {
+			t.Errorf("ClassifyBump(%q, %q): got %q, want %q", c.from, c.to, got, c.want)
+		}
+	}
+}
+
+// ─── IsBreaking ───────────────────────────────────────────────────────────────
+
+func TestIsBreaking(t *testing.T) {
+	cases := []struct {
+		style    vars.Style
+		from, to string
+		want     bool
+	}{
+		{vars.StyleNPM, "1.2.0", "1.9.0", false},
+		{vars.StyleNPM, "1.2.0", "2.0.0", true},
+		{vars.StyleNPM, "0.2.0", "0.3.0", true},
+		{vars.StyleNPM, "0.2.0", "0.2.5", false},
+		{vars.StyleRust, "0.0.3", "0.0.4", true},
+		{vars.StylePy, "0.2.0", "0.3.0", false},
+		{vars.StyleMaven, "1.0.0", "2.0.0", true},
+		{vars.StyleGo, "1.4.0", "1.5.0", false},
+	}
+	for _, c := range cases {
+		if got := IsBreaking(c.style, c.from, c.to); got != c.want {
+			t.Errorf("IsBreaking(%s, %q, %q): got %v, want %v", c.style, c.from, c.to, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// Lister fetches the published versions of a package by name, such as a
+// *registry.Client.
+type Lister interface {
+	Versions(ctx context.Context, style vars.Style, name string) ([]string, error)
+}
+
+// CandidateLister is a Lister that also reports yanked versions and
+// dist-tags, such as a *registry.Client.
+type CandidateLister interface {
+	Lister
+	Candidates(ctx context.Context, style vars.Style, name string) ([]vars.Candidate, error)
+}
+
+// AnalyzePackage is AnalyzeConstraint over the versions l lists for name.
+// When l is a CandidateLister, dist-tags resolve as in AnalyzeCandidates.
+func AnalyzePackage(ctx context.Context, l Lister, style vars.Style, name, constraint string) (vars.Analysis, error) {
+	if cl, ok := l.(CandidateLister); ok {
+		cands, err := cl.Candidates(ctx, style, name)
+		if err != nil {
+			return vars.Analysis{}, err
+		}
+		return AnalyzeCandidates(style, constraint, cands), nil
+	}
+	versions, err := l.Versions(ctx, style, name)
+	if err != nil {
+		return vars.Analysis{}, err
+	}
+	return AnalyzeConstraint(style, constraint, versions), nil
+}
+
+// AnalyzeOutdatedPackage is AnalyzeOutdated over the versions l lists for
+// name.
+func AnalyzeOutdatedPackage(ctx context.Context, l Lister, style vars.Style, name, constraint, current string) (vars.Outdated, error) {
+	versions, err := l.Versions(ctx, style, name)
+	if err != nil {
+		return vars.Outdated{}, err
+	}
+	return AnalyzeOutdated(style, constraint, current, versions), nil
+}
@@ -0,0 +1,45 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+type fakeLister map[string][]string
+
+func (f fakeLister) Versions(_ context.Context, _ vars.Style, name string) ([]string, error) {
+	v, ok := f[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return v, nil
+}
+
+// ─── AnalyzePackage ───────────────────────────────────────────────────────────
+
+func TestAnalyzePackage(t *testing.T) {
+	l := fakeLister{"demo": outdatedVersions}
+	a, err := AnalyzePackage(context.Background(), l, vars.StyleNPM, "demo", "~2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMatches(t, a, []string{"2.0.0"})
+
+	if _, err := AnalyzePackage(context.Background(), l, vars.StyleNPM, "missing", "*"); err == nil {
+		t.Error("expected the lister's error")
+	}
+}
+
+func TestAnalyzeOutdatedPackage(t *testing.T) {
+	l := fakeLister{"demo": outdatedVersions}
+	o, err := AnalyzeOutdatedPackage(context.Background(), l, vars.StyleNPM, "demo", "~1.4.0", "1.4.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Latest != "2.1.0" || o.Bump != vars.BumpMajor {
+		t.Errorf("got latest=%q bump=%q, want 2.1.0 major", o.Latest, o.Bump)
+	}
+}
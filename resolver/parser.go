@@ -1,41 +1,24 @@
 package resolver
 
 import (
-	"errors"
-
 	"github.com/rng70/versions/v2/parser"
 	"github.com/rng70/versions/v2/vars"
 )
 
 func AnalyzeConstraint(style vars.Style, constraint string, versions []string) vars.Analysis {
-	raw := constraint
-
-	var parsed [][]vars.Constraint
-	var err error
+	return AnalyzeConstraintWithTags(style, constraint, versions, nil)
+}
 
-	switch style {
-	case vars.StyleNPM:
-		parsed, err = parser.ParseNPM(constraint)
-	case vars.StylePy:
-		parsed, err = parser.ParsePython(constraint)
-	case vars.StyleNuGet:
-		parsed, err = parser.ParseNuGet(constraint)
-	case vars.StyleMaven:
-		parsed, err = parser.ParseMaven(constraint)
-	case vars.StyleRuby:
-		parsed, err = parser.ParseRuby(constraint)
-	case vars.StyleRust:
-		parsed, err = parser.ParseRust(constraint)
-	case vars.StyleGo:
-		parsed, err = parser.ParseGo(constraint)
-	default:
-		return vars.Analysis{Raw: raw, Parsed: nil, Matches: []string{}}
-	}
+// AnalyzeConstraintWithTags is AnalyzeConstraint with dist-tags such as
+// "latest" or "next" resolved through tags, which maps tag names to
+// versions, before matching.
+func AnalyzeConstraintWithTags(style vars.Style, constraint string, versions []string, tags map[string]string) vars.Analysis {
+	raw := constraint
 
+	// Unknown styles, unsupported sources (URLs, files) and malformed
+	// constraints all resolve to no matches.
+	parsed, err := parser.Parse(style, constraint)
 	if err != nil {
-		if errors.Is(err, vars.ErrUnsupportedSource) {
-			return vars.Analysis{Raw: raw, Parsed: nil, Matches: []string{}}
-		}
 		return vars.Analysis{Raw: raw, Parsed: nil, Matches: []string{}}
 	}
 
@@ -43,6 +26,23 @@ func AnalyzeConstraint(style vars.Style, constraint string, versions []string) v
 		return vars.Analysis{Raw: raw, Parsed: parsed, Matches: []string{}}
 	}
 
-	matches := parser.FilterMatches(parsed, versions)
+	parsed = parser.ResolveDistTags(parsed, tags)
+	matches := parser.FilterMatchesFor(style, parsed, versions)
 	return vars.Analysis{Raw: raw, Parsed: parsed, Matches: matches}
 }
+
+// AnalyzeCandidates is AnalyzeConstraint over registry candidates: yanked
+// versions are left out and dist-tags resolve through the candidates' Tags.
+func AnalyzeCandidates(style vars.Style, constraint string, cands []vars.Candidate) vars.Analysis {
+	versions := make([]string, 0, len(cands))
+	tags := map[string]string{}
+	for _, c := range cands {
+		for _, t := range c.Tags {
+			tags[t] = c.Version
+		}
+		if !c.Yanked {
+			versions = append(versions, c.Version)
+		}
+	}
+	return AnalyzeConstraintWithTags(style, constraint, versions, tags)
+}
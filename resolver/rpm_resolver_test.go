@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+var rpmVersions = []string{"2:1.8.0-1.el8", "2:1.8.0-3.el8_4", "2:1.8.0-3.el8_10", "1:2.0.0-1.el8", "2:1.8.1~rc1-1.el8", "2:1.8.1-1.el8"}
+
+func TestRPM_FixedIn(t *testing.T) {
+	// An OVAL definition fixed in 2:1.8.0-3.el8_4: installed versions
+	// below it are affected.
+	a := AnalyzeConstraint(vars.StyleRPM, "< 2:1.8.0-3.el8_4", rpmVersions)
+	assertParsedCount(t, a, 1)
+	assertMatches(t, a, []string{"2:1.8.0-1.el8", "1:2.0.0-1.el8"})
+}
+
+func TestRPM_Tilde(t *testing.T) {
+	a := AnalyzeConstraint(vars.StyleRPM, "foo >= 2:1.8.1", rpmVersions)
+	assertMatches(t, a, []string{"2:1.8.1-1.el8"})
+}
+
+func TestRPM_OutdatedWanted(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleRPM, "foo < 2:1.8.1", "2:1.8.0-1.el8", rpmVersions)
+	if o.Wanted != "2:1.8.0-3.el8_10" {
+		t.Errorf("wanted: got %q, want %q", o.Wanted, "2:1.8.0-3.el8_10")
+	}
+}
+
+func TestRPM_Explain(t *testing.T) {
+	e := Explain(vars.StyleRPM, "< 2:0.1", "1:9.0-1")
+	if !e.Satisfied {
+		t.Fatal("1:9.0-1 has a lower epoch and should satisfy < 2:0.1")
+	}
+	if c := e.Groups[0].Checks[0].Component; c != "epoch" {
+		t.Errorf("component: got %q, want %q", c, "epoch")
+	}
+}
+
+func TestRPM_OutdatedEpoch(t *testing.T) {
+	o := AnalyzeOutdated(vars.StyleRPM, ">= 1.0", "1.0-1", []string{"1.0-1", "1.1-1", "2:0.1-1"})
+	if o.Latest != "2:0.1-1" {
+		t.Errorf("latest: got %q, want %q", o.Latest, "2:0.1-1")
+	}
+	if o.Bump != vars.BumpMajor || !o.Breaking {
+		t.Errorf("epoch change: got bump=%q breaking=%v", o.Bump, o.Breaking)
+	}
+}
+
+func TestRPM_ClassifyBumpRelease(t *testing.T) {
+	if b := ClassifyBumpFor(vars.StyleRPM, "1.0-1.el8", "1.0-2.el8"); b != vars.BumpPatch {
+		t.Errorf("release change: got %q, want %q", b, vars.BumpPatch)
+	}
+}
@@ -0,0 +1,505 @@
+package rewrite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+var (
+	// ErrNotFound is returned when the file declares no version for the
+	// dependency, for example because it comes from git or its version is
+	// managed in another file.
+	ErrNotFound = errors.New("dependency not found")
+	// ErrUnknownFile is returned for file names no rewriter handles.
+	ErrUnknownFile = errors.New("unknown manifest file")
+)
+
+// Set replaces the constraint of dependency name in a manifest file and
+// returns the new content. The file type is chosen by the base name of
+// filename: package.json, requirements*.txt, Cargo.toml, pom.xml, or an
+// MSBuild file (.csproj, .fsproj, .vbproj, .props, .targets).
+//
+// Only the constraint text changes; whitespace, comments, quoting and key
+// order are kept. Every declaration of name in the file is rewritten: all
+// dependency sections of package.json and Cargo.toml, dependencyManagement
+// entries of a pom.xml, and so on. When a pom.xml or MSBuild version is a
+// ${property} or $(Property) defined in the same file, the property is
+// rewritten instead.
+func Set(filename string, data []byte, name, constraint string) ([]byte, error) {
+	_, fields, err := locate(filename, data, name)
+	if err != nil {
+		return nil, err
+	}
+	return apply(data, fields, func(string) (string, error) { return constraint, nil })
+}
+
+// Update rewrites the constraint of dependency name in a manifest file with
+// strategy s so that it admits version. See Set for the supported files and
+// Constraint for the strategies.
+func Update(filename string, data []byte, name, version string, s Strategy) ([]byte, error) {
+	style, fields, err := locate(filename, data, name)
+	if err != nil {
+		return nil, err
+	}
+	return apply(data, fields, func(old string) (string, error) {
+		c, err := Constraint(style, old, version, s)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+		return c, nil
+	})
+}
+
+// Style returns the constraint style of a manifest file Set and Update
+// handle.
+func Style(filename string) (vars.Style, bool) {
+	base := path.Base(filename)
+	switch ext := strings.ToLower(path.Ext(base)); {
+	case base == "package.json":
+		return vars.StyleNPM, true
+	case base == "Cargo.toml":
+		return vars.StyleRust, true
+	case base == "pom.xml" || ext == ".pom":
+		return vars.StyleMaven, true
+	case ext == ".csproj" || ext == ".fsproj" || ext == ".vbproj" || ext == ".props" || ext == ".targets":
+		return vars.StyleNuGet, true
+	case strings.HasPrefix(base, "requirements") && (ext == ".txt" || ext == ".in"):
+		return vars.StylePy, true
+	}
+	return "", false
+}
+
+// encoding tells how a constraint is escaped inside the file.
+type encoding int
+
+const (
+	encRaw encoding = iota
+	encJSON
+	encXML
+)
+
+// field is the position of one constraint in a file.
+type field struct {
+	start, end int
+	enc        encoding
+}
+
+func locate(filename string, data []byte, name string) (vars.Style, []field, error) {
+	style, ok := Style(filename)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s", ErrUnknownFile, path.Base(filename))
+	}
+	var (
+		fields []field
+		err    error
+	)
+	switch style {
+	case vars.StyleNPM:
+		fields, err = packageJSONFields(data, name)
+	case vars.StylePy:
+		fields = requirementsFields(data, name)
+	case vars.StyleRust:
+		fields = cargoFields(data, name)
+	case vars.StyleMaven:
+		fields, err = pomFields(data, name)
+	case vars.StyleNuGet:
+		fields = msbuildFields(data, name)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("%w: %s in %s", ErrNotFound, name, path.Base(filename))
+	}
+	return style, fields, nil
+}
+
+// apply replaces every field with the result of next, from the last field
+// to the first so that earlier offsets stay valid.
+func apply(data []byte, fields []field, next func(old string) (string, error)) ([]byte, error) {
+	sort.Slice(fields, func(i, j int) bool { return fields[i].start > fields[j].start })
+	out := append([]byte(nil), data...)
+	for i, f := range fields {
+		if i > 0 && f.start == fields[i-1].start {
+			continue
+		}
+		old, err := decode(f.enc, data[f.start:f.end])
+		if err != nil {
+			return nil, err
+		}
+		c, err := next(old)
+		if err != nil {
+			return nil, err
+		}
+		enc, err := encode(f.enc, c)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out[:f.start:f.start], append(enc, out[f.end:]...)...)
+	}
+	return out, nil
+}
+
+var xmlUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&amp;", "&")
+
+func decode(enc encoding, b []byte) (string, error) {
+	switch enc {
+	case encJSON:
+		var s string
+		err := json.Unmarshal(b, &s)
+		return s, err
+	case encXML:
+		return xmlUnescaper.Replace(string(b)), nil
+	default:
+		return string(b), nil
+	}
+}
+
+func encode(enc encoding, s string) ([]byte, error) {
+	switch enc {
+	case encJSON:
+		var buf bytes.Buffer
+		e := json.NewEncoder(&buf)
+		e.SetEscapeHTML(false)
+		if err := e.Encode(s); err != nil {
+			return nil, err
+		}
+		return bytes.TrimRight(buf.Bytes(), "\n"), nil
+	case encXML:
+		return []byte(strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;").Replace(s)), nil
+	default:
+		return []byte(s), nil
+	}
+}
+
+/* ------------------------- */
+/*        package.json       */
+/* ------------------------- */
+
+var npmSections = map[string]bool{
+	"dependencies": true, "devDependencies": true, "peerDependencies": true, "optionalDependencies": true,
+}
+
+func packageJSONFields(data []byte, name string) ([]field, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("package.json: expected an object")
+	}
+	var fields []field
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("package.json: %w", err)
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("package.json: %w", err)
+		}
+		if k, _ := key.(string); !npmSections[k] || len(raw) == 0 || raw[0] != '{' {
+			continue
+		}
+		base := int(dec.InputOffset()) - len(raw)
+		inner := json.NewDecoder(bytes.NewReader(raw))
+		inner.Token()
+		for inner.More() {
+			dep, err := inner.Token()
+			if err != nil {
+				return nil, fmt.Errorf("package.json: %w", err)
+			}
+			var v json.RawMessage
+			if err := inner.Decode(&v); err != nil {
+				return nil, fmt.Errorf("package.json: %w", err)
+			}
+			if dep == name && len(v) > 0 && v[0] == '"' {
+				end := base + int(inner.InputOffset())
+				fields = append(fields, field{end - len(v), end, encJSON})
+			}
+		}
+	}
+	return fields, nil
+}
+
+/* ------------------------- */
+/*      requirements.txt     */
+/* ------------------------- */
+
+var (
+	reRequirementName = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)(\s*\[[^\]]*\])?`)
+	rePyNameSep       = regexp.MustCompile(`[-_.]+`)
+)
+
+func pyName(s string) string { return rePyNameSep.ReplaceAllString(strings.ToLower(s), "-") }
+
+func requirementsFields(data []byte, name string) []field {
+	var fields []field
+	off := 0
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		start := off
+		off += len(line)
+		m := reRequirementName.FindStringSubmatchIndex(line)
+		if m == nil || pyName(line[m[2]:m[3]]) != pyName(name) {
+			continue
+		}
+		// The specifier runs to a marker, an option, a comment or a
+		// line continuation.
+		spec := line[m[1]:]
+		end := len(spec)
+		for _, stop := range []string{";", " --", "\t--", " #", "\t#", "\\", "\r", "\n"} {
+			if i := strings.Index(spec, stop); i >= 0 && i < end {
+				end = i
+			}
+		}
+		lead := len(spec[:end]) - len(strings.TrimLeft(spec[:end], " \t"))
+		trimmed := strings.TrimSpace(spec[:end])
+		s := start + m[1] + lead
+		fields = append(fields, field{s, s + len(trimmed), encRaw})
+	}
+	return fields
+}
+
+/* ------------------------- */
+/*         Cargo.toml        */
+/* ------------------------- */
+
+var (
+	reTOMLHeader  = regexp.MustCompile(`^\s*\[([^\[\]]+)\]\s*(#.*)?$`)
+	reTOMLKey     = regexp.MustCompile(`^\s*("[^"]*"|'[^']*'|[A-Za-z0-9_-]+)\s*=\s*`)
+	reTOMLString  = regexp.MustCompile(`^"([^"\\]*)"|^'([^']*)'`)
+	reInlineVer   = regexp.MustCompile(`(?:^|[{,\s])version\s*=\s*(?:"([^"\\]*)"|'([^']*)')`)
+	reInlinePkg   = regexp.MustCompile(`(?:^|[{,\s])package\s*=\s*(?:"([^"\\]*)"|'([^']*)')`)
+	cargoSections = map[string]bool{"dependencies": true, "dev-dependencies": true, "build-dependencies": true}
+)
+
+// stringSpan returns the span of the first non-empty capture of m.
+func stringSpan(m []int) (int, int) {
+	if m[2] >= 0 {
+		return m[2], m[3]
+	}
+	return m[4], m[5]
+}
+
+func cargoFields(data []byte, name string) []field {
+	var (
+		fields []field
+		off    int
+		// section is "deps" inside a dependency table, "dep" inside a
+		// [dependencies.<key>] table, "" elsewhere.
+		section string
+		key     string
+		pending *field
+		pkg     string
+	)
+	flush := func() {
+		if pending != nil && (key == name || pkg == name) {
+			fields = append(fields, *pending)
+		}
+		pending, pkg = nil, ""
+	}
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		start := off
+		off += len(line)
+		if m := reTOMLHeader.FindStringSubmatch(line); m != nil {
+			flush()
+			parts := tomlPath(m[1])
+			switch n := len(parts); {
+			case cargoSections[parts[n-1]]:
+				section = "deps"
+			case n >= 2 && cargoSections[parts[n-2]]:
+				section, key = "dep", parts[n-1]
+			default:
+				section = ""
+			}
+			continue
+		}
+		km := reTOMLKey.FindStringSubmatchIndex(line)
+		if km == nil || section == "" {
+			continue
+		}
+		k := strings.Trim(line[km[2]:km[3]], `"'`)
+		rest := line[km[1]:]
+		switch section {
+		case "dep":
+			if sm := reTOMLString.FindStringSubmatchIndex(rest); sm != nil {
+				s, e := stringSpan(sm)
+				switch k {
+				case "version":
+					pending = &field{start + km[1] + s, start + km[1] + e, encRaw}
+				case "package":
+					pkg = rest[s:e]
+				}
+			}
+		case "deps":
+			if sm := reTOMLString.FindStringSubmatchIndex(rest); sm != nil {
+				if k == name {
+					s, e := stringSpan(sm)
+					fields = append(fields, field{start + km[1] + s, start + km[1] + e, encRaw})
+				}
+				continue
+			}
+			if !strings.HasPrefix(rest, "{") {
+				continue
+			}
+			p := ""
+			if pm := reInlinePkg.FindStringSubmatchIndex(rest); pm != nil {
+				s, e := stringSpan(pm)
+				p = rest[s:e]
+			}
+			if vm := reInlineVer.FindStringSubmatchIndex(rest); vm != nil && (k == name || p == name) {
+				s, e := stringSpan(vm)
+				fields = append(fields, field{start + km[1] + s, start + km[1] + e, encRaw})
+			}
+		}
+	}
+	flush()
+	return fields
+}
+
+// tomlPath splits a dotted table name, honouring quoted keys.
+func tomlPath(s string) []string {
+	var parts []string
+	var cur strings.Builder
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			cur.WriteByte(c)
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '.':
+			parts = append(parts, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	return append(parts, strings.TrimSpace(cur.String()))
+}
+
+/* ------------------------- */
+/*      pom.xml / MSBuild    */
+/* ------------------------- */
+
+var (
+	reXMLComment   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	rePomDep       = regexp.MustCompile(`(?s)<dependency>(.*?)</dependency>`)
+	rePomGroup     = regexp.MustCompile(`<groupId>\s*([^<]*?)\s*</groupId>`)
+	rePomArtifact  = regexp.MustCompile(`<artifactId>\s*([^<]*?)\s*</artifactId>`)
+	rePomVersion   = regexp.MustCompile(`<version>\s*([^<]*?)\s*</version>`)
+	rePomProperty  = regexp.MustCompile(`^\$\{([^}]+)\}$`)
+	reMSBuildItem  = regexp.MustCompile(`<(PackageReference|PackageVersion|GlobalPackageReference)\b([^>]*?)(/?)>`)
+	reMSBuildName  = regexp.MustCompile(`\b(?:Include|Update)\s*=\s*"([^"]*)"`)
+	reMSBuildAttrs = []*regexp.Regexp{
+		regexp.MustCompile(`\bVersionOverride\s*=\s*"([^"]*)"`),
+		regexp.MustCompile(`\bVersion\s*=\s*"([^"]*)"`),
+	}
+	reMSBuildElems = []*regexp.Regexp{
+		regexp.MustCompile(`<VersionOverride>\s*([^<]*?)\s*</VersionOverride>`),
+		regexp.MustCompile(`<Version>\s*([^<]*?)\s*</Version>`),
+	}
+	reMSBuildProp = regexp.MustCompile(`^\$\(([^)]+)\)$`)
+)
+
+// maskComments blanks XML comments so that commented-out declarations are
+// not matched. Offsets are unchanged.
+func maskComments(data []byte) []byte {
+	return reXMLComment.ReplaceAllFunc(data, func(c []byte) []byte { return bytes.Repeat([]byte(" "), len(c)) })
+}
+
+// propertyField finds the element <name>value</name>; with fold, the
+// element name is matched case-insensitively as MSBuild does.
+func propertyField(masked []byte, name string, fold bool) (field, bool) {
+	expr := `<` + regexp.QuoteMeta(name) + `>\s*([^<]*?)\s*</` + regexp.QuoteMeta(name) + `>`
+	if fold {
+		expr = `(?i)` + expr
+	}
+	m := regexp.MustCompile(expr).FindSubmatchIndex(masked)
+	if m == nil {
+		return field{}, false
+	}
+	return field{m[2], m[3], encXML}, true
+}
+
+func pomFields(data []byte, name string) ([]field, error) {
+	masked := maskComments(data)
+	var fields []field
+	for _, m := range rePomDep.FindAllSubmatchIndex(masked, -1) {
+		body := masked[m[2]:m[3]]
+		g, a := rePomGroup.FindSubmatch(body), rePomArtifact.FindSubmatch(body)
+		if g == nil || a == nil || string(g[1])+":"+string(a[1]) != name {
+			continue
+		}
+		vm := rePomVersion.FindSubmatchIndex(body)
+		if vm == nil {
+			continue
+		}
+		f := field{m[2] + vm[2], m[2] + vm[3], encXML}
+		if pm := rePomProperty.FindSubmatch(masked[f.start:f.end]); pm != nil {
+			pf, ok := propertyField(masked, string(pm[1]), false)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s uses property %s, which pom.xml does not define", ErrNotFound, name, pm[1])
+			}
+			f = pf
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func msbuildFields(data []byte, name string) []field {
+	masked := maskComments(data)
+	var fields []field
+	for _, m := range reMSBuildItem.FindAllSubmatchIndex(masked, -1) {
+		attrs := masked[m[4]:m[5]]
+		nm := reMSBuildName.FindSubmatch(attrs)
+		if nm == nil || !strings.EqualFold(strings.TrimSpace(string(nm[1])), name) {
+			continue
+		}
+		f, ok := msbuildVersion(masked, m, attrs)
+		if !ok {
+			continue
+		}
+		if pm := reMSBuildProp.FindSubmatch(masked[f.start:f.end]); pm != nil {
+			if pf, ok := propertyField(masked, string(pm[1]), true); ok {
+				f = pf
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// msbuildVersion finds the version of the item whose start tag m matched:
+// a VersionOverride or Version attribute, else child element.
+func msbuildVersion(masked []byte, m []int, attrs []byte) (field, bool) {
+	for _, re := range reMSBuildAttrs {
+		if vm := re.FindSubmatchIndex(attrs); vm != nil {
+			return field{m[4] + vm[2], m[4] + vm[3], encXML}, true
+		}
+	}
+	if m[7] > m[6] {
+		return field{}, false // self-closing
+	}
+	closing := []byte("</" + string(masked[m[2]:m[3]]) + ">")
+	end := bytes.Index(masked[m[1]:], closing)
+	if end < 0 {
+		return field{}, false
+	}
+	body := masked[m[1] : m[1]+end]
+	for _, re := range reMSBuildElems {
+		if vm := re.FindSubmatchIndex(body); vm != nil {
+			return field{m[1] + vm[2], m[1] + vm[3], encXML}, true
+		}
+	}
+	return field{}, false
+}
@@ -0,0 +1,200 @@
+package rewrite
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func rewriteCheck(t *testing.T, filename, in, want string, fn func([]byte) ([]byte, error)) {
+	t.Helper()
+	got, err := fn([]byte(in))
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", filename, err)
+	}
+	if string(got) != want {
+		t.Errorf("%s:\ngot:\n%s\nwant:\n%s", filename, got, want)
+	}
+}
+
+// ─── package.json ─────────────────────────────────────────────────────────────
+
+const packageJSONIn = `{
+  "name": "app",
+  "version": "1.0.0",
+  "dependencies": {
+    "react":   "^18.2.0",
+    "lodash": "~4.17.20"
+  },
+  "devDependencies": {"react": ">=18.2.0 <19.0.0", "vite": "github:vitejs/vite"},
+  "scripts": {"react": "not a dependency"}
+}
+`
+
+func TestUpdate_PackageJSON(t *testing.T) {
+	want := strings.NewReplacer(`"^18.2.0"`, `"^18.3.1"`, `">=18.2.0 <19.0.0"`, `">=18.3.1 <19.0.0"`).Replace(packageJSONIn)
+	rewriteCheck(t, "package.json", packageJSONIn, want, func(b []byte) ([]byte, error) {
+		return Update("package.json", b, "react", "18.3.1", StrategyBump)
+	})
+
+	want = strings.Replace(packageJSONIn, `"~4.17.20"`, `"4.17.21"`, 1)
+	rewriteCheck(t, "package.json", packageJSONIn, want, func(b []byte) ([]byte, error) {
+		return Update("package.json", b, "lodash", "4.17.21", StrategyPin)
+	})
+
+	if _, err := Update("package.json", []byte(packageJSONIn), "vite", "5.0.0", StrategyBump); err == nil {
+		t.Error("expected error for a git source")
+	}
+	if _, err := Set("package.json", []byte(packageJSONIn), "left-pad", "1.0.0"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// ─── requirements.txt ─────────────────────────────────────────────────────────
+
+const requirementsIn = `# pinned for prod
+Django>=4.2,<5   # LTS
+requests[socks] ==2.31.0 ; python_version >= "3.8"
+flask
+numpy==1.26.4 \
+    --hash=sha256:abc
+`
+
+func TestUpdate_Requirements(t *testing.T) {
+	want := strings.Replace(requirementsIn, "Django>=4.2,<5   # LTS", "Django>=4.2,<6   # LTS", 1)
+	rewriteCheck(t, "requirements.txt", requirementsIn, want, func(b []byte) ([]byte, error) {
+		return Update("requirements.txt", b, "django", "5.0.1", StrategyWiden)
+	})
+
+	want = strings.Replace(requirementsIn, "==2.31.0 ;", "==2.32.3 ;", 1)
+	rewriteCheck(t, "requirements.txt", requirementsIn, want, func(b []byte) ([]byte, error) {
+		return Update("requirements.txt", b, "Requests", "2.32.3", StrategyBump)
+	})
+
+	want = strings.Replace(requirementsIn, "numpy==1.26.4 \\", "numpy==2.0.0 \\", 1)
+	rewriteCheck(t, "requirements.txt", requirementsIn, want, func(b []byte) ([]byte, error) {
+		return Update("requirements.txt", b, "numpy", "2.0.0", StrategyPin)
+	})
+
+	want = strings.Replace(requirementsIn, "flask\n", "flask>=3.0\n", 1)
+	rewriteCheck(t, "requirements.txt", requirementsIn, want, func(b []byte) ([]byte, error) {
+		return Set("requirements.txt", b, "Flask", ">=3.0")
+	})
+}
+
+// ─── Cargo.toml ───────────────────────────────────────────────────────────────
+
+const cargoIn = `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+tokio = "1.35"   # runtime
+rand_old = { package = "rand", version = '0.7' }
+local = { path = "../local" }
+
+[target.'cfg(unix)'.dependencies]
+tokio = { version = "~1.35.1" }
+
+[dev-dependencies.rand]
+version = "0.8"
+features = ["small_rng"]
+`
+
+func TestUpdate_Cargo(t *testing.T) {
+	want := strings.NewReplacer(`tokio = "1.35"`, `tokio = "1.36"`, `"~1.35.1"`, `"~1.36.0"`).Replace(cargoIn)
+	rewriteCheck(t, "Cargo.toml", cargoIn, want, func(b []byte) ([]byte, error) {
+		return Update("Cargo.toml", b, "tokio", "1.36.0", StrategyBump)
+	})
+
+	want = strings.NewReplacer(`'0.7'`, `'0.8'`, `version = "0.8"`, `version = "0.8.5"`).Replace(cargoIn)
+	rewriteCheck(t, "Cargo.toml", cargoIn, want, func(b []byte) ([]byte, error) {
+		return Update("Cargo.toml", b, "rand", "0.8.5", StrategyBump)
+	})
+
+	if _, err := Set("Cargo.toml", []byte(cargoIn), "local", "1.0"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("path dependency: expected ErrNotFound, got %v", err)
+	}
+}
+
+// ─── pom.xml ──────────────────────────────────────────────────────────────────
+
+const pomIn = `<project>
+  <properties>
+    <guava.version>32.1.3-jre</guava.version>
+  </properties>
+  <dependencies>
+    <!-- <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>1.7.0</version></dependency> -->
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>${guava.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>[2.0,3.0)</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+
+func TestUpdate_Pom(t *testing.T) {
+	want := strings.Replace(pomIn, "32.1.3-jre</guava", "33.0.0-jre</guava", 1)
+	rewriteCheck(t, "pom.xml", pomIn, want, func(b []byte) ([]byte, error) {
+		return Update("pom.xml", b, "com.google.guava:guava", "33.0.0-jre", StrategyPin)
+	})
+
+	want = strings.Replace(pomIn, "[2.0,3.0)", "[2.0,4.0)", 1)
+	rewriteCheck(t, "pom.xml", pomIn, want, func(b []byte) ([]byte, error) {
+		return Update("pom.xml", b, "org.slf4j:slf4j-api", "3.1.0", StrategyWiden)
+	})
+
+	undefined := strings.Replace(pomIn, "<guava.version>", "<other.version>", 1)
+	undefined = strings.Replace(undefined, "</guava.version>", "</other.version>", 1)
+	if _, err := Set("pom.xml", []byte(undefined), "com.google.guava:guava", "33.0"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("undefined property: expected ErrNotFound, got %v", err)
+	}
+}
+
+// ─── .csproj ──────────────────────────────────────────────────────────────────
+
+const csprojIn = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <SerilogVersion>3.1.1</SerilogVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.1" />
+    <PackageReference Include="Serilog" Version="$(SerilogVersion)" />
+    <PackageReference Include="Dapper">
+      <Version>[2.1.24, 3.0)</Version>
+    </PackageReference>
+    <PackageReference Include="xunit" />
+  </ItemGroup>
+</Project>
+`
+
+func TestUpdate_MSBuild(t *testing.T) {
+	want := strings.Replace(csprojIn, `Version="13.0.1"`, `Version="13.0.3"`, 1)
+	rewriteCheck(t, "app.csproj", csprojIn, want, func(b []byte) ([]byte, error) {
+		return Update("src/app.csproj", b, "newtonsoft.json", "13.0.3", StrategyBump)
+	})
+
+	want = strings.Replace(csprojIn, ">3.1.1<", ">4.0.0<", 1)
+	rewriteCheck(t, "app.csproj", csprojIn, want, func(b []byte) ([]byte, error) {
+		return Update("app.csproj", b, "Serilog", "4.0.0", StrategyBump)
+	})
+
+	want = strings.Replace(csprojIn, "[2.1.24, 3.0)", "[2.1.24, 4.0)", 1)
+	rewriteCheck(t, "app.csproj", csprojIn, want, func(b []byte) ([]byte, error) {
+		return Update("app.csproj", b, "Dapper", "3.0.1", StrategyWiden)
+	})
+
+	if _, err := Set("app.csproj", []byte(csprojIn), "xunit", "2.6.0"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("centrally managed: expected ErrNotFound, got %v", err)
+	}
+	if _, err := Set("build.gradle", nil, "x", "1"); !errors.Is(err, ErrUnknownFile) {
+		t.Errorf("expected ErrUnknownFile, got %v", err)
+	}
+}
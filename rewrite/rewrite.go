@@ -0,0 +1,511 @@
+// Package rewrite edits dependency constraints: it computes a new
+// constraint that admits a chosen version while keeping the operator style
+// of the old one, and writes it back into manifest files without touching
+// their formatting.
+package rewrite
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// Strategy selects how a constraint is moved to a new version.
+type Strategy string
+
+const (
+	// StrategyPin replaces the constraint with an exact version: "1.4.0"
+	// for npm, "==1.4.0" for Python, "[1.4.0]" for NuGet, ...
+	StrategyPin Strategy = "pin"
+	// StrategyBump raises the lower bound and keeps the operator, so that
+	// "^1.2.0" becomes "^1.4.0" and ">=1.2,<2" becomes ">=1.4,<2".
+	StrategyBump Strategy = "bump"
+	// StrategyWiden keeps the lower bound and lifts the upper bound to
+	// admit the major of the new version, so that "^1.2.0" becomes
+	// "^1.2.0 || ^2.0.0" and "[1.2,2.0)" becomes "[1.2,3.0)".
+	StrategyWiden Strategy = "widen"
+)
+
+var (
+	ErrUnknownStrategy = errors.New("unknown strategy")
+	// ErrUnsatisfied is returned when the strategy cannot produce a
+	// constraint admitting the version, for example when bumping past an
+	// explicit upper bound or widening an exact pin.
+	ErrUnsatisfied = errors.New("constraint does not admit version")
+)
+
+// Constraint returns constraint rewritten with strategy s so that it admits
+// version. The result keeps the number of components, wildcards and "v"
+// prefix of the version it replaces where that still admits version and,
+// for a bump, still moves the bound.
+//
+// Widening a constraint that already admits version returns it unchanged.
+func Constraint(style vars.Style, constraint, version string, s Strategy) (string, error) {
+	if _, err := parser.Parse(style, constraint); err != nil {
+		return "", err
+	}
+	c := strings.TrimSpace(constraint)
+	unbounded := c == "" || c == "*" || c == "latest" || (style == vars.StyleNPM && parser.IsDistTag(c))
+
+	var out string
+	switch s {
+	case StrategyPin:
+		out = pin(style, c, version)
+	case StrategyBump:
+		if unbounded {
+			out = minimum(style, version)
+		} else {
+			out = bump(style, c, version)
+		}
+	case StrategyWiden:
+		if unbounded || admits(style, c, version) {
+			return constraint, nil
+		}
+		out = widen(style, c, version)
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownStrategy, s)
+	}
+	if !admits(style, out, version) {
+		return "", fmt.Errorf("%w: %s %s of %q gives %q, which excludes %s", ErrUnsatisfied, s, style, constraint, out, version)
+	}
+	return out, nil
+}
+
+/* ------------------------- */
+/*        strategies         */
+/* ------------------------- */
+
+func pin(style vars.Style, c, version string) string {
+	toks := scan(style, c)
+	if len(toks) == 1 && toks[0].op == "" && toks[0].role == roleExact && c == toks[0].text(c) {
+		return version
+	}
+	switch style {
+	case vars.StylePy:
+		return "==" + version
+	case vars.StyleRust:
+		return "=" + version
+	case vars.StyleMaven, vars.StyleGradle, vars.StyleNuGet:
+		return "[" + version + "]"
+	default:
+		return version
+	}
+}
+
+func minimum(style vars.Style, version string) string {
+	switch style {
+	case vars.StyleRuby:
+		return ">= " + version
+	case vars.StyleMaven, vars.StyleGradle, vars.StyleNuGet:
+		return "[" + version + ",)"
+	default:
+		return ">=" + version
+	}
+}
+
+func bump(style vars.Style, c, version string) string {
+	toks := scan(style, c)
+	lo := pick(c, toks, version)
+	if lo < 0 {
+		return c
+	}
+	t := toks[lo]
+	out := t.replace(c, format(t.text(c), version))
+	if !admits(style, out, version) {
+		out = t.replace(c, full(t.text(c), version))
+	}
+	// Kept at its precision the bound may not move at all, as "1.0" to
+	// 1.0.190; it is then written in full, unless that lowers the ceiling
+	// as it would for "~=1.2", "~1" or a wildcard.
+	if out == c && strings.Contains(t.text(c), ".") && full(t.text(c), version) != t.text(c) {
+		if wider := t.replace(c, full(t.text(c), version)); ceiling(style, wider) == ceiling(style, c) {
+			out = wider
+		}
+	}
+	return out
+}
+
+func widen(style vars.Style, c, version string) string {
+	toks := scan(style, c)
+	lo := pick(c, toks, version)
+	if lo < 0 {
+		return c
+	}
+	for _, t := range toks {
+		if t.role != roleUpper || t.group != toks[lo].group || t.start < toks[lo].start {
+			continue
+		}
+		if t.inclusive {
+			return t.replace(c, full(t.text(c), version))
+		}
+		return t.replace(c, nextMajor(t.text(c), version))
+	}
+
+	t := toks[lo]
+	if !t.implicit {
+		return c
+	}
+	if style == vars.StyleNPM {
+		return c + " || " + t.op + format(t.text(c), floorOf(t.op, version))
+	}
+	low := zeroWild.Replace(t.text(c))
+	high := nextMajor(low, version)
+	var rng string
+	switch style {
+	case vars.StyleRuby:
+		rng = ">= " + low + ", < " + high
+	case vars.StylePy:
+		rng = ">=" + low + ",<" + high
+	default:
+		rng = ">=" + low + ", <" + high
+	}
+	return c[:t.opStart] + rng + c[t.end:]
+}
+
+// pick returns the index of the lower or exact bound to move: the highest
+// one not above version, else the first.
+func pick(c string, toks []token, version string) int {
+	target := canonicalized.NewVersion(version)
+	best, first := -1, -1
+	var bestV canonicalized.Version
+	for i, t := range toks {
+		if t.role != roleLower && t.role != roleExact {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		v := canonicalized.NewVersion(zeroWild.Replace(t.text(c)))
+		if v.Compare(&target) > 0 {
+			continue
+		}
+		if best < 0 || v.Compare(&bestV) > 0 {
+			best, bestV = i, v
+		}
+	}
+	if best < 0 {
+		return first
+	}
+	return best
+}
+
+// admits reports whether c admits version under style.
+func admits(style vars.Style, c, version string) bool {
+	if style == vars.StyleNPM {
+		return admitsNPM(c, version)
+	}
+	return admitsParsed(style, normalize(style, c), version)
+}
+
+// normalize spells out what bare versions mean: "compatible" in Cargo and
+// "at least" in NuGet, whereas the parsers read them as exact.
+func normalize(style vars.Style, c string) string {
+	switch style {
+	case vars.StyleRust:
+		parts := strings.Split(c, ",")
+		for i, p := range parts {
+			if p = strings.TrimSpace(p); reBare.MatchString(p) {
+				parts[i] = "^" + p
+			}
+		}
+		c = strings.Join(parts, ",")
+	case vars.StyleNuGet:
+		if c = strings.TrimSpace(c); reBare.MatchString(c) {
+			c = "[" + c + ", )"
+		}
+	}
+	return c
+}
+
+// ceiling returns the upper bounds of c under style, one list per group,
+// for comparing two constraints; "" when c does not parse.
+func ceiling(style vars.Style, c string) string {
+	groups, err := parser.Parse(style, normalize(style, c))
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, ands := range groups {
+		for _, k := range ands {
+			if strings.HasPrefix(k.Op, "<") {
+				b.WriteString(k.Op + k.Ver + " ")
+			}
+		}
+		b.WriteString("|")
+	}
+	return b.String()
+}
+
+// admitsNPM checks each comparator of a space-separated npm range on its
+// own, as ParseNPM does not keep every comparator of such a range.
+func admitsNPM(c, version string) bool {
+	if strings.TrimSpace(c) == "" {
+		return admitsParsed(vars.StyleNPM, c, version)
+	}
+	for _, block := range strings.Split(c, "||") {
+		if admitsNPMBlock(strings.TrimSpace(block), version) {
+			return true
+		}
+	}
+	return false
+}
+
+func admitsNPMBlock(block, version string) bool {
+	if vars.ReDashRange.MatchString(block) {
+		return admitsParsed(vars.StyleNPM, block, version)
+	}
+	var comparators []string
+	op := ""
+	for _, f := range strings.Fields(block) {
+		if strings.Trim(f, "^~<>=") == "" {
+			op += f
+			continue
+		}
+		comparators = append(comparators, op+f)
+		op = ""
+	}
+	for _, cmp := range comparators {
+		if !admitsParsed(vars.StyleNPM, cmp, version) {
+			return false
+		}
+	}
+	return len(comparators) > 0
+}
+
+func admitsParsed(style vars.Style, c, version string) bool {
+	groups, err := parser.Parse(style, c)
+	if err != nil {
+		return false
+	}
+	return len(parser.FilterMatchesFor(style, groups, []string{version})) == 1
+}
+
+/* ------------------------- */
+/*      version formatting   */
+/* ------------------------- */
+
+var (
+	reBare    = regexp.MustCompile(`^v?[0-9]+(\.[0-9]+)*(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+	reNumeric = regexp.MustCompile(`^(v?)([0-9xX*]+(?:\.[0-9xX*]+)*)(.*)$`)
+	zeroWild  = strings.NewReplacer("x", "0", "X", "0", "*", "0")
+)
+
+// format writes version in the shape of old: same number of components,
+// wildcards kept, "v" prefix kept. Versions with a pre-release or build
+// suffix on either side are written in full.
+func format(old, version string) string {
+	om, vm := reNumeric.FindStringSubmatch(old), reNumeric.FindStringSubmatch(version)
+	if om == nil || vm == nil || om[3] != "" || vm[3] != "" {
+		return full(old, version)
+	}
+	oc, vc := strings.Split(om[2], "."), strings.Split(vm[2], ".")
+	for i := range oc {
+		switch {
+		case isWildcard(oc[i]):
+		case i < len(vc):
+			oc[i] = vc[i]
+		default:
+			oc[i] = "0"
+		}
+	}
+	return om[1] + strings.Join(oc, ".")
+}
+
+// full writes version completely, with the "v" prefix of old.
+func full(old, version string) string {
+	version = strings.TrimPrefix(version, "v")
+	if strings.HasPrefix(old, "v") {
+		return "v" + version
+	}
+	return version
+}
+
+// nextMajor returns the major after version's in the shape of old: "2.0"
+// and 3.1.0 give "4.0".
+func nextMajor(old, version string) string {
+	return format(zeroWild.Replace(old), bumpMajor(version, 1))
+}
+
+// floorOf returns the lower bound of the op range that admits version: "~"
+// keeps its major and minor, "^" keeps up to the first non-zero of major and
+// minor, anything else the major. ^ and 0.3.1 give 0.3.0; ~ and 1.4.3 give
+// 1.4.0.
+func floorOf(op, version string) string {
+	vm := reNumeric.FindStringSubmatch(zeroWild.Replace(version))
+	if vm == nil {
+		return version
+	}
+	nums := append(strings.Split(vm[2], "."), "0", "0")[:3]
+	keep := 1
+	switch {
+	case op == "~":
+		keep = 2
+	case op == "^" && nums[0] == "0" && nums[1] == "0":
+		keep = 3
+	case op == "^" && nums[0] == "0":
+		keep = 2
+	}
+	for i := keep; i < 3; i++ {
+		nums[i] = "0"
+	}
+	return strings.Join(nums, ".")
+}
+
+func bumpMajor(version string, by int) string {
+	vm := reNumeric.FindStringSubmatch(version)
+	if vm == nil {
+		return version
+	}
+	major, _ := strconv.Atoi(strings.Split(vm[2], ".")[0])
+	return strconv.Itoa(major+by) + ".0.0"
+}
+
+func isWildcard(s string) bool { return s == "x" || s == "X" || s == "*" }
+
+/* ------------------------- */
+/*         scanning          */
+/* ------------------------- */
+
+type role int
+
+const (
+	roleLower role = iota
+	roleExact
+	roleUpper
+	roleExclude
+)
+
+// token is one version inside a constraint string.
+type token struct {
+	opStart, start, end int
+	op                  string
+	role                role
+	// implicit marks a lower bound that carries its own upper bound: caret,
+	// tilde, compatible release, wildcards and bare Cargo versions.
+	implicit bool
+	// inclusive marks an upper bound that admits its own version.
+	inclusive bool
+	// group counts OR alternatives and bracketed ranges.
+	group int
+}
+
+func (t token) text(s string) string { return s[t.start:t.end] }
+
+func (t token) replace(s, with string) string { return s[:t.start] + with + s[t.end:] }
+
+var reToken = regexp.MustCompile(`^v?[0-9]+(\.([0-9]+|[xX*]))*(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?|^[xX*](\.[xX*])*`)
+
+// scan finds the versions of constraint s and the part each one plays.
+func scan(style vars.Style, s string) []token {
+	ranged := style == vars.StyleMaven || style == vars.StyleGradle || style == vars.StyleNuGet
+	var (
+		toks       []token
+		group      int
+		inRange    bool
+		afterComma bool
+		rangeFirst int
+	)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case strings.HasPrefix(s[i:], "||"):
+			group++
+			i += 2
+			continue
+		case ranged && (c == '[' || c == '('):
+			if len(toks) > 0 {
+				group++
+			}
+			inRange, afterComma, rangeFirst = true, false, len(toks)
+		case ranged && inRange && c == ',':
+			afterComma = true
+		case ranged && inRange && (c == ']' || c == ')'):
+			inRange = false
+			rng := toks[rangeFirst:]
+			if !afterComma && len(rng) == 1 {
+				rng[0].role = roleExact
+			} else if len(rng) > 0 && rng[len(rng)-1].role == roleUpper {
+				rng[len(rng)-1].inclusive = c == ']'
+			}
+		case boundary(s, i):
+			if m := reToken.FindString(s[i:]); m != "" {
+				t := token{start: i, end: i + len(m), group: group}
+				t.opStart, t.op = operator(s, i)
+				if inRange {
+					t.role = roleLower
+					if afterComma {
+						t.role = roleUpper
+					}
+				} else {
+					classify(style, s, &t)
+				}
+				if strings.ContainsAny(m, "xX*") && t.role != roleUpper {
+					t.implicit = true
+				}
+				toks = append(toks, t)
+				i = t.end
+				continue
+			}
+		}
+		i++
+	}
+	return toks
+}
+
+func classify(style vars.Style, s string, t *token) {
+	switch t.op {
+	case "<", "<=":
+		t.role, t.inclusive = roleUpper, t.op == "<="
+	case "!=":
+		t.role = roleExclude
+	case ">", ">=":
+		t.role = roleLower
+	case "^", "~", "~>", "~=":
+		t.role, t.implicit = roleLower, true
+	case "":
+		switch {
+		case style == vars.StyleNPM && strings.HasSuffix(strings.TrimRight(s[:t.start], " "), " -"):
+			t.role, t.inclusive = roleUpper, true
+		case style == vars.StyleRust:
+			t.role, t.implicit = roleLower, true
+		case style == vars.StyleNuGet:
+			t.role = roleLower
+		default:
+			t.role = roleExact
+		}
+	default:
+		t.role = roleExact
+	}
+}
+
+// boundary reports whether a version may start at s[i].
+func boundary(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	p := s[i-1]
+	return !(p >= '0' && p <= '9' || p >= 'a' && p <= 'z' || p >= 'A' && p <= 'Z' || p == '.' || p == '-' || p == '+')
+}
+
+// operator returns the comparison operator written before s[i] and where it
+// starts.
+func operator(s string, i int) (int, string) {
+	j := i
+	for j > 0 && s[j-1] == ' ' {
+		j--
+	}
+	k := j
+	for k > 0 && strings.IndexByte("^~<>=!", s[k-1]) >= 0 {
+		k--
+	}
+	if k == j {
+		return i, ""
+	}
+	return k, s[k:j]
+}
@@ -0,0 +1,95 @@
+package rewrite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ─── Constraint ───────────────────────────────────────────────────────────────
+
+func TestConstraint(t *testing.T) {
+	cases := []struct {
+		style      vars.Style
+		constraint string
+		version    string
+		strategy   Strategy
+		want       string
+	}{
+		// Pin
+		{vars.StyleNPM, "^1.2.0", "1.4.0", StrategyPin, "1.4.0"},
+		{vars.StylePy, ">=1.2,<2", "1.4.0", StrategyPin, "==1.4.0"},
+		{vars.StyleRust, "1.2", "1.4.0", StrategyPin, "=1.4.0"},
+		{vars.StyleNuGet, "[1.2, 2)", "1.4.0", StrategyPin, "[1.4.0]"},
+		{vars.StyleMaven, "1.2.0", "1.4.0", StrategyPin, "1.4.0"},
+		{vars.StyleMaven, "[1.2,2.0)", "1.4.0", StrategyPin, "[1.4.0]"},
+
+		// Bump
+		{vars.StyleNPM, "^1.2.0", "1.4.0", StrategyBump, "^1.4.0"},
+		{vars.StyleNPM, "~1.2", "1.4.3", StrategyBump, "~1.4"},
+		{vars.StyleNPM, ">=1.2.0 <2.0.0", "1.4.0", StrategyBump, ">=1.4.0 <2.0.0"},
+		{vars.StyleNPM, "1.x", "2.3.0", StrategyBump, "2.x"},
+		{vars.StyleNPM, "^1.2.0 || ^3.0.0", "1.4.0", StrategyBump, "^1.4.0 || ^3.0.0"},
+		{vars.StyleNPM, "^1.2.0", "1.4.0-rc.1", StrategyBump, "^1.4.0-rc.1"},
+		{vars.StyleNPM, "", "1.4.0", StrategyBump, ">=1.4.0"},
+		{vars.StylePy, ">=1.2,<2", "1.4.0", StrategyBump, ">=1.4,<2"},
+		{vars.StylePy, "~=1.2", "1.4.0", StrategyBump, "~=1.4"},
+		{vars.StylePy, "==1.2", "1.2.5", StrategyBump, "==1.2.5"},
+		{vars.StyleRust, "1.2", "1.4.0", StrategyBump, "1.4"},
+		{vars.StyleRuby, "~> 1.2", "1.4.0", StrategyBump, "~> 1.4"},
+		{vars.StyleMaven, "[1.2,2.0)", "1.4.0", StrategyBump, "[1.4,2.0)"},
+		{vars.StyleNuGet, "13.0.1", "13.0.3", StrategyBump, "13.0.3"},
+		{vars.StyleGo, ">=v1.2.0", "v1.4.0", StrategyBump, ">=v1.4.0"},
+		{vars.StyleRust, "1.0", "1.0.190", StrategyBump, "1.0.190"},
+		{vars.StyleRust, "^0.1", "0.1.5", StrategyBump, "^0.1.5"},
+		{vars.StyleNPM, "~1.2", "1.2.9", StrategyBump, "~1.2.9"},
+		{vars.StyleNPM, "^1.2", "1.2.9", StrategyBump, "^1.2.9"},
+		{vars.StyleNPM, ">=1.2 <2.0.0", "1.2.9", StrategyBump, ">=1.2.9 <2.0.0"},
+		{vars.StyleMaven, "[1.2,2.0)", "1.2.5", StrategyBump, "[1.2.5,2.0)"},
+		{vars.StyleNPM, "~1", "1.0.5", StrategyBump, "~1"},
+		{vars.StylePy, "~=1.2", "1.2.5", StrategyBump, "~=1.2"},
+		{vars.StyleNPM, "1.x", "1.5.0", StrategyBump, "1.x"},
+		{vars.StyleNPM, "^1.2.0", "1.2.0", StrategyBump, "^1.2.0"},
+
+		// Widen
+		{vars.StyleNPM, "^1.2.0", "2.1.0", StrategyWiden, "^1.2.0 || ^2.0.0"},
+		{vars.StyleNPM, ">=1.2.0 <2.0.0", "3.1.0", StrategyWiden, ">=1.2.0 <4.0.0"},
+		{vars.StyleNPM, "1.2.0 - 2.0.0", "3.1.0", StrategyWiden, "1.2.0 - 3.1.0"},
+		{vars.StyleNPM, "^1.2.0", "1.4.0", StrategyWiden, "^1.2.0"},
+		{vars.StyleNPM, "^0.2.0", "0.3.1", StrategyWiden, "^0.2.0 || ^0.3.0"},
+		{vars.StyleNPM, "^0.0.2", "0.0.4", StrategyWiden, "^0.0.2 || ^0.0.4"},
+		{vars.StyleNPM, "~1.2", "1.4.3", StrategyWiden, "~1.2 || ~1.4"},
+		{vars.StylePy, ">=1.2,<2", "2.1", StrategyWiden, ">=1.2,<3"},
+		{vars.StylePy, "~=1.2", "2.1.0", StrategyWiden, ">=1.2,<3.0"},
+		{vars.StyleRust, "1.2", "2.0.1", StrategyWiden, ">=1.2, <3.0"},
+		{vars.StyleRuby, "~> 1.2", "2.0.0", StrategyWiden, ">= 1.2, < 3.0"},
+		{vars.StyleMaven, "[1.2,2.0)", "2.5", StrategyWiden, "[1.2,3.0)"},
+		{vars.StyleNuGet, "13.0.1", "14.0.0", StrategyWiden, "13.0.1"},
+	}
+	for _, c := range cases {
+		got, err := Constraint(c.style, c.constraint, c.version, c.strategy)
+		if err != nil {
+			t.Errorf("%s %s %q to %s: unexpected error: %v", c.strategy, c.style, c.constraint, c.version, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s %s %q to %s: got %q, want %q", c.strategy, c.style, c.constraint, c.version, got, c.want)
+		}
+	}
+}
+
+func TestConstraint_Errors(t *testing.T) {
+	if _, err := Constraint(vars.StyleNPM, ">=1.2.0 <2.0.0", "2.1.0", StrategyBump); !errors.Is(err, ErrUnsatisfied) {
+		t.Errorf("bump past upper bound: got %v", err)
+	}
+	if _, err := Constraint(vars.StylePy, "==1.2.0", "2.0.0", StrategyWiden); !errors.Is(err, ErrUnsatisfied) {
+		t.Errorf("widen exact pin: got %v", err)
+	}
+	if _, err := Constraint(vars.StyleNPM, "^1.2.0", "1.4.0", "latest"); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("unknown strategy: got %v", err)
+	}
+	if _, err := Constraint(vars.StyleNPM, "github:user/repo", "1.4.0", StrategyBump); !errors.Is(err, vars.ErrUnsupportedSource) {
+		t.Errorf("source: got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Explanation tells why version solving failed.
+type Explanation struct {
+	// Steps derive the failure one conclusion at a time; the last step
+	// concludes that version solving failed.
+	Steps []Step `json:"steps"`
+	// Conflict is a minimal set of requirements that cannot all hold:
+	// leaving out any one of them makes the rest solvable.
+	Conflict []Fact `json:"conflict"`
+}
+
+// Step is one line of a derivation: Conclusion follows from Because.
+type Step struct {
+	Because    []Premise `json:"because"`
+	Conclusion string    `json:"conclusion"`
+}
+
+// Premise is a reason used by a step: a requirement, or the conclusion of an
+// earlier step.
+type Premise struct {
+	Text string `json:"text"`
+	// Step is the 1-based number of the step that concluded Text, or zero
+	// for a requirement.
+	Step int `json:"step,omitempty"`
+}
+
+// Fact is a requirement the failure depends on: versions of Package require
+// Name at Constraint. Package is empty for the root requirements.
+type Fact struct {
+	Package string `json:"package,omitempty"`
+	// Versions describes the versions of Package that carry the
+	// requirement; empty when all of them do.
+	Versions   string `json:"versions,omitempty"`
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+
+	in map[string]bool // versions of Package carrying the requirement
+}
+
+func (f Fact) String() string {
+	from := "root"
+	if f.Package != "" {
+		from = f.Package
+		if f.Versions != "" {
+			from += " " + f.Versions
+		}
+	}
+	req := f.Name
+	if f.Constraint != "" {
+		req += " " + f.Constraint
+	}
+	return from + " requires " + req
+}
+
+// Explain derives the failure step by step from the requirements it rests
+// on, and narrows those requirements to a minimal conflicting set by
+// solving again without each of them in turn.
+func (f *Failure) Explain() Explanation {
+	var e Explanation
+	steps := map[*incompatibility]int{}
+	var visit func(*incompatibility)
+	visit = func(inc *incompatibility) {
+		if inc.cause != CauseConflict || steps[inc] != 0 {
+			return
+		}
+		visit(inc.left)
+		visit(inc.right)
+		st := Step{Conclusion: conclusion(inc)}
+		for _, p := range []*incompatibility{inc.left, inc.right} {
+			switch p.cause {
+			case CauseConflict:
+				st.Because = append(st.Because, Premise{Text: conclusion(p), Step: steps[p]})
+			case CauseRoot:
+			default:
+				st.Because = append(st.Because, Premise{Text: p.String()})
+			}
+		}
+		e.Steps = append(e.Steps, st)
+		steps[inc] = len(e.Steps)
+	}
+	visit(f.incompatibility)
+	if len(e.Steps) == 0 {
+		e.Steps = []Step{{Because: []Premise{{Text: f.incompatibility.String()}}, Conclusion: conclusion(f.incompatibility)}}
+	}
+	e.Conflict = f.minimalConflict(f.facts())
+	return e
+}
+
+// String renders the explanation as text:
+//
+//	Because b depends on c <2.0.0 and a <=1.1.0 depends on c ^2.0.0, a <=1.1.0 is incompatible with b.
+//	And because root depends on a ^1.0.0, b is forbidden.
+//	And because root depends on b 3.0.0, version solving failed.
+//
+//	Conflicting requirements:
+//	  root requires a ^1.0.0
+//	  root requires b 3.0.0
+//	  a <=1.1.0 requires c ^2.0.0
+//	  b requires c <2.0.0
+func (e Explanation) String() string {
+	// Steps used again later than the next line get a number.
+	numbered := map[int]bool{}
+	for i, st := range e.Steps {
+		for _, p := range st.Because {
+			if p.Step != 0 && p.Step != i {
+				numbered[p.Step] = true
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for i, st := range e.Steps {
+		var reasons []string
+		chained := false
+		for _, p := range st.Because {
+			switch {
+			case p.Step != 0 && p.Step == i && !chained:
+				chained = true
+			case p.Step != 0:
+				reasons = append(reasons, fmt.Sprintf("%s (%d)", p.Text, p.Step))
+			default:
+				reasons = append(reasons, p.Text)
+			}
+		}
+		switch {
+		case chained && len(reasons) > 0:
+			sb.WriteString("And because " + strings.Join(reasons, " and ") + ", ")
+		case chained:
+			sb.WriteString("So, ")
+		case len(reasons) > 0:
+			sb.WriteString("Because " + strings.Join(reasons, " and ") + ", ")
+		}
+		sb.WriteString(st.Conclusion + ".")
+		if numbered[i+1] {
+			fmt.Fprintf(&sb, " (%d)", i+1)
+		}
+		sb.WriteString("\n")
+	}
+	if len(e.Conflict) > 0 {
+		sb.WriteString("\nConflicting requirements:\n")
+		for _, fact := range e.Conflict {
+			sb.WriteString("  " + fact.String() + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func conclusion(inc *incompatibility) string {
+	if terminal(inc) {
+		return "version solving failed"
+	}
+	return inc.String()
+}
+
+// facts collects the dependency incompatibilities the failure was derived
+// from, root requirements first, then by package.
+func (f *Failure) facts() []Fact {
+	var out []Fact
+	seen := map[*incompatibility]bool{}
+	var walk func(*incompatibility)
+	walk = func(inc *incompatibility) {
+		if inc == nil || seen[inc] {
+			return
+		}
+		seen[inc] = true
+		if inc.cause == CauseConflict {
+			walk(inc.left)
+			walk(inc.right)
+			return
+		}
+		if inc.cause != CauseDependency || len(inc.terms) != 2 {
+			return
+		}
+		from, on := inc.terms[0], inc.terms[1]
+		if !from.positive() {
+			from, on = on, from
+		}
+		fact := Fact{Name: on.pkg.name, Constraint: on.label, in: map[string]bool{}}
+		if from.pkg.name != "" {
+			fact.Package = from.pkg.name
+			fact.Versions = strings.TrimPrefix(from.describe(from.set), " ")
+			for i, v := range from.pkg.versions {
+				if from.set.has(i) {
+					fact.in[v] = true
+				}
+			}
+		}
+		out = append(out, fact)
+	}
+	walk(f.incompatibility)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Package != out[j].Package {
+			return out[i].Package < out[j].Package
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// minimalConflict drops every fact without which solving still fails.
+func (f *Failure) minimalConflict(facts []Fact) []Fact {
+	if f.src == nil {
+		return facts
+	}
+	for i := 0; i < len(facts); {
+		rest := append(append([]Fact(nil), facts[:i]...), facts[i+1:]...)
+		if f.fails(rest) {
+			facts = rest
+		} else {
+			i++
+		}
+	}
+	return facts
+}
+
+// fails reports whether solving fails when the only requirements are facts.
+func (f *Failure) fails(facts []Fact) bool {
+	var root []Requirement
+	for _, fact := range facts {
+		if fact.Package == "" {
+			root = append(root, Requirement{Name: fact.Name, Constraint: fact.Constraint})
+		}
+	}
+	_, err := Solve(f.style, restricted{f.src, facts}, root)
+	var failure *Failure
+	return errors.As(err, &failure)
+}
+
+// restricted is a Source whose versions depend only on the given facts.
+type restricted struct {
+	Source
+	facts []Fact
+}
+
+func (r restricted) Dependencies(name, version string) ([]Requirement, error) {
+	reqs, err := r.Source.Dependencies(name, version)
+	if err != nil {
+		return nil, err
+	}
+	var out []Requirement
+	for _, req := range reqs {
+		for _, fact := range r.facts {
+			if fact.Package == name && fact.Name == req.Name && fact.in[version] {
+				out = append(out, req)
+				break
+			}
+		}
+	}
+	return out, nil
+}
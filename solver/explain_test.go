@@ -0,0 +1,135 @@
+package solver
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func failure(t *testing.T, reg Registry, root map[string]string) *Failure {
+	t.Helper()
+	_, err := solve(t, reg, root)
+	var f *Failure
+	if !errors.As(err, &f) {
+		t.Fatalf("expected *Failure, got %v", err)
+	}
+	return f
+}
+
+func conflictStrings(e Explanation) []string {
+	out := make([]string, len(e.Conflict))
+	for i, f := range e.Conflict {
+		out[i] = f.String()
+	}
+	return out
+}
+
+// ─── Explain ──────────────────────────────────────────────────────────────────
+
+func TestExplain_SharedDependency(t *testing.T) {
+	reg := Registry{}
+	reg.Add("a", "1.0.0", map[string]string{"c": "^2.0.0"})
+	reg.Add("a", "1.1.0", map[string]string{"c": "^2.0.0"})
+	reg.Add("a", "2.0.0", map[string]string{"c": "<2.0.0"})
+	reg.Add("b", "3.0.0", map[string]string{"c": "<2.0.0", "d": "^1.0.0"})
+	reg.Add("c", "1.5.0", nil)
+	reg.Add("c", "2.1.0", nil)
+	reg.Add("d", "1.0.0", nil)
+
+	e := failure(t, reg, map[string]string{"a": "^1.0.0", "b": "3.0.0", "d": "*"}).Explain()
+
+	first := e.Steps[0]
+	if first.Conclusion != "a <=1.1.0 is incompatible with b" {
+		t.Errorf("first conclusion: got %q", first.Conclusion)
+	}
+	if len(first.Because) != 2 || first.Because[0].Step != 0 || first.Because[1].Step != 0 {
+		t.Errorf("first premises: got %+v", first.Because)
+	}
+	if last := e.Steps[len(e.Steps)-1]; last.Conclusion != "version solving failed" {
+		t.Errorf("last conclusion: got %q", last.Conclusion)
+	}
+
+	want := []string{
+		"root requires a ^1.0.0",
+		"root requires b 3.0.0",
+		"a <=1.1.0 requires c ^2.0.0",
+		"b requires c <2.0.0",
+	}
+	if got := conflictStrings(e); !reflect.DeepEqual(got, want) {
+		t.Errorf("conflict: got %q, want %q", got, want)
+	}
+
+	text := e.String()
+	for _, line := range []string{
+		"b depends on c <2.0.0 and a <=1.1.0 depends on c ^2.0.0",
+		", a <=1.1.0 is incompatible with b.\n",
+		"And because root depends on a ^1.0.0, b is forbidden.\n",
+		"version solving failed.\n",
+		"\nConflicting requirements:\n  root requires a ^1.0.0\n",
+	} {
+		if !strings.Contains(text, line) {
+			t.Errorf("text missing %q:\n%s", line, text)
+		}
+	}
+	if strings.Contains(text, "d ^1.0.0") {
+		t.Errorf("unrelated requirement in explanation:\n%s", text)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `{"package":"b","name":"c","constraint":"\u003c2.0.0"}`) {
+		t.Errorf("json: got %s", data)
+	}
+}
+
+func TestExplain_Chain(t *testing.T) {
+	// The linear error reporting example of the PubGrub documentation.
+	reg := Registry{}
+	reg.Add("foo", "1.0.0", map[string]string{"bar": "^2.0.0"})
+	reg.Add("bar", "2.0.0", map[string]string{"baz": "^3.0.0"})
+	reg.Add("baz", "1.0.0", nil)
+	reg.Add("baz", "3.0.0", nil)
+
+	e := failure(t, reg, map[string]string{"foo": "^1.0.0", "baz": "^1.0.0"}).Explain()
+
+	if len(e.Steps) < 2 {
+		t.Fatalf("expected a chain of steps, got %+v", e.Steps)
+	}
+	if !strings.Contains(e.String(), "And because ") {
+		t.Errorf("expected chained steps:\n%s", e)
+	}
+	want := []string{
+		"root requires baz ^1.0.0",
+		"root requires foo ^1.0.0",
+		"bar requires baz ^3.0.0",
+		"foo requires bar ^2.0.0",
+	}
+	if got := conflictStrings(e); !reflect.DeepEqual(got, want) {
+		t.Errorf("conflict: got %q, want %q", got, want)
+	}
+}
+
+func TestExplain_MinimalConflict(t *testing.T) {
+	// x is ruled out twice over; one reason is enough.
+	reg := Registry{}
+	reg.Add("x", "1.0.0", map[string]string{"y": "^2.0.0", "z": "^5.0.0"})
+	reg.Add("y", "1.0.0", nil)
+	reg.Add("z", "1.0.0", nil)
+
+	e := failure(t, reg, map[string]string{"x": "1.0.0", "y": "^1.0.0"}).Explain()
+	for _, f := range e.Conflict {
+		if f.Name == "z" {
+			if len(e.Conflict) != 2 {
+				t.Errorf("conflict: got %q", conflictStrings(e))
+			}
+			return
+		}
+	}
+	if got := conflictStrings(e); len(got) != 3 {
+		t.Errorf("conflict: got %q", got)
+	}
+}
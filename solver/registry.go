@@ -0,0 +1,56 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Registry is an in-memory Source. Its JSON form maps package names to
+// versions to requirements:
+//
+//	{"a": {"1.0.0": {"b": "^2.0"}, "1.1.0": {}}, "b": {"2.1.0": null}}
+type Registry map[string]map[string]map[string]string
+
+// ReadRegistry reads a Registry from its JSON form.
+func ReadRegistry(r io.Reader) (Registry, error) {
+	reg := Registry{}
+	if err := json.NewDecoder(r).Decode(&reg); err != nil {
+		return nil, fmt.Errorf("registry: %w", err)
+	}
+	return reg, nil
+}
+
+// Add publishes one version of a package with its requirements, given as
+// name to constraint.
+func (r Registry) Add(name, version string, deps map[string]string) {
+	if r[name] == nil {
+		r[name] = map[string]map[string]string{}
+	}
+	r[name][version] = deps
+}
+
+// Versions returns the published versions of a package, in no particular
+// order.
+func (r Registry) Versions(name string) ([]string, error) {
+	out := make([]string, 0, len(r[name]))
+	for v := range r[name] {
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// Dependencies returns the requirements of one version, sorted by name.
+func (r Registry) Dependencies(name, version string) ([]Requirement, error) {
+	deps, ok := r[name][version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnknownVersion, name, version)
+	}
+	out := make([]Requirement, 0, len(deps))
+	for n, c := range deps {
+		out = append(out, Requirement{Name: n, Constraint: c})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
@@ -0,0 +1,559 @@
+// Package solver resolves a whole dependency graph with the PubGrub
+// algorithm: given root requirements and a Source of package versions and
+// their dependencies, it finds one version of every needed package such that
+// all constraints hold, or explains why none exists.
+//
+// Constraints are read with the parser of the chosen style and matched
+// against the versions the Source lists, so every term the solver reasons
+// about is a finite set of known versions.
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rng70/versions/v2/canonicalized"
+	"github.com/rng70/versions/v2/parser"
+	"github.com/rng70/versions/v2/vars"
+)
+
+// ErrUnknownVersion is returned by a Source asked for the dependencies of a
+// version it does not list.
+var ErrUnknownVersion = errors.New("unknown version")
+
+// Requirement is a dependency on a range of another package.
+type Requirement struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+}
+
+// Source supplies packages to the solver.
+type Source interface {
+	// Versions returns the published versions of a package. An unknown
+	// package has none.
+	Versions(name string) ([]string, error)
+	// Dependencies returns the requirements of one version of a package.
+	Dependencies(name, version string) ([]Requirement, error)
+}
+
+// Solution maps every selected package to its version.
+type Solution map[string]string
+
+// Failure is the error returned when the requirements cannot be satisfied.
+// Explain turns it into a step-by-step derivation.
+type Failure struct {
+	incompatibility *incompatibility
+
+	style vars.Style
+	src   Source
+	root  []Requirement
+}
+
+func (f *Failure) Error() string {
+	var facts []string
+	seen := map[*incompatibility]bool{}
+	var walk func(*incompatibility)
+	walk = func(inc *incompatibility) {
+		if inc == nil || seen[inc] {
+			return
+		}
+		seen[inc] = true
+		if inc.cause != CauseConflict {
+			if inc.cause != CauseRoot {
+				facts = append(facts, inc.String())
+			}
+			return
+		}
+		walk(inc.left)
+		walk(inc.right)
+	}
+	walk(f.incompatibility)
+	return "version solving failed: " + strings.Join(facts, "; ")
+}
+
+// Solve finds versions for requirements and everything they depend on.
+// Among the versions a package may take, the highest is tried first, and
+// packages with the fewest candidates are decided first.
+//
+// When no solution exists the error is a *Failure; errors of the Source and
+// unparsable constraints are returned as they are.
+func Solve(style vars.Style, src Source, requirements []Requirement) (Solution, error) {
+	s := &solver{
+		style:     style,
+		src:       src,
+		root:      requirements,
+		pkgs:      map[string]*universe{},
+		incompats: map[string][]*incompatibility{},
+		byPkg:     map[string][]int{},
+		decisions: map[string]int{},
+		deps:      map[string][]Requirement{},
+		seen:      map[string]bool{},
+	}
+	root := &universe{name: "", versions: []string{""}}
+	s.pkgs[""] = root
+	notRoot := emptySet(1)
+	notRoot.add(root.absent())
+	s.addIncompatibility(newIncompatibility(CauseRoot, term{pkg: root, set: notRoot}))
+
+	next := ""
+	for {
+		if err := s.propagate(next); err != nil {
+			return nil, err
+		}
+		pkg, done, err := s.decide()
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			break
+		}
+		next = pkg
+	}
+
+	out := Solution{}
+	for name, i := range s.decisions {
+		if name != "" {
+			out[name] = s.pkgs[name].versions[i]
+		}
+	}
+	return out, nil
+}
+
+// assignment is one step of the partial solution: a decision selecting a
+// version, or a term derived from an incompatibility.
+type assignment struct {
+	term
+	level    int
+	decision bool
+	cause    *incompatibility
+}
+
+type solver struct {
+	style vars.Style
+	src   Source
+	root  []Requirement
+
+	pkgs        map[string]*universe
+	incompats   map[string][]*incompatibility
+	assignments []assignment
+	byPkg       map[string][]int // assignment indexes per package
+	decisions   map[string]int   // selected version index per package
+	deps        map[string][]Requirement
+	seen        map[string]bool // dependency incompatibilities already added
+}
+
+/* ------------------------- */
+/*       partial solution    */
+/* ------------------------- */
+
+func (s *solver) accumulated(pkg string) vset {
+	u := s.pkgs[pkg]
+	acc := fullSet(len(u.versions))
+	for _, i := range s.byPkg[pkg] {
+		acc = acc.intersect(s.assignments[i].set)
+	}
+	return acc
+}
+
+func (s *solver) assign(a assignment) {
+	s.byPkg[a.pkg.name] = append(s.byPkg[a.pkg.name], len(s.assignments))
+	s.assignments = append(s.assignments, a)
+}
+
+func (s *solver) backtrack(level int) {
+	kept := s.assignments[:0]
+	s.byPkg = map[string][]int{}
+	for _, a := range s.assignments {
+		if a.level > level {
+			if a.decision {
+				delete(s.decisions, a.pkg.name)
+			}
+			continue
+		}
+		s.byPkg[a.pkg.name] = append(s.byPkg[a.pkg.name], len(kept))
+		kept = append(kept, a)
+	}
+	s.assignments = kept
+}
+
+func (s *solver) addIncompatibility(inc *incompatibility) {
+	for _, t := range inc.terms {
+		s.incompats[t.pkg.name] = append(s.incompats[t.pkg.name], inc)
+	}
+}
+
+/* ------------------------- */
+/*      unit propagation     */
+/* ------------------------- */
+
+type relation int
+
+const (
+	relSatisfied relation = iota
+	relAlmostSatisfied
+	relContradicted
+	relInconclusive
+)
+
+// relation tells how the partial solution relates to inc. For an almost
+// satisfied incompatibility it also returns the one term left undecided.
+func (s *solver) relation(inc *incompatibility) (relation, term) {
+	var open *term
+	for i, t := range inc.terms {
+		acc := s.accumulated(t.pkg.name)
+		switch {
+		case acc.subset(t.set):
+		case acc.disjoint(t.set):
+			return relContradicted, term{}
+		case open != nil:
+			return relInconclusive, term{}
+		default:
+			open = &inc.terms[i]
+		}
+	}
+	if open == nil {
+		return relSatisfied, term{}
+	}
+	return relAlmostSatisfied, *open
+}
+
+func (s *solver) propagate(pkg string) error {
+	changed := []string{pkg}
+	for len(changed) > 0 {
+		name := changed[len(changed)-1]
+		changed = changed[:len(changed)-1]
+		incs := s.incompats[name]
+		for i := len(incs) - 1; i >= 0; i-- {
+			rel, t := s.relation(incs[i])
+			if rel == relSatisfied {
+				learned, err := s.resolve(incs[i])
+				if err != nil {
+					return err
+				}
+				if rel, t = s.relation(learned); rel != relAlmostSatisfied {
+					return fmt.Errorf("solver: learned incompatibility %s is not almost satisfied", learned)
+				}
+				s.derive(t, learned)
+				changed = []string{t.pkg.name}
+				break
+			}
+			if rel == relAlmostSatisfied {
+				s.derive(t, incs[i])
+				changed = append(changed, t.pkg.name)
+			}
+		}
+	}
+	return nil
+}
+
+// derive records that t cannot hold, because of cause.
+func (s *solver) derive(t term, cause *incompatibility) {
+	s.assign(assignment{term: t.negate(), level: len(s.decisions), cause: cause})
+}
+
+/* ------------------------- */
+/*    conflict resolution    */
+/* ------------------------- */
+
+// resolve derives, from an incompatibility the partial solution satisfies,
+// the root cause of the conflict and backjumps to where it is almost
+// satisfied. It returns a *Failure when the root cause rules out the root.
+func (s *solver) resolve(inc *incompatibility) (*incompatibility, error) {
+	learned := false
+	for {
+		if terminal(inc) {
+			return nil, &Failure{incompatibility: inc, style: s.style, src: s.src, root: s.root}
+		}
+
+		satIdx := -1
+		var satTerm term
+		sats := make([]int, len(inc.terms))
+		for i, t := range inc.terms {
+			// A term that always holds is satisfied from the start: -1.
+			sats[i] = s.satisfier(t, nil, len(s.assignments))
+			if sats[i] > satIdx {
+				satIdx, satTerm = sats[i], t
+			}
+		}
+		sat := s.assignments[satIdx]
+
+		previous := 1
+		for i, t := range inc.terms {
+			if t.pkg != satTerm.pkg && sats[i] >= 0 {
+				previous = max(previous, s.assignments[sats[i]].level)
+			}
+		}
+		if !sat.set.subset(satTerm.set) {
+			if j := s.satisfier(satTerm, sat.set, satIdx); j >= 0 {
+				previous = max(previous, s.assignments[j].level)
+			}
+		}
+
+		if sat.decision || previous != sat.level {
+			if learned {
+				s.addIncompatibility(inc)
+			}
+			s.backtrack(previous)
+			return inc, nil
+		}
+
+		var terms []term
+		for _, t := range inc.terms {
+			if t.pkg != satTerm.pkg {
+				terms = append(terms, t)
+			}
+		}
+		for _, t := range sat.cause.terms {
+			if t.pkg != satTerm.pkg {
+				terms = append(terms, t)
+			}
+		}
+		if !sat.set.subset(satTerm.set) {
+			n := len(satTerm.pkg.versions)
+			terms = append(terms, term{pkg: satTerm.pkg, set: sat.set.complement(n).union(satTerm.set)})
+		}
+		next := newIncompatibility(CauseConflict, terms...)
+		next.left, next.right = inc, sat.cause
+		inc, learned = next, true
+	}
+}
+
+// satisfier returns the index of the earliest assignment before limit after
+// which the assignments of t's package, intersected with start (nil for
+// none), imply t. A term that always holds has no satisfier: -1.
+func (s *solver) satisfier(t term, start vset, limit int) int {
+	acc := start
+	if acc == nil {
+		acc = fullSet(len(t.pkg.versions))
+		if acc.subset(t.set) {
+			return -1
+		}
+	}
+	for _, i := range s.byPkg[t.pkg.name] {
+		if i >= limit {
+			break
+		}
+		acc = acc.intersect(s.assignments[i].set)
+		if acc.subset(t.set) {
+			return i
+		}
+	}
+	return -1
+}
+
+// terminal reports whether inc rules out any solution: apart from terms
+// that always hold, it has no terms or only says the root cannot be
+// selected.
+func terminal(inc *incompatibility) bool {
+	var terms []term
+	for _, t := range inc.terms {
+		if !t.set.equal(fullSet(len(t.pkg.versions))) {
+			terms = append(terms, t)
+		}
+	}
+	return len(terms) == 0 || len(terms) == 1 && terms[0].pkg.name == "" && terms[0].positive()
+}
+
+/* ------------------------- */
+/*         decisions         */
+/* ------------------------- */
+
+// decide selects a version for the undecided required package with the
+// fewest candidates and adds its dependencies. It reports done when every
+// required package has a version.
+func (s *solver) decide() (string, bool, error) {
+	var (
+		pick  string
+		count int
+		found bool
+	)
+	for name := range s.byPkg {
+		if _, ok := s.decisions[name]; ok {
+			continue
+		}
+		acc := s.accumulated(name)
+		if acc.has(s.pkgs[name].absent()) {
+			continue
+		}
+		if c := acc.count(); !found || c < count || c == count && name < pick {
+			pick, count, found = name, c, true
+		}
+	}
+	if !found {
+		return "", true, nil
+	}
+	u := s.pkgs[pick]
+	acc := s.accumulated(pick)
+
+	v := -1
+	for i := len(u.versions) - 1; i >= 0; i-- {
+		if acc.has(i) {
+			v = i
+			break
+		}
+	}
+	if v < 0 {
+		s.addIncompatibility(newIncompatibility(CauseNoVersions, term{pkg: u, set: acc}))
+		return pick, false, nil
+	}
+
+	incs, err := s.dependencyIncompatibilities(u, v)
+	if err != nil {
+		return "", false, err
+	}
+	conflict := false
+	for _, inc := range incs {
+		if s.satisfiedWith(inc, u.name) {
+			conflict = true
+		}
+	}
+	if !conflict {
+		one := emptySet(len(u.versions))
+		one.add(v)
+		s.decisions[pick] = v
+		s.assign(assignment{term: term{pkg: u, set: one}, level: len(s.decisions), decision: true})
+	}
+	return pick, false, nil
+}
+
+// satisfiedWith reports whether every term of inc not about pkg is already
+// satisfied, so that selecting pkg would satisfy inc.
+func (s *solver) satisfiedWith(inc *incompatibility, pkg string) bool {
+	for _, t := range inc.terms {
+		if t.pkg.name != pkg && !s.accumulated(t.pkg.name).subset(t.set) {
+			return false
+		}
+	}
+	return true
+}
+
+// dependencyIncompatibilities adds "u at v depends on d" for each
+// requirement of version v. The depending side covers the contiguous run of
+// versions around v with the same requirement, which keeps explanations
+// short: "a >=1.0.0 <=1.4.0 depends on c ^2.0".
+func (s *solver) dependencyIncompatibilities(u *universe, v int) ([]*incompatibility, error) {
+	reqs, err := s.dependencies(u, v)
+	if err != nil {
+		return nil, err
+	}
+	var out []*incompatibility
+	for _, r := range reqs {
+		d, err := s.universe(r.Name)
+		if err != nil {
+			return nil, err
+		}
+		allowed, err := s.allowed(d, r.Constraint)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", u.name, u.versions[v], err)
+		}
+
+		lo, hi := v, v
+		for u.name != "" && lo > 0 && s.sameRequirement(u, lo-1, r) {
+			lo--
+		}
+		for u.name != "" && hi < len(u.versions)-1 && s.sameRequirement(u, hi+1, r) {
+			hi++
+		}
+		key := fmt.Sprintf("%s\x00%d\x00%d\x00%s\x00%s", u.name, lo, hi, r.Name, r.Constraint)
+		if s.seen[key] {
+			continue
+		}
+		s.seen[key] = true
+
+		run := emptySet(len(u.versions))
+		for i := lo; i <= hi; i++ {
+			run.add(i)
+		}
+		dep := term{pkg: d, set: allowed, label: strings.TrimSpace(r.Constraint)}
+		inc := newIncompatibility(CauseDependency, term{pkg: u, set: run}, dep.negate())
+		s.addIncompatibility(inc)
+		out = append(out, inc)
+	}
+	return out, nil
+}
+
+func (s *solver) sameRequirement(u *universe, i int, r Requirement) bool {
+	reqs, err := s.dependencies(u, i)
+	if err != nil {
+		return false
+	}
+	for _, o := range reqs {
+		if o.Name == r.Name {
+			return strings.TrimSpace(o.Constraint) == strings.TrimSpace(r.Constraint)
+		}
+	}
+	return false
+}
+
+func (s *solver) dependencies(u *universe, i int) ([]Requirement, error) {
+	if u.name == "" {
+		return s.root, nil
+	}
+	key := u.name + "@" + u.versions[i]
+	if reqs, ok := s.deps[key]; ok {
+		return reqs, nil
+	}
+	reqs, err := s.src.Dependencies(u.name, u.versions[i])
+	if err != nil {
+		return nil, err
+	}
+	s.deps[key] = reqs
+	return reqs, nil
+}
+
+// universe loads the versions of a package, sorted ascending.
+func (s *solver) universe(name string) (*universe, error) {
+	if u, ok := s.pkgs[name]; ok {
+		return u, nil
+	}
+	vs, err := s.src.Versions(name)
+	if err != nil {
+		return nil, err
+	}
+	parsed := make([]canonicalized.Version, 0, len(vs))
+	seen := map[string]bool{}
+	for _, v := range vs {
+		if !seen[v] {
+			seen[v] = true
+			parsed = append(parsed, canonicalized.NewVersion(v))
+		}
+	}
+	sort.SliceStable(parsed, func(i, j int) bool { return parsed[i].Compare(&parsed[j]) < 0 })
+	u := &universe{name: name, versions: make([]string, len(parsed))}
+	for i := range parsed {
+		u.versions[i] = parsed[i].Original
+	}
+	if parser.Comparator(s.style) != nil {
+		u.versions = parser.SortVersionsFor(s.style, u.versions, false)
+	}
+	s.pkgs[name] = u
+	return u, nil
+}
+
+// allowed returns the versions of u matching constraint. An empty
+// constraint allows every version.
+func (s *solver) allowed(u *universe, constraint string) (vset, error) {
+	out := emptySet(len(u.versions))
+	if strings.TrimSpace(constraint) == "" {
+		for i := range u.versions {
+			out.add(i)
+		}
+		return out, nil
+	}
+	groups, err := parser.Parse(s.style, constraint)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %w", u.name, constraint, err)
+	}
+	matches := map[string]bool{}
+	for _, m := range parser.FilterMatchesFor(s.style, groups, u.versions) {
+		matches[m] = true
+	}
+	for i, v := range u.versions {
+		if matches[v] {
+			out.add(i)
+		}
+	}
+	return out, nil
+}
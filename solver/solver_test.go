@@ -0,0 +1,220 @@
+package solver
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rng70/versions/v2/vars"
+)
+
+func solve(t *testing.T, reg Registry, root map[string]string) (Solution, error) {
+	t.Helper()
+	var reqs []Requirement
+	for n, c := range root {
+		reqs = append(reqs, Requirement{Name: n, Constraint: c})
+	}
+	return Solve(vars.StyleNPM, reg, reqs)
+}
+
+// ─── Solve ────────────────────────────────────────────────────────────────────
+
+func TestSolve_NoConflicts(t *testing.T) {
+	reg := Registry{}
+	reg.Add("a", "1.0.0", map[string]string{"shared": ">=2.0.0"})
+	reg.Add("b", "1.0.0", map[string]string{"shared": "<4.0.0"})
+	reg.Add("shared", "2.0.0", nil)
+	reg.Add("shared", "3.0.0", nil)
+	reg.Add("shared", "3.6.9", nil)
+	reg.Add("shared", "4.0.0", nil)
+	reg.Add("shared", "5.0.0", nil)
+
+	root := map[string]string{"a": "^1.0.0", "b": "^1.0.0"}
+	got, err := solve(t, reg, root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	consistent(t, reg, root, got)
+	want := Solution{"a": "1.0.0", "b": "1.0.0", "shared": "3.6.9"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// consistent checks that sol satisfies root and every dependency of the
+// versions it selects.
+func consistent(t *testing.T, reg Registry, root map[string]string, sol Solution) {
+	t.Helper()
+	check := func(from, name, constraint string) {
+		v, ok := sol[name]
+		if !ok {
+			t.Errorf("%s needs %s %s, which is not selected", from, name, constraint)
+			return
+		}
+		if !admits(name, constraint, v) {
+			t.Errorf("%s needs %s %s, selected %s", from, name, constraint, v)
+		}
+	}
+	for n, c := range root {
+		check("root", n, c)
+	}
+	for n, v := range sol {
+		for dep, c := range reg[n][v] {
+			check(n+" "+v, dep, c)
+		}
+	}
+}
+
+func admits(name, constraint, version string) bool {
+	s := &solver{style: vars.StyleNPM}
+	u := &universe{name: name, versions: []string{version}}
+	set, err := s.allowed(u, constraint)
+	return err == nil && set.has(0)
+}
+
+// The scenarios below are the worked examples of the PubGrub documentation.
+
+func TestSolve_AvoidsConflictDuringDecision(t *testing.T) {
+	reg := Registry{}
+	reg.Add("foo", "1.0.0", nil)
+	reg.Add("foo", "1.1.0", map[string]string{"bar": "^2.0.0"})
+	reg.Add("bar", "1.0.0", nil)
+	reg.Add("bar", "1.1.0", nil)
+	reg.Add("bar", "2.0.0", nil)
+
+	root := map[string]string{"foo": "^1.0.0", "bar": "^1.0.0"}
+	got, err := solve(t, reg, root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Solution{"foo": "1.0.0", "bar": "1.1.0"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSolve_ConflictResolution(t *testing.T) {
+	reg := Registry{}
+	reg.Add("foo", "1.0.0", nil)
+	reg.Add("foo", "2.0.0", map[string]string{"bar": "^1.0.0"})
+	reg.Add("bar", "1.0.0", map[string]string{"foo": "^1.0.0"})
+
+	got, err := solve(t, reg, map[string]string{"foo": ">=1.0.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Solution{"foo": "1.0.0"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSolve_PartialSatisfier(t *testing.T) {
+	reg := Registry{}
+	reg.Add("foo", "1.0.0", nil)
+	reg.Add("foo", "1.1.0", map[string]string{"left": "^1.0.0", "right": "^1.0.0"})
+	reg.Add("left", "1.0.0", map[string]string{"shared": ">=1.0.0"})
+	reg.Add("right", "1.0.0", map[string]string{"shared": "<2.0.0"})
+	reg.Add("shared", "1.0.0", map[string]string{"target": "^1.0.0"})
+	reg.Add("shared", "2.0.0", nil)
+	reg.Add("target", "1.0.0", nil)
+	reg.Add("target", "2.0.0", nil)
+
+	root := map[string]string{"foo": "^1.0.0", "target": "^2.0.0"}
+	got, err := solve(t, reg, root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Solution{"foo": "1.0.0", "target": "2.0.0"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	consistent(t, reg, root, got)
+}
+
+func TestSolve_Backjumping(t *testing.T) {
+	reg := Registry{}
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0", "1.3.0"} {
+		reg.Add("a", v, map[string]string{"c": "^" + v})
+	}
+	reg.Add("a", "1.4.0", map[string]string{"c": "^9.0.0"})
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0", "1.3.0"} {
+		reg.Add("c", v, map[string]string{"d": "<2.0.0"})
+	}
+	reg.Add("b", "1.0.0", map[string]string{"d": ">=1.0.0"})
+	reg.Add("d", "1.0.0", nil)
+	reg.Add("d", "2.0.0", nil)
+
+	root := map[string]string{"a": "^1.0.0", "b": "^1.0.0"}
+	got, err := solve(t, reg, root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["a"] != "1.3.0" || got["d"] != "1.0.0" {
+		t.Errorf("got %v", got)
+	}
+	consistent(t, reg, root, got)
+}
+
+func TestSolve_Failure(t *testing.T) {
+	reg := Registry{}
+	reg.Add("a", "1.0.0", map[string]string{"c": "^2.0.0"})
+	reg.Add("a", "1.1.0", map[string]string{"c": "^2.0.0"})
+	reg.Add("b", "3.0.0", map[string]string{"c": "<2.0.0"})
+	reg.Add("c", "1.5.0", nil)
+	reg.Add("c", "2.1.0", nil)
+
+	_, err := solve(t, reg, map[string]string{"a": "^1.0.0", "b": "3.0.0"})
+	var f *Failure
+	if !errors.As(err, &f) {
+		t.Fatalf("expected *Failure, got %v", err)
+	}
+	for _, fact := range []string{"a depends on c ^2.0.0", "b depends on c <2.0.0", "root depends on b 3.0.0"} {
+		if !strings.Contains(err.Error(), fact) {
+			t.Errorf("error %q does not mention %q", err, fact)
+		}
+	}
+}
+
+func TestSolve_MissingPackage(t *testing.T) {
+	reg := Registry{}
+	reg.Add("a", "1.0.0", map[string]string{"ghost": "^1.0.0"})
+	reg.Add("a", "2.0.0", map[string]string{"ghost": "^2.0.0"})
+
+	_, err := solve(t, reg, map[string]string{"a": "^1.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "a 1.0.0 depends on ghost ^1.0.0 (no matching versions)") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestSolve_Errors(t *testing.T) {
+	reg := Registry{}
+	reg.Add("a", "1.0.0", map[string]string{"b": "git+https://example.com/b.git"})
+	reg.Add("b", "1.0.0", nil)
+	if _, err := solve(t, reg, map[string]string{"a": "1.0.0"}); !errors.Is(err, vars.ErrUnsupportedSource) {
+		t.Errorf("expected ErrUnsupportedSource, got %v", err)
+	}
+	if _, err := reg.Dependencies("a", "9.9.9"); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("expected ErrUnknownVersion, got %v", err)
+	}
+}
+
+// ─── Registry ─────────────────────────────────────────────────────────────────
+
+func TestReadRegistry(t *testing.T) {
+	reg, err := ReadRegistry(strings.NewReader(`{
+  "app-utils": {"1.0.0": {"left-pad": "~1.1.0"}, "1.2.0": {"left-pad": "^1.3.0"}},
+  "left-pad": {"1.1.3": null, "1.3.0": {}}
+}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := Solve(vars.StyleNPM, reg, []Requirement{{Name: "app-utils", Constraint: "^1.0.0"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Solution{"app-utils": "1.2.0", "left-pad": "1.3.0"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := ReadRegistry(strings.NewReader("[")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
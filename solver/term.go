@@ -0,0 +1,284 @@
+package solver
+
+import (
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+/* ------------------------- */
+/*          vset             */
+/* ------------------------- */
+
+// vset is a set of indexes into a package's sorted version list. The index
+// one past the last version stands for "not selected", so that negative
+// terms are plain sets as well: "not foo ^1.0" is every version outside
+// ^1.0 plus not selecting foo at all.
+type vset []uint64
+
+func fullSet(n int) vset {
+	s := make(vset, (n+64)/64)
+	for i := 0; i <= n; i++ {
+		s.add(i)
+	}
+	return s
+}
+
+func emptySet(n int) vset { return make(vset, (n+64)/64) }
+
+func (s vset) add(i int)            { s[i/64] |= 1 << (i % 64) }
+func (s vset) has(i int) bool       { return s[i/64]&(1<<(i%64)) != 0 }
+func (s vset) clone() vset          { return append(vset(nil), s...) }
+func (s vset) equal(o vset) bool    { return s.subset(o) && o.subset(s) }
+func (s vset) disjoint(o vset) bool { return s.intersect(o).empty() }
+
+func (s vset) intersect(o vset) vset {
+	out := s.clone()
+	for i := range out {
+		out[i] &= o[i]
+	}
+	return out
+}
+
+func (s vset) union(o vset) vset {
+	out := s.clone()
+	for i := range out {
+		out[i] |= o[i]
+	}
+	return out
+}
+
+// complement is taken within a universe of n versions plus "not selected".
+func (s vset) complement(n int) vset {
+	out := fullSet(n)
+	for i := range out {
+		out[i] &^= s[i]
+	}
+	return out
+}
+
+func (s vset) subset(o vset) bool {
+	for i := range s {
+		if s[i]&^o[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s vset) empty() bool {
+	for _, w := range s {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s vset) count() int {
+	n := 0
+	for _, w := range s {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+/* ------------------------- */
+/*          terms            */
+/* ------------------------- */
+
+// universe holds the known versions of one package, sorted ascending.
+type universe struct {
+	name     string
+	versions []string
+}
+
+// absent is the index standing for "not selected".
+func (u *universe) absent() int { return len(u.versions) }
+
+// term states that a package's selection lies in set. A term whose set
+// excludes "not selected" is positive: the package must be selected.
+type term struct {
+	pkg *universe
+	set vset
+	// label is the constraint the term came from, kept for explanations.
+	// It describes the selected versions of set.
+	label string
+}
+
+func (t term) positive() bool { return !t.set.has(t.pkg.absent()) }
+
+// negate returns the term that holds exactly when t does not. A label
+// carries over: "not foo ^1.0" is described by the ^1.0 of its versions.
+func (t term) negate() term {
+	return term{pkg: t.pkg, set: t.set.complement(len(t.pkg.versions)), label: t.label}
+}
+
+func (t term) intersect(o term) term {
+	out := term{pkg: t.pkg, set: t.set.intersect(o.set)}
+	switch {
+	case out.set.equal(t.set):
+		out.label = t.label
+	case out.set.equal(o.set):
+		out.label = o.label
+	}
+	return out
+}
+
+// String describes the term, such as "foo ^1.0", "foo 1.2.0" or
+// "not foo >=2.0.0".
+func (t term) String() string {
+	if t.pkg.name == "" {
+		if t.positive() {
+			return "root"
+		}
+		return "not root"
+	}
+	if t.positive() {
+		return t.pkg.name + t.describe(t.set)
+	}
+	return "not " + t.pkg.name + t.describe(t.set.complement(len(t.pkg.versions)))
+}
+
+// describe writes the selected versions of s, preferring the label.
+func (t term) describe(s vset) string {
+	if t.label != "" {
+		return " " + t.label
+	}
+	var idx []int
+	for i := range t.pkg.versions {
+		if s.has(i) {
+			idx = append(idx, i)
+		}
+	}
+	n := len(t.pkg.versions)
+	switch {
+	case len(idx) == 0:
+		return " (no versions)"
+	case len(idx) == n:
+		return ""
+	case len(idx) == 1:
+		return " " + t.pkg.versions[idx[0]]
+	}
+
+	// Contiguous runs of the version list become ranges.
+	var parts []string
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && idx[j+1] == idx[j]+1 {
+			j++
+		}
+		lo, hi := t.pkg.versions[idx[i]], t.pkg.versions[idx[j]]
+		switch {
+		case i == j:
+			parts = append(parts, lo)
+		case idx[i] == 0:
+			parts = append(parts, "<="+hi)
+		case idx[j] == n-1:
+			parts = append(parts, ">="+lo)
+		default:
+			parts = append(parts, ">="+lo+" <="+hi)
+		}
+		i = j + 1
+	}
+	return " " + strings.Join(parts, " || ")
+}
+
+/* ------------------------- */
+/*     incompatibilities     */
+/* ------------------------- */
+
+// Cause tells why an incompatibility holds.
+type Cause string
+
+const (
+	// CauseRoot is the requirement that the root package be selected.
+	CauseRoot Cause = "root"
+	// CauseDependency is a package version depending on a range of
+	// another package.
+	CauseDependency Cause = "dependency"
+	// CauseNoVersions is a range that matches no known version.
+	CauseNoVersions Cause = "no versions"
+	// CauseConflict is derived from two other incompatibilities.
+	CauseConflict Cause = "conflict"
+)
+
+// incompatibility is a set of terms that cannot all hold at once. Terms
+// are keyed by package and sorted by name.
+type incompatibility struct {
+	terms []term
+	cause Cause
+	// left and right are the incompatibilities a conflict was derived from.
+	left, right *incompatibility
+}
+
+func newIncompatibility(cause Cause, terms ...term) *incompatibility {
+	byPkg := map[string]term{}
+	for _, t := range terms {
+		if prev, ok := byPkg[t.pkg.name]; ok {
+			t = prev.intersect(t)
+		}
+		byPkg[t.pkg.name] = t
+	}
+	inc := &incompatibility{cause: cause}
+	for _, t := range byPkg {
+		// A term that always holds adds nothing. Dependencies keep theirs,
+		// so that a dependency on a range with no versions can be told.
+		if cause != CauseDependency && t.set.equal(fullSet(len(t.pkg.versions))) {
+			continue
+		}
+		inc.terms = append(inc.terms, t)
+	}
+	sort.Slice(inc.terms, func(i, j int) bool { return inc.terms[i].pkg.name < inc.terms[j].pkg.name })
+	return inc
+}
+
+// String states the incompatibility as a fact, such as
+// "a 1.0.0 depends on c ^2.0" or "b 3.0.0 is incompatible with c >=2.0.0".
+func (inc *incompatibility) String() string {
+	if inc.cause == CauseDependency && len(inc.terms) == 2 {
+		dep, on := inc.terms[0], inc.terms[1]
+		if !dep.positive() {
+			dep, on = on, dep
+		}
+		if dep.positive() && !on.positive() {
+			if on.set.equal(fullSet(len(on.pkg.versions))) {
+				return dep.String() + " depends on " + on.negate().String() + " (no matching versions)"
+			}
+			return dep.String() + " depends on " + on.negate().String()
+		}
+	}
+	if inc.cause == CauseNoVersions && len(inc.terms) == 1 {
+		return "no versions of " + inc.terms[0].pkg.name + " match" + inc.terms[0].describe(inc.terms[0].set)
+	}
+
+	// The root is always selected, so it goes without saying.
+	var pos, neg []term
+	for _, t := range inc.terms {
+		if t.pkg.name == "" && t.positive() && len(inc.terms) > 1 {
+			continue
+		}
+		if t.positive() {
+			pos = append(pos, t)
+		} else {
+			neg = append(neg, t)
+		}
+	}
+	switch {
+	case len(pos)+len(neg) == 0:
+		return "version solving failed"
+	case len(pos) == 1 && len(neg) == 0:
+		return pos[0].String() + " is forbidden"
+	case len(neg) == 1 && len(pos) == 0:
+		return neg[0].negate().String() + " is required"
+	case len(pos) == 2 && len(neg) == 0:
+		return pos[0].String() + " is incompatible with " + pos[1].String()
+	case len(pos) == 1 && len(neg) == 1:
+		return pos[0].String() + " requires " + neg[0].negate().String()
+	}
+	var parts []string
+	for _, t := range append(pos, neg...) {
+		parts = append(parts, t.String())
+	}
+	return "one of " + strings.Join(parts, ", ") + " must be false"
+}
@@ -0,0 +1,76 @@
+package vars
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation records how a version was checked against a constraint.
+type Explanation struct {
+	Style      Style              `json:"style"`
+	Constraint string             `json:"constraint"`
+	Version    string             `json:"version"`
+	Satisfied  bool               `json:"satisfied"`
+	Groups     []GroupExplanation `json:"groups"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// GroupExplanation covers one OR group. Groups after the first satisfied one
+// are never tried.
+type GroupExplanation struct {
+	Normalized string             `json:"normalized"`
+	Tried      bool               `json:"tried"`
+	Satisfied  bool               `json:"satisfied"`
+	Checks     []CheckExplanation `json:"checks"`
+}
+
+// CheckExplanation covers one comparator of an AND group. Component names the
+// part of the version that decided the comparison: epoch, major, minor,
+// patch, revision, prerelease, equal (no difference) or literal.
+type CheckExplanation struct {
+	Constraint Constraint `json:"constraint"`
+	Satisfied  bool       `json:"satisfied"`
+	Component  string     `json:"component"`
+}
+
+// String renders the explanation as indented human-readable text.
+func (e Explanation) String() string {
+	var b strings.Builder
+	verdict := "does not satisfy"
+	if e.Satisfied {
+		verdict = "satisfies"
+	}
+	fmt.Fprintf(&b, "%s %s %s", e.Version, verdict, e.Constraint)
+	if e.Style != "" {
+		fmt.Fprintf(&b, " (%s)", e.Style)
+	}
+	b.WriteString("\n")
+	if e.Error != "" {
+		fmt.Fprintf(&b, "  error: %s\n", e.Error)
+		return b.String()
+	}
+	if len(e.Groups) == 0 {
+		b.WriteString("  constraint parsed to no groups\n")
+	}
+	for i, g := range e.Groups {
+		status := "not tried"
+		if g.Tried {
+			status = "failed"
+			if g.Satisfied {
+				status = "passed"
+			}
+		}
+		fmt.Fprintf(&b, "  group %d: %s -> %s\n", i+1, g.Normalized, status)
+		if !g.Tried {
+			continue
+		}
+		for _, c := range g.Checks {
+			res := "fail"
+			if c.Satisfied {
+				res = "pass"
+			}
+			fmt.Fprintf(&b, "    %s%s: %s (decided by %s)\n", c.Constraint.Op, c.Constraint.Ver, res, c.Component)
+		}
+	}
+	return b.String()
+}
@@ -1,6 +1,9 @@
 package vars
 
-import "regexp"
+import (
+	"regexp"
+	"time"
+)
 
 type Constraint struct {
 	Op  string
@@ -16,15 +19,57 @@ type Analysis struct {
 type Style string
 
 const (
-	StyleNPM   Style = "npm"
-	StylePy    Style = "python"
-	StyleNuGet Style = "nuget"
-	StyleMaven Style = "maven"
-	StyleRuby  Style = "ruby"
-	StyleRust  Style = "rust"
-	StyleGo    Style = "go"
+	StyleNPM      Style = "npm"
+	StylePy       Style = "python"
+	StyleNuGet    Style = "nuget"
+	StyleMaven    Style = "maven"
+	StyleGradle   Style = "gradle"
+	StyleRuby     Style = "ruby"
+	StyleRust     Style = "rust"
+	StyleGo       Style = "go"
+	StyleComposer Style = "composer"
+	StyleDebian   Style = "debian"
+	StyleRPM      Style = "rpm"
+	StyleAlpine   Style = "alpine"
+	StyleConda    Style = "conda"
+)
+
+// Bump classifies the gap between two versions by the most significant
+// component that differs.
+type Bump string
+
+const (
+	BumpNone       Bump = "none"
+	BumpMajor      Bump = "major"
+	BumpMinor      Bump = "minor"
+	BumpPatch      Bump = "patch"
+	BumpPrerelease Bump = "prerelease"
 )
 
+// Outdated is an npm-outdated style report for one dependency.
+type Outdated struct {
+	Constraint string
+	Current    string // installed version, may be empty
+	Wanted     string // highest version satisfying Constraint
+	Latest     string // highest stable version overall
+	Bump       Bump   // gap between Current and Latest
+	Breaking   bool   // Current -> Latest crosses a breaking-change boundary
+}
+
+// Candidate is a published version together with what the registry knows
+// about it.
+type Candidate struct {
+	Version   string    `json:"version"`
+	Published time.Time `json:"published,omitzero"`
+	// Yanked marks a version withdrawn by its publisher: still resolvable
+	// for existing locks, but not to be picked for new ones.
+	Yanked bool `json:"yanked,omitempty"`
+	// Deprecated holds the registry's deprecation message, if any.
+	Deprecated string `json:"deprecated,omitempty"`
+	// Tags lists the dist-tags pointing at the version, such as "latest".
+	Tags []string `json:"tags,omitempty"`
+}
+
 type ConstraintResult struct {
 	Raw     string
 	Parsed  string